@@ -23,12 +23,19 @@ import (
 
 	"github.com/lithammer/dedent"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
 	cliflag "k8s.io/component-base/cli/flag"
 	"k8s.io/klog/v2"
 
 	"github.com/k-cloud-labs/kluster-capacity/app/cmds/capacityestimation/options"
 	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/admission"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/framework"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/history"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/output"
 	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/capacityestimation"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/usage"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
 )
 
 var capacityEstimationLong = dedent.Dedent(`
@@ -88,6 +95,30 @@ func validate(opt *options.CapacityEstimationOptions) error {
 		return errors.New("schedulerconfig is missing")
 	}
 
+	if opt.BatchSize < 1 {
+		return errors.New("batch size must be greater than 0")
+	}
+
+	if opt.Parallelism < 1 {
+		return errors.New("parallelism must be greater than 0")
+	}
+
+	if opt.BatchSize > 1 && opt.EnablePreemption {
+		return errors.New("batch size and enable preemption is exclusive")
+	}
+
+	if err := usage.Validate(&opt.Options); err != nil {
+		return err
+	}
+
+	if err := output.Validate(opt.OutputVersion); err != nil {
+		return err
+	}
+
+	if err := admission.Validate(&opt.Options); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -100,15 +131,26 @@ func run(opt *options.CapacityEstimationOptions) error {
 		return fmt.Errorf("failed to parse pod spec file: %v ", err)
 	}
 
-	reports, err := runSimulator(conf)
+	var reports pkg.Printer
+	if len(opt.UsageFile) > 0 {
+		reports, err = runUsageSimulator(conf)
+	} else {
+		reports, err = runSimulator(conf)
+	}
 	if err != nil {
 		return err
 	}
 
-	if err := reports.Print(conf.Options.Verbose, conf.Options.OutputFormat); err != nil {
+	if err := output.Print(reports, conf.Options.Verbose, conf.Options.OutputFormat, conf.Options.OutputVersion); err != nil {
 		return fmt.Errorf("error while printing: %v", err)
 	}
 
+	if len(opt.History) > 0 {
+		if err := history.Append(opt.History, history.CommandCapacityEstimation, usage.Requested(reports)); err != nil {
+			return fmt.Errorf("failed to append the result to the history: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -130,3 +172,26 @@ func runSimulator(conf *options.CapacityEstimationConfig) (pkg.Printer, error) {
 
 	return s.Report(), nil
 }
+
+// runUsageSimulator runs the simulation on the world as it's requested and on the world right-sized to the usage
+func runUsageSimulator(conf *options.CapacityEstimationConfig) (pkg.Printer, error) {
+	kubeConfig, err := utils.BuildRestConfig(conf.Options.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	objs, err := framework.GetInitObjectsFromCluster(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return usage.Compare(&conf.Options.Options, objs, func(objs []runtime.Object) (pkg.Printer, error) {
+		c := *conf
+		c.InitObjs = objs
+		// the pods are changed by the simulation
+		c.Pods = nil
+		for _, pod := range conf.Pods {
+			c.Pods = append(c.Pods, pod.DeepCopy())
+		}
+		return runSimulator(&c)
+	})
+}
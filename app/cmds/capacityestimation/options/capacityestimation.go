@@ -2,18 +2,11 @@ package options
 
 import (
 	"context"
-	"fmt"
-	"io"
-	"net/http"
-	"os"
-	"path/filepath"
-	"strings"
 
 	"github.com/spf13/pflag"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/util/yaml"
 	clientset "k8s.io/client-go/kubernetes"
 
 	"github.com/k-cloud-labs/kluster-capacity/app/cmds"
@@ -24,6 +17,12 @@ type CapacityEstimationOptions struct {
 	cmds.Options
 	PodsFromTemplate []string
 	PodsFromCluster  NamespaceNames
+	// allow the simulated pods to preempt pods with lower priority
+	EnablePreemption bool
+	// number of simulated pods kept pending in the scheduling queue at a time
+	BatchSize int
+	// number of pod templates simulated in parallel
+	Parallelism int
 }
 
 type CapacityEstimationConfig struct {
@@ -39,7 +38,15 @@ func NewCapacityEstimationConfig(opt *CapacityEstimationOptions) *CapacityEstima
 }
 
 func NewCapacityEstimationOptions() *CapacityEstimationOptions {
-	return &CapacityEstimationOptions{}
+	return &CapacityEstimationOptions{
+		Options: cmds.Options{
+			UsagePercentile: cmds.DefaultUsagePercentile,
+			UsageMargin:     cmds.DefaultUsageMargin,
+			OutputVersion:   cmds.DefaultOutputVersion,
+		},
+		BatchSize:   1,
+		Parallelism: 4,
+	}
 }
 
 func (s *CapacityEstimationOptions) AddFlags(fs *pflag.FlagSet) {
@@ -49,48 +56,26 @@ func (s *CapacityEstimationOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&s.MaxLimit, "max-limit", 0, "Number of instances of pod to be scheduled after which analysis stops. By default unlimited")
 	fs.StringVar(&s.SchedulerConfig, "schedulerconfig", s.SchedulerConfig, "Path to JSON or YAML file containing scheduler configuration")
 	fs.BoolVar(&s.Verbose, "verbose", s.Verbose, "Verbose mode")
-	fs.StringVarP(&s.OutputFormat, "output", "o", s.OutputFormat, "Output format. One of: json|yaml (Note: output is not versioned or guaranteed to be stable across releases)")
+	fs.StringVarP(&s.OutputFormat, "output", "o", s.OutputFormat, "Output format. One of: json|yaml|html")
+	fs.StringVar(&s.OutputVersion, "output-version", s.OutputVersion, "Version of the output api printed with json or yaml. One of: v1|v1alpha1. By default v1alpha1, the unversioned shape printed before the output was versioned")
 	fs.StringSliceVar(&s.ExcludeNodes, "exclude-nodes", s.ExcludeNodes, "Exclude nodes to be scheduled")
+	fs.StringSliceVar(&s.EnableAdmissionPlugins, "enable-admission-plugins", s.EnableAdmissionPlugins, "Built-in admission plugins called for the pods created besides the default ones. Any of: LimitRanger|ServiceAccount|Priority|PodTolerationRestriction|RuntimeClass. By default LimitRanger, ServiceAccount, Priority and RuntimeClass are called")
+	fs.StringSliceVar(&s.DisableAdmissionPlugins, "disable-admission-plugins", s.DisableAdmissionPlugins, "Built-in admission plugins not called for the pods created. Any of: LimitRanger|ServiceAccount|Priority|PodTolerationRestriction|RuntimeClass")
+	fs.StringVar(&s.AdmissionWebhookConfig, "admission-webhook-config", s.AdmissionWebhookConfig, "Path to a JSON or YAML file of the MutatingWebhookConfigurations called for the pods created, the webhooks must have a url")
+	fs.BoolVar(&s.EnablePreemption, "enable-preemption", s.EnablePreemption, "Whether the pod template can preempt pods with lower priority, the pod priority is taken from spec.priority. By default false")
+	fs.IntVar(&s.BatchSize, "batch-size", s.BatchSize, "Number of simulated pods kept pending in the scheduling queue at a time, a larger value speeds up the estimation on large clusters. Exclusive with --enable-preemption. By default 1")
+	fs.IntVar(&s.Parallelism, "parallelism", s.Parallelism, "Number of pod templates simulated in parallel, all the simulations share the same snapshot of the cluster. By default 4")
+	fs.StringVar(&s.Trace, "trace", s.Trace, "File path to write the scheduling trace of each pod as NDJSON, including the filter and score plugin results of each node")
+	fs.StringVar(&s.History, "history", s.History, "Directory of the history store to append the result to, the trends of the results are shown by the history command")
+	fs.StringVar(&s.UsageFile, "usage-file", s.UsageFile, "Path to a CSV or JSON file of the usage percentiles of the pods, e.g. exported from Prometheus. The simulation runs on the world as it's requested and on the world with the requests of the pods right-sized to the usage, and the capacity gained is reported")
+	fs.IntVar(&s.UsagePercentile, "usage-percentile", s.UsagePercentile, "Percentile of the usage the requests are right-sized to, the usage file must record it. By default 95")
+	fs.Float64Var(&s.UsageMargin, "usage-margin", s.UsageMargin, "Margin added to the usage when right-sizing the requests, e.g. 0.1 for 10%. By default 0.1")
 }
 
 func (s *CapacityEstimationConfig) ParseAPISpec() error {
-	getPodFromTemplate := func(template string) (*corev1.Pod, error) {
-		var (
-			err          error
-			versionedPod = &corev1.Pod{}
-			spec         io.Reader
-		)
-
-		if strings.HasPrefix(template, "http://") || strings.HasPrefix(template, "https://") {
-			response, err := http.Get(template)
-			if err != nil {
-				return nil, err
-			}
-			defer response.Body.Close()
-			if response.StatusCode != http.StatusOK {
-				return nil, fmt.Errorf("unable to read URL %q, server reported %v, status code=%v", template, response.Status, response.StatusCode)
-			}
-			spec = response.Body
-		} else {
-			filename, _ := filepath.Abs(template)
-			spec, err = os.Open(filename)
-			if err != nil {
-				return nil, fmt.Errorf("failed to open config file: %v", err)
-			}
-		}
-
-		decoder := yaml.NewYAMLOrJSONDecoder(spec, 4096)
-		err = decoder.Decode(versionedPod)
-		if err != nil {
-			return nil, fmt.Errorf("failed to decode config file: %v", err)
-		}
-
-		return versionedPod, nil
-	}
-
 	if len(s.Options.PodsFromTemplate) != 0 {
 		for _, template := range s.Options.PodsFromTemplate {
-			pod, err := getPodFromTemplate(template)
+			pod, err := utils.GetPodFromTemplate(template)
 			if err != nil {
 				return err
 			}
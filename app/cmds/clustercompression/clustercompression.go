@@ -23,12 +23,19 @@ import (
 
 	"github.com/lithammer/dedent"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
 	cliflag "k8s.io/component-base/cli/flag"
 	"k8s.io/klog/v2"
 
 	"github.com/k-cloud-labs/kluster-capacity/app/cmds/clustercompression/options"
 	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/admission"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/framework"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/history"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/output"
 	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/clustercompression"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/usage"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
 )
 
 var clusterCompressionLong = dedent.Dedent(`
@@ -80,6 +87,18 @@ func validateOptions(opt *options.ClusterCompressionOptions) error {
 		return errors.New("schedulerconfig is missing")
 	}
 
+	if err := usage.Validate(&opt.Options); err != nil {
+		return err
+	}
+
+	if err := output.Validate(opt.OutputVersion); err != nil {
+		return err
+	}
+
+	if err := admission.Validate(&opt.Options); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -87,15 +106,29 @@ func run(opt *options.ClusterCompressionOptions) error {
 	defer klog.Flush()
 	conf := options.NewClusterCompressionConfig(opt)
 
-	reports, err := runCCSimulator(conf)
+	var (
+		reports pkg.Printer
+		err     error
+	)
+	if len(opt.UsageFile) > 0 {
+		reports, err = runUsageSimulator(conf)
+	} else {
+		reports, err = runCCSimulator(conf)
+	}
 	if err != nil {
 		klog.Errorf("runCCSimulator err: %s\n", err.Error())
 		return err
 	}
 
-	if err := reports.Print(conf.Options.Verbose, conf.Options.OutputFormat); err != nil {
+	if err := output.Print(reports, conf.Options.Verbose, conf.Options.OutputFormat, conf.Options.OutputVersion); err != nil {
 		return fmt.Errorf("error while printing: %v\n", err)
 	}
+
+	if len(opt.History) > 0 {
+		if err := history.Append(opt.History, history.CommandClusterCompression, usage.Requested(reports)); err != nil {
+			return fmt.Errorf("failed to append the result to the history: %v", err)
+		}
+	}
 	return nil
 }
 
@@ -105,7 +138,7 @@ func runCCSimulator(conf *options.ClusterCompressionConfig) (pkg.Printer, error)
 		return nil, err
 	}
 
-	err = s.Initialize()
+	err = s.Initialize(conf.InitObjs...)
 	if err != nil {
 		return nil, err
 	}
@@ -117,3 +150,21 @@ func runCCSimulator(conf *options.ClusterCompressionConfig) (pkg.Printer, error)
 
 	return s.Report(), nil
 }
+
+// runUsageSimulator runs the simulation on the world as it's requested and on the world right-sized to the usage
+func runUsageSimulator(conf *options.ClusterCompressionConfig) (pkg.Printer, error) {
+	kubeConfig, err := utils.BuildRestConfig(conf.Options.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	objs, err := framework.GetInitObjectsFromCluster(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return usage.Compare(&conf.Options.Options, objs, func(objs []runtime.Object) (pkg.Printer, error) {
+		c := *conf
+		c.InitObjs = objs
+		return runCCSimulator(&c)
+	})
+}
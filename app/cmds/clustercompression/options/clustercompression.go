@@ -2,6 +2,7 @@ package options
 
 import (
 	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/k-cloud-labs/kluster-capacity/app/cmds"
 )
@@ -21,7 +22,8 @@ type FilterNodeOptions struct {
 }
 
 type ClusterCompressionConfig struct {
-	Options *ClusterCompressionOptions
+	Options  *ClusterCompressionOptions
+	InitObjs []runtime.Object
 }
 
 func NewClusterCompressionConfig(opt *ClusterCompressionOptions) *ClusterCompressionConfig {
@@ -31,12 +33,19 @@ func NewClusterCompressionConfig(opt *ClusterCompressionOptions) *ClusterCompres
 }
 
 func NewClusterCompressionOptions() *ClusterCompressionOptions {
-	return &ClusterCompressionOptions{}
+	return &ClusterCompressionOptions{
+		Options: cmds.Options{
+			UsagePercentile: cmds.DefaultUsagePercentile,
+			UsageMargin:     cmds.DefaultUsageMargin,
+			OutputVersion:   cmds.DefaultOutputVersion,
+		},
+	}
 }
 
 func (s *ClusterCompressionOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&s.KubeConfig, "kubeconfig", s.KubeConfig, "Path to the kubeconfig file to use for the analysis.")
-	fs.StringVarP(&s.OutputFormat, "output", "o", s.OutputFormat, "Output format. One of: json|default (Note: output is not versioned or guaranteed to be stable across releases)")
+	fs.StringVarP(&s.OutputFormat, "output", "o", s.OutputFormat, "Output format. One of: json|yaml|html|default")
+	fs.StringVar(&s.OutputVersion, "output-version", s.OutputVersion, "Version of the output api printed with json or yaml. One of: v1|v1alpha1. By default v1alpha1, the unversioned shape printed before the output was versioned")
 	fs.StringVar(&s.SchedulerConfig, "schedulerconfig", s.SchedulerConfig, "Path to JSON or YAML file containing scheduler configuration.")
 	fs.IntVar(&s.MaxLimit, "max-limit", 0, "Number of instances of node to be scale down after which analysis stops.. By default unlimited.")
 	fs.BoolVar(&s.FilterNodeOptions.ExcludeTaintNode, "exclude-taint-node", true, "Whether to filter nodes with taint when selecting nodes. By default true.")
@@ -46,5 +55,13 @@ func (s *ClusterCompressionOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&s.FilterNodeOptions.IgnoreCloneSet, "ignore-cloneset", false, "Whether to ignore nodes with cloneSet pods when filtering nodes. By default false.")
 	fs.BoolVar(&s.FilterNodeOptions.IgnoreVolumePod, "ignore-volume-pod", false, "Whether to ignore nodes with volume pods when filtering nodes. By default false.")
 	fs.StringSliceVar(&s.ExcludeNodes, "exclude-nodes", s.ExcludeNodes, "Exclude nodes to be scheduled")
+	fs.StringSliceVar(&s.EnableAdmissionPlugins, "enable-admission-plugins", s.EnableAdmissionPlugins, "Built-in admission plugins called for the pods created besides the default ones. Any of: LimitRanger|ServiceAccount|Priority|PodTolerationRestriction|RuntimeClass. By default LimitRanger, ServiceAccount, Priority and RuntimeClass are called")
+	fs.StringSliceVar(&s.DisableAdmissionPlugins, "disable-admission-plugins", s.DisableAdmissionPlugins, "Built-in admission plugins not called for the pods created. Any of: LimitRanger|ServiceAccount|Priority|PodTolerationRestriction|RuntimeClass")
+	fs.StringVar(&s.AdmissionWebhookConfig, "admission-webhook-config", s.AdmissionWebhookConfig, "Path to a JSON or YAML file of the MutatingWebhookConfigurations called for the pods created, the webhooks must have a url")
 	fs.BoolVar(&s.Verbose, "verbose", s.Verbose, "Verbose mode")
+	fs.StringVar(&s.Trace, "trace", s.Trace, "File path to write the scheduling trace of each pod as NDJSON, including the filter and score plugin results of each node.")
+	fs.StringVar(&s.History, "history", s.History, "Directory of the history store to append the result to, the trends of the results are shown by the history command.")
+	fs.StringVar(&s.UsageFile, "usage-file", s.UsageFile, "Path to a CSV or JSON file of the usage percentiles of the pods, e.g. exported from Prometheus. The simulation runs on the world as it's requested and on the world with the requests of the pods right-sized to the usage, and the capacity gained is reported.")
+	fs.IntVar(&s.UsagePercentile, "usage-percentile", s.UsagePercentile, "Percentile of the usage the requests are right-sized to, the usage file must record it. By default 95.")
+	fs.Float64Var(&s.UsageMargin, "usage-margin", s.UsageMargin, "Margin added to the usage when right-sizing the requests, e.g. 0.1 for 10%. By default 0.1.")
 }
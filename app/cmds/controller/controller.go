@@ -0,0 +1,124 @@
+/*
+Copyright © 2023 k-cloud-labs org
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/dynamic"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/klog/v2"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/controller/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/controller"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/framework"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+var controllerLong = dedent.Dedent(`
+		controller reconciles the CapacityEstimationReview, ClusterCompressionReview and SchedulerSimulationReview
+		objects of the Kubernetes environment specified in KUBECONFIG. Each review is evaluated by running the
+		simulation in a fresh world initialized from the objects cached by informers, the results are written to
+		its status, and it's evaluated again when its spec is changed or the resync period is passed.
+	`)
+
+func NewControllerCmd() *cobra.Command {
+	opt := options.NewControllerOptions()
+
+	var cmd = &cobra.Command{
+		Use:           "controller --kubeconfig KUBECONFIG",
+		Short:         "controller reconciles the reviews submitted as custom resources",
+		Long:          controllerLong,
+		SilenceErrors: false,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flag.Parse()
+
+			err := validate(opt)
+			if err != nil {
+				return err
+			}
+
+			err = run(opt)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.SetNormalizeFunc(cliflag.WordSepNormalizeFunc)
+	flags.AddGoFlagSet(flag.CommandLine)
+	opt.AddFlags(flags)
+
+	return cmd
+}
+
+func validate(opt *options.ControllerOptions) error {
+	if opt.Concurrency < 1 {
+		return errors.New("concurrency must be greater than 0")
+	}
+
+	if opt.ResyncPeriod <= 0 {
+		return errors.New("resync period must be greater than 0")
+	}
+
+	// make sure the scheduler configuration is valid before reconciling
+	if _, err := utils.LoadKubeSchedulerConfiguration(opt.SchedulerConfig); err != nil {
+		return fmt.Errorf("invalid schedulerconfig: %v", err)
+	}
+
+	return nil
+}
+
+func run(opt *options.ControllerOptions) error {
+	defer klog.Flush()
+	conf := options.NewControllerConfig(opt)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	kubeConfig, err := utils.BuildRestConfig(opt.KubeConfig)
+	if err != nil {
+		return err
+	}
+	cache, err := framework.NewClusterCache(kubeConfig)
+	if err != nil {
+		return err
+	}
+	client, err := dynamic.NewForConfig(kubeConfig)
+	if err != nil {
+		return err
+	}
+	c := controller.NewController(conf, client, cache)
+
+	klog.InfoS("Waiting for the cache of the cluster to be synced")
+	if err := cache.Start(ctx.Done()); err != nil {
+		return err
+	}
+
+	return c.Run(ctx)
+}
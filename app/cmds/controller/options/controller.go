@@ -0,0 +1,41 @@
+package options
+
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds"
+)
+
+type ControllerOptions struct {
+	cmds.Options
+	// number of reviews evaluated at the same time
+	Concurrency int
+	// how often a review is evaluated again if its spec doesn't specify
+	ResyncPeriod time.Duration
+}
+
+type ControllerConfig struct {
+	Options *ControllerOptions
+}
+
+func NewControllerOptions() *ControllerOptions {
+	return &ControllerOptions{
+		Concurrency:  2,
+		ResyncPeriod: 10 * time.Minute,
+	}
+}
+
+func NewControllerConfig(opt *ControllerOptions) *ControllerConfig {
+	return &ControllerConfig{
+		Options: opt,
+	}
+}
+
+func (s *ControllerOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.KubeConfig, "kubeconfig", s.KubeConfig, "Path to the kubeconfig file of the cluster whose reviews are reconciled. By default the in-cluster config")
+	fs.StringVar(&s.SchedulerConfig, "schedulerconfig", s.SchedulerConfig, "Path to JSON or YAML file containing scheduler configuration used by all the simulations. By default the default scheduler configuration")
+	fs.IntVar(&s.Concurrency, "concurrency", s.Concurrency, "Number of reviews evaluated at the same time. By default 2")
+	fs.DurationVar(&s.ResyncPeriod, "resync-period", s.ResyncPeriod, "How often a review is evaluated again if its spec doesn't specify the resync period. By default 10m")
+}
@@ -0,0 +1,97 @@
+/*
+Copyright © 2023 k-cloud-labs org
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package convert
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	cliflag "k8s.io/component-base/cli/flag"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/convert/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/output"
+)
+
+var convertLong = dedent.Dedent(`
+		convert reads a result of ce, cc or ss saved with -o json or yaml in any version of the output api, or
+		recorded in the history store with --history, and prints it in the version specified by --output-version.
+		It's meant for upgrading saved results, and for the tools which still read an older version.
+	`)
+
+func NewConvertCmd() *cobra.Command {
+	opt := options.NewConvertOptions()
+
+	var cmd = &cobra.Command{
+		Use:           "convert FILE",
+		Short:         "convert converts a saved result of ce, cc or ss to a version of the output api",
+		Long:          convertLong,
+		Args:          cobra.ExactArgs(1),
+		SilenceErrors: false,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flag.Parse()
+
+			opt.Input = args[0]
+			err := validate(opt)
+			if err != nil {
+				return err
+			}
+
+			err = run(opt)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.SetNormalizeFunc(cliflag.WordSepNormalizeFunc)
+	flags.AddGoFlagSet(flag.CommandLine)
+	opt.AddFlags(flags)
+
+	return cmd
+}
+
+func validate(opt *options.ConvertOptions) error {
+	switch opt.OutputFormat {
+	case "json", "yaml":
+	default:
+		return fmt.Errorf("output format %q not recognized", opt.OutputFormat)
+	}
+
+	return output.Validate(opt.OutputVersion)
+}
+
+func run(opt *options.ConvertOptions) error {
+	hub, err := output.Load(opt.Input)
+	if err != nil {
+		return err
+	}
+
+	obj, err := output.Convert(hub, opt.OutputVersion)
+	if err != nil {
+		return err
+	}
+
+	if err := output.PrintObject(obj, opt.OutputFormat); err != nil {
+		return fmt.Errorf("error while printing: %v", err)
+	}
+
+	return nil
+}
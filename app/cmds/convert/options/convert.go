@@ -0,0 +1,26 @@
+package options
+
+import (
+	"github.com/spf13/pflag"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds"
+)
+
+type ConvertOptions struct {
+	// file of the result to convert, set from the argument
+	Input         string
+	OutputFormat  string
+	OutputVersion string
+}
+
+func NewConvertOptions() *ConvertOptions {
+	return &ConvertOptions{
+		OutputFormat:  "json",
+		OutputVersion: cmds.LatestOutputVersion,
+	}
+}
+
+func (s *ConvertOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&s.OutputFormat, "output", "o", s.OutputFormat, "Output format. One of: json|yaml. By default json")
+	fs.StringVar(&s.OutputVersion, "output-version", s.OutputVersion, "Version of the output api the result is converted to. One of: v1|v1alpha1. By default v1")
+}
@@ -0,0 +1,106 @@
+/*
+Copyright © 2023 k-cloud-labs org
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	cliflag "k8s.io/component-base/cli/flag"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/diff/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/diff"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/output"
+)
+
+var diffLong = dedent.Dedent(`
+		diff compares two results of the same command saved from ce, cc or ss with -o json or yaml in any
+		version of the output api, or recorded in the history store with --history, and prints only what
+		changed: the replicas of each template and on each node, the nodes newly removable or blocked, and
+		the pods newly unschedulable. It's meant for the same checks run periodically where only the
+		changes matter.
+	`)
+
+func NewDiffCmd() *cobra.Command {
+	opt := options.NewDiffOptions()
+
+	var cmd = &cobra.Command{
+		Use:           "diff OLD NEW",
+		Short:         "diff compares two saved results of ce, cc or ss and prints what changed",
+		Long:          diffLong,
+		Args:          cobra.ExactArgs(2),
+		SilenceErrors: false,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flag.Parse()
+
+			opt.Old, opt.New = args[0], args[1]
+			err := validate(opt)
+			if err != nil {
+				return err
+			}
+
+			err = run(opt)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.SetNormalizeFunc(cliflag.WordSepNormalizeFunc)
+	flags.AddGoFlagSet(flag.CommandLine)
+	opt.AddFlags(flags)
+
+	return cmd
+}
+
+func validate(opt *options.DiffOptions) error {
+	switch opt.OutputFormat {
+	case "", "json", "yaml":
+	default:
+		return fmt.Errorf("output format %q not recognized", opt.OutputFormat)
+	}
+
+	return nil
+}
+
+func run(opt *options.DiffOptions) error {
+	oldReview, err := output.Load(opt.Old)
+	if err != nil {
+		return err
+	}
+	newReview, err := output.Load(opt.New)
+	if err != nil {
+		return err
+	}
+
+	review, err := diff.Diff(oldReview, newReview)
+	if err != nil {
+		return err
+	}
+	review.Old, review.New = opt.Old, opt.New
+
+	if err := review.Print(opt.Verbose, opt.OutputFormat); err != nil {
+		return fmt.Errorf("error while printing: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+package options
+
+import (
+	"github.com/spf13/pflag"
+)
+
+type DiffOptions struct {
+	// files of the results to compare, set from the arguments
+	Old          string
+	New          string
+	Verbose      bool
+	OutputFormat string
+}
+
+func NewDiffOptions() *DiffOptions {
+	return &DiffOptions{}
+}
+
+func (s *DiffOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&s.OutputFormat, "output", "o", s.OutputFormat, "Output format. One of: json|yaml")
+	fs.BoolVar(&s.Verbose, "verbose", s.Verbose, "Verbose mode")
+}
@@ -0,0 +1,137 @@
+/*
+Copyright © 2023 k-cloud-labs org
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package explain
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/klog/v2"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/explain/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/admission"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/explain"
+)
+
+var explainLong = dedent.Dedent(`
+		explain simulates an API server with initial state copied from the Kubernetes environment
+		with its configuration specified in KUBECONFIG. The simulated API server schedules the pod once,
+		and shows the verdict of each PreFilter, Filter and Score plugin on each node, so that it's clear why
+		the pod can or can't run on a node.
+	`)
+
+func NewExplainCmd() *cobra.Command {
+	opt := options.NewExplainOptions()
+
+	var cmd = &cobra.Command{
+		Use:           "explain --kubeconfig KUBECONFIG --pod-from-template PODYAML | --pod-from-cluster Namespace/Name [--node NODE]",
+		Short:         "explain is used to show why a pod can or can't run on the nodes",
+		Long:          explainLong,
+		SilenceErrors: false,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flag.Parse()
+
+			err := validate(opt)
+			if err != nil {
+				return err
+			}
+
+			err = run(opt)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.SetNormalizeFunc(cliflag.WordSepNormalizeFunc)
+	flags.AddGoFlagSet(flag.CommandLine)
+	opt.AddFlags(flags)
+
+	return cmd
+}
+
+func validate(opt *options.ExplainOptions) error {
+	if len(opt.PodFromTemplate) == 0 && len(opt.PodFromCluster) == 0 {
+		return errors.New("pod template file and pod from cluster both is missing")
+	}
+
+	if len(opt.PodFromTemplate) != 0 && len(opt.PodFromCluster) != 0 {
+		return errors.New("pod template file and pod from cluster is exclusive")
+	}
+
+	if len(opt.KubeConfig) == 0 {
+		return errors.New("kubeconfig is missing")
+	}
+
+	if len(opt.SchedulerConfig) == 0 {
+		return errors.New("schedulerconfig is missing")
+	}
+
+	if err := admission.Validate(&opt.Options); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func run(opt *options.ExplainOptions) error {
+	defer klog.Flush()
+	conf := options.NewExplainConfig(opt)
+
+	err := conf.ParseAPISpec()
+	if err != nil {
+		return fmt.Errorf("failed to parse pod spec: %v ", err)
+	}
+
+	report, err := runSimulator(conf)
+	if err != nil {
+		return err
+	}
+
+	if err := report.Print(conf.Options.Verbose, conf.Options.OutputFormat); err != nil {
+		return fmt.Errorf("error while printing: %v", err)
+	}
+
+	return nil
+}
+
+func runSimulator(conf *options.ExplainConfig) (pkg.Printer, error) {
+	s, err := explain.NewExplainSimulatorExecutor(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Initialize(conf.InitObjs...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Report(), nil
+}
@@ -0,0 +1,91 @@
+package options
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientset "k8s.io/client-go/kubernetes"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+type ExplainOptions struct {
+	cmds.Options
+	PodFromTemplate string
+	// namespace/name of the pod from existing cluster
+	PodFromCluster string
+	// only explain this node, empty means all the nodes
+	NodeName string
+}
+
+type ExplainConfig struct {
+	Pod      *corev1.Pod
+	InitObjs []runtime.Object
+	Options  *ExplainOptions
+}
+
+func NewExplainOptions() *ExplainOptions {
+	return &ExplainOptions{}
+}
+
+func NewExplainConfig(opt *ExplainOptions) *ExplainConfig {
+	return &ExplainConfig{
+		Options: opt,
+	}
+}
+
+func (s *ExplainOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.KubeConfig, "kubeconfig", s.KubeConfig, "Path to the kubeconfig file to use for the analysis")
+	fs.StringVar(&s.SchedulerConfig, "schedulerconfig", s.SchedulerConfig, "Path to JSON or YAML file containing scheduler configuration")
+	fs.StringVar(&s.PodFromTemplate, "pod-from-template", s.PodFromTemplate, "Path to JSON or YAML file containing pod definition. Exclusive with --pod-from-cluster")
+	fs.StringVar(&s.PodFromCluster, "pod-from-cluster", s.PodFromCluster, "Namespace/Name of the pod from existing cluster. Exclusive with --pod-from-template")
+	fs.StringVar(&s.NodeName, "node", s.NodeName, "Name of the node to explain. By default all the nodes")
+	fs.StringSliceVar(&s.ExcludeNodes, "exclude-nodes", s.ExcludeNodes, "Exclude nodes to be scheduled")
+	fs.StringSliceVar(&s.EnableAdmissionPlugins, "enable-admission-plugins", s.EnableAdmissionPlugins, "Built-in admission plugins called for the pods created besides the default ones. Any of: LimitRanger|ServiceAccount|Priority|PodTolerationRestriction|RuntimeClass. By default LimitRanger, ServiceAccount, Priority and RuntimeClass are called")
+	fs.StringSliceVar(&s.DisableAdmissionPlugins, "disable-admission-plugins", s.DisableAdmissionPlugins, "Built-in admission plugins not called for the pods created. Any of: LimitRanger|ServiceAccount|Priority|PodTolerationRestriction|RuntimeClass")
+	fs.StringVar(&s.AdmissionWebhookConfig, "admission-webhook-config", s.AdmissionWebhookConfig, "Path to a JSON or YAML file of the MutatingWebhookConfigurations called for the pods created, the webhooks must have a url")
+	fs.StringVarP(&s.OutputFormat, "output", "o", s.OutputFormat, "Output format. One of: json|yaml (Note: output is not versioned or guaranteed to be stable across releases)")
+	fs.BoolVar(&s.Verbose, "verbose", s.Verbose, "Verbose mode, show the verdict of every plugin on every node")
+}
+
+func (s *ExplainConfig) ParseAPISpec() error {
+	if len(s.Options.PodFromTemplate) != 0 {
+		pod, err := utils.GetPodFromTemplate(s.Options.PodFromTemplate)
+		if err != nil {
+			return err
+		}
+		s.Pod = pod
+		return nil
+	}
+
+	namespace, name := metav1.NamespaceDefault, s.Options.PodFromCluster
+	if strs := strings.Split(s.Options.PodFromCluster, "/"); len(strs) == 2 {
+		namespace, name = strs[0], strs[1]
+	} else if len(strs) > 2 {
+		return errors.New("invalid format of pod from cluster")
+	}
+
+	cfg, err := utils.BuildRestConfig(s.Options.KubeConfig)
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := clientset.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	pod, err := kubeClient.CoreV1().Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{ResourceVersion: "0"})
+	if err != nil {
+		return err
+	}
+	s.Pod = pod
+
+	return nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright © 2023 k-cloud-labs org
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporter
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/klog/v2"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/exporter/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/exporter"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/framework"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+var exporterLong = dedent.Dedent(`
+		exporter runs ce for the reference pods given by --pods-from-template and cc for the Kubernetes environment
+		specified in KUBECONFIG every --interval, and exposes the remaining replicas of each reference pod in
+		total, per zone and per node group, the number of removable nodes, and the duration and status of the
+		last runs as Prometheus metrics.
+	`)
+
+func NewExporterCmd() *cobra.Command {
+	opt := options.NewExporterOptions()
+
+	var cmd = &cobra.Command{
+		Use:           "exporter --kubeconfig KUBECONFIG --pods-from-template PODYAML",
+		Short:         "exporter exposes the capacity of the cluster as prometheus metrics",
+		Long:          exporterLong,
+		SilenceErrors: false,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flag.Parse()
+
+			err := validate(opt)
+			if err != nil {
+				return err
+			}
+
+			err = run(opt)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.SetNormalizeFunc(cliflag.WordSepNormalizeFunc)
+	flags.AddGoFlagSet(flag.CommandLine)
+	opt.AddFlags(flags)
+
+	return cmd
+}
+
+func validate(opt *options.ExporterOptions) error {
+	if len(opt.Address) == 0 {
+		return errors.New("address is missing")
+	}
+
+	if opt.Interval <= 0 {
+		return errors.New("interval must be greater than 0")
+	}
+
+	// make sure the scheduler configuration is valid before exporting
+	if _, err := utils.LoadKubeSchedulerConfiguration(opt.SchedulerConfig); err != nil {
+		return fmt.Errorf("invalid schedulerconfig: %v", err)
+	}
+
+	return nil
+}
+
+func run(opt *options.ExporterOptions) error {
+	defer klog.Flush()
+	conf := options.NewExporterConfig(opt)
+
+	for _, template := range opt.PodsFromTemplate {
+		pod, err := utils.GetPodFromTemplate(template)
+		if err != nil {
+			return fmt.Errorf("failed to get pod from template %s: %v", template, err)
+		}
+		conf.Pods = append(conf.Pods, pod)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	kubeConfig, err := utils.BuildRestConfig(opt.KubeConfig)
+	if err != nil {
+		return err
+	}
+	cache, err := framework.NewClusterCache(kubeConfig)
+	if err != nil {
+		return err
+	}
+	klog.InfoS("Waiting for the cache of the cluster to be synced")
+	if err := cache.Start(ctx.Done()); err != nil {
+		return err
+	}
+
+	return exporter.NewExporter(conf, cache).Run(ctx)
+}
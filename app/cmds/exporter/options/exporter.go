@@ -0,0 +1,53 @@
+package options
+
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds"
+)
+
+type ExporterOptions struct {
+	cmds.Options
+	PodsFromTemplate []string
+	// address the metrics are served on
+	Address string
+	// how often the simulations run
+	Interval time.Duration
+	// labels of the nodes used to group the replicas
+	ZoneLabel      string
+	NodeGroupLabel string
+}
+
+type ExporterConfig struct {
+	// the reference templates estimated by ce in each run
+	Pods    []*corev1.Pod
+	Options *ExporterOptions
+}
+
+func NewExporterOptions() *ExporterOptions {
+	return &ExporterOptions{
+		Address:   ":9090",
+		Interval:  5 * time.Minute,
+		ZoneLabel: corev1.LabelTopologyZone,
+	}
+}
+
+func NewExporterConfig(opt *ExporterOptions) *ExporterConfig {
+	return &ExporterConfig{
+		Options: opt,
+	}
+}
+
+func (s *ExporterOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.KubeConfig, "kubeconfig", s.KubeConfig, "Path to the kubeconfig file of the cluster to export the capacity of. By default the in-cluster config")
+	fs.StringVar(&s.SchedulerConfig, "schedulerconfig", s.SchedulerConfig, "Path to JSON or YAML file containing scheduler configuration used by all the simulations. By default the default scheduler configuration")
+	fs.StringSliceVar(&s.PodsFromTemplate, "pods-from-template", s.PodsFromTemplate, "Path to JSON or YAML file containing the definition of a reference pod, whose remaining replicas are exported. Comma seperated")
+	fs.StringSliceVar(&s.ExcludeNodes, "exclude-nodes", s.ExcludeNodes, "Exclude nodes to be scheduled")
+	fs.StringVar(&s.Address, "address", s.Address, "Address the metrics are served on")
+	fs.DurationVar(&s.Interval, "interval", s.Interval, "How often the capacity is estimated. By default 5m")
+	fs.StringVar(&s.ZoneLabel, "zone-label", s.ZoneLabel, "Label of the nodes used to group the remaining replicas by zone")
+	fs.StringVar(&s.NodeGroupLabel, "node-group-label", s.NodeGroupLabel, "Label of the nodes used to group the remaining replicas by node group, e.g. eks.amazonaws.com/nodegroup. The replicas are not grouped by node group if it's empty")
+}
@@ -0,0 +1,121 @@
+/*
+Copyright © 2023 k-cloud-labs org
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	cliflag "k8s.io/component-base/cli/flag"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/history/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/history"
+)
+
+var historyLong = dedent.Dedent(`
+		history shows the trends of the results appended to the history store by the commands with --history,
+		e.g. the remaining replicas of each template, the removable nodes and the nodes used over time. The
+		date when the remaining replicas of a template, or the removable nodes, reach zero is projected with a
+		linear or seasonal fit of the recorded results.
+	`)
+
+func NewHistoryCmd() *cobra.Command {
+	opt := options.NewHistoryOptions()
+
+	var cmd = &cobra.Command{
+		Use:           "history --dir DIR",
+		Short:         "history shows the trends of the recorded results and forecasts the capacity exhaustion",
+		Long:          historyLong,
+		SilenceErrors: false,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flag.Parse()
+
+			err := validate(opt)
+			if err != nil {
+				return err
+			}
+
+			err = run(opt)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.SetNormalizeFunc(cliflag.WordSepNormalizeFunc)
+	flags.AddGoFlagSet(flag.CommandLine)
+	opt.AddFlags(flags)
+
+	return cmd
+}
+
+func validate(opt *options.HistoryOptions) error {
+	if len(opt.Dir) == 0 {
+		return errors.New("dir is missing")
+	}
+
+	switch opt.Command {
+	case "", history.CommandCapacityEstimation, history.CommandClusterCompression, history.CommandSchedulerSimulation,
+		history.CommandSSTune, history.CommandSSCompare, history.CommandHPABurst, history.CommandWhatIf,
+		history.CommandResilience, history.CommandUpgrade:
+	default:
+		return fmt.Errorf("command %q not recognized", opt.Command)
+	}
+
+	if opt.Since < 0 {
+		return errors.New("since must not be negative")
+	}
+
+	if opt.Fit != history.FitLinear && opt.Fit != history.FitSeasonal {
+		return fmt.Errorf("fit %q not recognized", opt.Fit)
+	}
+
+	if opt.Fit == history.FitSeasonal && opt.Season < time.Hour {
+		return errors.New("season must be at least 1h")
+	}
+
+	if opt.Horizon <= 0 {
+		return errors.New("horizon must be greater than 0")
+	}
+
+	return nil
+}
+
+func run(opt *options.HistoryOptions) error {
+	var since time.Time
+	if opt.Since > 0 {
+		since = time.Now().Add(-opt.Since)
+	}
+	records, err := history.NewStore(opt.Dir).List(opt.Command, since)
+	if err != nil {
+		return err
+	}
+
+	review := history.Analyze(records, opt.Fit, opt.Season, opt.Horizon)
+	if err := review.Print(opt.Verbose, opt.OutputFormat); err != nil {
+		return fmt.Errorf("error while printing: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package options
+
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/k-cloud-labs/kluster-capacity/pkg/history"
+)
+
+type HistoryOptions struct {
+	// directory of the history store
+	Dir string
+	// only the records of the command are shown if specified
+	Command string
+	// only the records written within the duration are shown if specified
+	Since time.Duration
+	// linear or seasonal
+	Fit string
+	// period of the seasonal fit
+	Season time.Duration
+	// how far the exhaustion is forecast
+	Horizon      time.Duration
+	Verbose      bool
+	OutputFormat string
+}
+
+func NewHistoryOptions() *HistoryOptions {
+	return &HistoryOptions{
+		Fit:     history.FitLinear,
+		Season:  7 * 24 * time.Hour,
+		Horizon: 365 * 24 * time.Hour,
+	}
+}
+
+func (s *HistoryOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.Dir, "dir", s.Dir, "Directory of the history store, the same as the --history of the other commands")
+	fs.StringVar(&s.Command, "command", s.Command, "Only show the history of the command. One of: ce|cc|ss|ss-tune|ss-compare|hb|whatif|resilience|upgrade. By default all the commands")
+	fs.DurationVar(&s.Since, "since", s.Since, "Only use the results recorded within the duration, e.g. 720h. By default all the results")
+	fs.StringVar(&s.Fit, "fit", s.Fit, "How the exhaustion is forecast. One of: linear|seasonal. The seasonal fit adds the mean deviation of each hour of the season to the linear trend")
+	fs.DurationVar(&s.Season, "season", s.Season, "Period of the seasonal fit. By default a week")
+	fs.DurationVar(&s.Horizon, "horizon", s.Horizon, "How far the exhaustion is forecast. By default a year")
+	fs.StringVarP(&s.OutputFormat, "output", "o", s.OutputFormat, "Output format. One of: json|yaml")
+	fs.BoolVar(&s.Verbose, "verbose", s.Verbose, "Verbose mode, show every recorded value")
+}
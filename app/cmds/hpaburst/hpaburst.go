@@ -0,0 +1,135 @@
+/*
+Copyright © 2023 k-cloud-labs org
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hpaburst
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/klog/v2"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/hpaburst/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/admission"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/history"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/hpaburst"
+)
+
+var hpaBurstLong = dedent.Dedent(`
+		hb simulates an API server with initial state copied from the Kubernetes environment
+		with its configuration specified in KUBECONFIG. It loads all HorizontalPodAutoscalers and their target
+		workloads, then scales every target from its current replicas to maxReplicas at the same time, either in
+		the order of pod priority or interleaved, and reports which HorizontalPodAutoscalers can't reach their maximum.
+	`)
+
+func NewHPABurstCmd() *cobra.Command {
+	opt := options.NewHPABurstOptions()
+
+	var cmd = &cobra.Command{
+		Use:           "hb",
+		Short:         "hb is used to check whether all HorizontalPodAutoscalers can reach maxReplicas at once",
+		Long:          hpaBurstLong,
+		SilenceErrors: false,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flag.Parse()
+
+			err := validate(opt)
+			if err != nil {
+				return err
+			}
+
+			err = run(opt)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.SetNormalizeFunc(cliflag.WordSepNormalizeFunc)
+	flags.AddGoFlagSet(flag.CommandLine)
+	opt.AddFlags(flags)
+
+	return cmd
+}
+
+func validate(opt *options.HPABurstOptions) error {
+	if opt.ScaleOrder != options.ScaleInPriorityOrder && opt.ScaleOrder != options.ScaleInterleaved {
+		return errors.New("scale order must be Priority or Interleaved")
+	}
+
+	if len(opt.KubeConfig) == 0 {
+		return errors.New("kubeconfig is missing")
+	}
+
+	if len(opt.SchedulerConfig) == 0 {
+		return errors.New("schedulerconfig is missing")
+	}
+
+	if err := admission.Validate(&opt.Options); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func run(opt *options.HPABurstOptions) error {
+	defer klog.Flush()
+	conf := options.NewHPABurstConfig(opt)
+
+	reports, err := runSimulator(conf)
+	if err != nil {
+		return err
+	}
+
+	if err := reports.Print(conf.Options.Verbose, conf.Options.OutputFormat); err != nil {
+		return fmt.Errorf("error while printing: %v", err)
+	}
+
+	if len(conf.Options.History) > 0 {
+		if err := history.Append(conf.Options.History, history.CommandHPABurst, reports); err != nil {
+			return fmt.Errorf("failed to append the result to the history: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func runSimulator(conf *options.HPABurstConfig) (pkg.Printer, error) {
+	s, err := hpaburst.NewHBSimulatorExecutor(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Initialize()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Report(), nil
+}
@@ -0,0 +1,51 @@
+package options
+
+import (
+	"github.com/spf13/pflag"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds"
+)
+
+const (
+	// ScaleInPriorityOrder means scale the targets one by one in the order of the priority of their pods
+	ScaleInPriorityOrder = "Priority"
+	// ScaleInterleaved means scale all the targets one replica at a time in turn
+	ScaleInterleaved = "Interleaved"
+)
+
+type HPABurstOptions struct {
+	cmds.Options
+	// Priority, Interleaved
+	ScaleOrder string
+	// only simulate hpa in these namespaces, empty means all namespaces
+	Namespaces []string
+}
+
+type HPABurstConfig struct {
+	Options *HPABurstOptions
+}
+
+func NewHPABurstOptions() *HPABurstOptions {
+	return &HPABurstOptions{}
+}
+
+func NewHPABurstConfig(opt *HPABurstOptions) *HPABurstConfig {
+	return &HPABurstConfig{
+		Options: opt,
+	}
+}
+
+func (s *HPABurstOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.KubeConfig, "kubeconfig", s.KubeConfig, "Path to the kubeconfig file to use for the analysis")
+	fs.StringVar(&s.SchedulerConfig, "schedulerconfig", s.SchedulerConfig, "Path to JSON or YAML file containing scheduler configuration")
+	fs.StringVarP(&s.OutputFormat, "output", "o", s.OutputFormat, "Output format. One of: json|yaml (Note: output is not versioned or guaranteed to be stable across releases)")
+	fs.StringSliceVar(&s.ExcludeNodes, "exclude-nodes", s.ExcludeNodes, "Exclude nodes to be scheduled")
+	fs.StringSliceVar(&s.EnableAdmissionPlugins, "enable-admission-plugins", s.EnableAdmissionPlugins, "Built-in admission plugins called for the pods created besides the default ones. Any of: LimitRanger|ServiceAccount|Priority|PodTolerationRestriction|RuntimeClass. By default LimitRanger, ServiceAccount, Priority and RuntimeClass are called")
+	fs.StringSliceVar(&s.DisableAdmissionPlugins, "disable-admission-plugins", s.DisableAdmissionPlugins, "Built-in admission plugins not called for the pods created. Any of: LimitRanger|ServiceAccount|Priority|PodTolerationRestriction|RuntimeClass")
+	fs.StringVar(&s.AdmissionWebhookConfig, "admission-webhook-config", s.AdmissionWebhookConfig, "Path to a JSON or YAML file of the MutatingWebhookConfigurations called for the pods created, the webhooks must have a url")
+	fs.StringSliceVar(&s.Namespaces, "namespaces", s.Namespaces, "Only simulate the HorizontalPodAutoscalers in these namespaces. By default all namespaces")
+	fs.StringVar(&s.ScaleOrder, "scale-order", ScaleInPriorityOrder, "Order to scale the targets of HorizontalPodAutoscalers. One of: Priority|Interleaved")
+	fs.BoolVar(&s.Verbose, "verbose", s.Verbose, "Verbose mode")
+	fs.StringVar(&s.Trace, "trace", s.Trace, "File path to write the scheduling trace of each pod as NDJSON, including the filter and score plugin results of each node")
+	fs.StringVar(&s.History, "history", s.History, "Directory of the history store to append the result to, the trends of the results are shown by the history command")
+}
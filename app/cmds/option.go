@@ -1,14 +1,46 @@
 package cmds
 
+const (
+	// DefaultUsagePercentile is the percentile of the usage the requests are right-sized to by default
+	DefaultUsagePercentile = 95
+	// DefaultUsageMargin is the margin added to the usage by default
+	DefaultUsageMargin = 0.1
+	// DefaultOutputVersion is the version of the output api printed by default, the unversioned shape printed
+	// before the output was versioned so that the existing consumers keep working
+	DefaultOutputVersion = "v1alpha1"
+	// LatestOutputVersion is the latest stable version of the output api, the saved results are converted to it
+	// by default
+	LatestOutputVersion = "v1"
+)
+
 type Options struct {
 	SchedulerConfig string
 	KubeConfig      string
 	Verbose         bool
 	OutputFormat    string
+	// version of the output api printed with json or yaml
+	OutputVersion string
 	// file to load initial data instead of from k8s cluster
 	Snapshot string
 	// file to save the result
 	SaveTo       string
 	ExcludeNodes []string
 	MaxLimit     int
+	// file to write the scheduling trace of each pod
+	Trace string
+	// directory of the history store to append the result to
+	History string
+	// file of the usage of the pods, the requests of the pods are right-sized to the usage if it's specified
+	UsageFile string
+	// percentile of the usage the requests are right-sized to
+	UsagePercentile int
+	// margin added to the usage, e.g. 0.1 for 10%
+	UsageMargin float64
+	// built-in admission plugins called for the pods created besides the default ones, i.e. PodTolerationRestriction
+	EnableAdmissionPlugins []string
+	// built-in admission plugins not called for the pods created, LimitRanger, ServiceAccount, Priority and
+	// RuntimeClass are called by default
+	DisableAdmissionPlugins []string
+	// file of the MutatingWebhookConfigurations called for the pods created
+	AdmissionWebhookConfig string
 }
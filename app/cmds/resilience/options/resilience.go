@@ -0,0 +1,49 @@
+package options
+
+import (
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds"
+)
+
+type ResilienceOptions struct {
+	cmds.Options
+	// label of the nodes grouping them into failure domains, e.g. topology.kubernetes.io/zone, each node is a
+	// failure domain if empty
+	TopologyKey string
+	// number of failure domains simulated in parallel
+	Parallelism int
+}
+
+type ResilienceConfig struct {
+	InitObjs []runtime.Object
+	Options  *ResilienceOptions
+}
+
+func NewResilienceOptions() *ResilienceOptions {
+	return &ResilienceOptions{
+		Parallelism: 4,
+	}
+}
+
+func NewResilienceConfig(opt *ResilienceOptions) *ResilienceConfig {
+	return &ResilienceConfig{
+		Options: opt,
+	}
+}
+
+func (s *ResilienceOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.KubeConfig, "kubeconfig", s.KubeConfig, "Path to the kubeconfig file to use for the analysis")
+	fs.StringVar(&s.SchedulerConfig, "schedulerconfig", s.SchedulerConfig, "Path to JSON or YAML file containing scheduler configuration")
+	fs.StringVar(&s.Snapshot, "snapshot", s.Snapshot, "Path of a JSON or YAML file of the objects to initialize the world, e.g. the output of kubectl get -o yaml. Exclusive with --kubeconfig")
+	fs.StringVar(&s.TopologyKey, "topology-key", s.TopologyKey, "Label of the nodes grouping them into failure domains, e.g. topology.kubernetes.io/zone. By default each node is a failure domain, i.e. the N+1 analysis")
+	fs.IntVar(&s.Parallelism, "parallelism", s.Parallelism, "Number of failure domains simulated in parallel, all the simulations share the same snapshot of the cluster. By default 4")
+	fs.StringSliceVar(&s.ExcludeNodes, "exclude-nodes", s.ExcludeNodes, "Exclude nodes to be scheduled")
+	fs.StringSliceVar(&s.EnableAdmissionPlugins, "enable-admission-plugins", s.EnableAdmissionPlugins, "Built-in admission plugins called for the pods created besides the default ones. Any of: LimitRanger|ServiceAccount|Priority|PodTolerationRestriction|RuntimeClass. By default LimitRanger, ServiceAccount, Priority and RuntimeClass are called")
+	fs.StringSliceVar(&s.DisableAdmissionPlugins, "disable-admission-plugins", s.DisableAdmissionPlugins, "Built-in admission plugins not called for the pods created. Any of: LimitRanger|ServiceAccount|Priority|PodTolerationRestriction|RuntimeClass")
+	fs.StringVar(&s.AdmissionWebhookConfig, "admission-webhook-config", s.AdmissionWebhookConfig, "Path to a JSON or YAML file of the MutatingWebhookConfigurations called for the pods created, the webhooks must have a url")
+	fs.StringVarP(&s.OutputFormat, "output", "o", s.OutputFormat, "Output format. One of: json|yaml (Note: output is not versioned or guaranteed to be stable across releases)")
+	fs.StringVar(&s.History, "history", s.History, "Directory of the history store to append the result to, the trends of the results are shown by the history command")
+	fs.BoolVar(&s.Verbose, "verbose", s.Verbose, "Verbose mode, show the pending pods of each failure domain the cluster can't survive")
+}
@@ -0,0 +1,143 @@
+/*
+Copyright © 2023 k-cloud-labs org
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resilience
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/klog/v2"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/resilience/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/admission"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/framework"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/history"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/resilience"
+)
+
+var resilienceLong = dedent.Dedent(`
+		resilience simulates an API server with initial state copied from the Kubernetes environment
+		with its configuration specified in KUBECONFIG, or from a snapshot. Each failure domain, i.e. each
+		node or each value of a topology label such as topology.kubernetes.io/zone, is removed from the
+		simulated world in an independent trial, and the pods displaced from it are rescheduled by the
+		scheduler with the configuration specified. The failure domains leaving pods pending are the ones
+		the cluster can't survive, so the cluster is N+1 if there are none.
+	`)
+
+func NewResilienceCmd() *cobra.Command {
+	opt := options.NewResilienceOptions()
+
+	var cmd = &cobra.Command{
+		Use:           "resilience --kubeconfig KUBECONFIG | --snapshot SNAPSHOT",
+		Aliases:       []string{"rs"},
+		Short:         "resilience is used to check if the cluster survives the loss of any single node or zone",
+		Long:          resilienceLong,
+		SilenceErrors: false,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flag.Parse()
+
+			err := validate(opt)
+			if err != nil {
+				return err
+			}
+
+			err = run(opt)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.SetNormalizeFunc(cliflag.WordSepNormalizeFunc)
+	flags.AddGoFlagSet(flag.CommandLine)
+	opt.AddFlags(flags)
+
+	return cmd
+}
+
+func validate(opt *options.ResilienceOptions) error {
+	if len(opt.KubeConfig) > 0 && len(opt.Snapshot) > 0 {
+		return errors.New("kubeconfig and snapshot is exclusive")
+	}
+
+	if opt.Parallelism < 1 {
+		return errors.New("parallelism must be at least 1")
+	}
+
+	if err := admission.Validate(&opt.Options); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func run(opt *options.ResilienceOptions) error {
+	defer klog.Flush()
+	conf := options.NewResilienceConfig(opt)
+
+	if len(opt.Snapshot) > 0 {
+		var err error
+		conf.InitObjs, err = framework.GetInitObjectsFromFile(opt.Snapshot)
+		if err != nil {
+			return err
+		}
+	}
+
+	report, err := runSimulator(conf)
+	if err != nil {
+		return err
+	}
+
+	if err := report.Print(conf.Options.Verbose, conf.Options.OutputFormat); err != nil {
+		return fmt.Errorf("error while printing: %v", err)
+	}
+
+	if len(conf.Options.History) > 0 {
+		if err := history.Append(conf.Options.History, history.CommandResilience, report); err != nil {
+			return fmt.Errorf("failed to append the result to the history: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func runSimulator(conf *options.ResilienceConfig) (pkg.Printer, error) {
+	s, err := resilience.NewResilienceSimulatorExecutor(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Initialize(conf.InitObjs...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Report(), nil
+}
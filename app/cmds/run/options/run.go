@@ -0,0 +1,19 @@
+package options
+
+import (
+	"github.com/spf13/pflag"
+)
+
+type RunOptions struct {
+	Verbose      bool
+	OutputFormat string
+}
+
+func NewRunOptions() *RunOptions {
+	return &RunOptions{}
+}
+
+func (s *RunOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&s.OutputFormat, "output", "o", s.OutputFormat, "Output format. One of: json|yaml")
+	fs.BoolVar(&s.Verbose, "verbose", s.Verbose, "Verbose mode")
+}
@@ -0,0 +1,94 @@
+/*
+Copyright © 2023 k-cloud-labs org
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package run
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cliflag "k8s.io/component-base/cli/flag"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/run/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/scenario"
+)
+
+var runLong = dedent.Dedent(`
+		run executes the scenario specified with --config, which describes where the world comes from, the
+		scheduler configuration and a list of named ce, cc and ss runs with their options, and prints one
+		combined report of all the runs. The world is loaded once and shared by all the runs, a failed run
+		doesn't stop the following ones.
+	`)
+
+func NewRunCmd() *cobra.Command {
+	opt := options.NewRunOptions()
+
+	var cmd = &cobra.Command{
+		Use:           "run --config SCENARIO",
+		Short:         "run executes the ce, cc and ss runs described in a scenario file",
+		Long:          runLong,
+		SilenceErrors: false,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flag.Parse()
+
+			err := run(opt)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.SetNormalizeFunc(cliflag.WordSepNormalizeFunc)
+	flags.AddGoFlagSet(flag.CommandLine)
+	opt.AddFlags(flags)
+
+	return cmd
+}
+
+func run(opt *options.RunOptions) error {
+	// the scenario file is found by viper as the config file, and decoded as it is since viper lowercases the
+	// keys, e.g. the ones of the labels
+	if err := viper.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read the scenario file, specify it with --config: %v", err)
+	}
+	path := viper.ConfigFileUsed()
+
+	s, err := scenario.Load(path)
+	if err != nil {
+		return fmt.Errorf("invalid scenario %s: %v", path, err)
+	}
+
+	review, err := scenario.Execute(s, path)
+	if err != nil {
+		return err
+	}
+
+	if err := review.Print(opt.Verbose, opt.OutputFormat); err != nil {
+		return fmt.Errorf("error while printing: %v", err)
+	}
+
+	if failed := review.Failed(); failed > 0 {
+		return fmt.Errorf("%d of %d runs failed", failed, len(review.Runs))
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright © 2023 k-cloud-labs org
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulersimulation
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/klog/v2"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/schedulersimulation/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/framework"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/history"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/schedulersimulation"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+var compareLong = dedent.Dedent(`
+		compare replays the same pods of the Kubernetes environment specified in KUBECONFIG twice, under the two
+		scheduler configurations specified by --schedulerconfig, and reports the differences: nodes used,
+		unschedulable pods, pods placed differently and per-node utilization deltas.
+	`)
+
+func NewCompareCmd() *cobra.Command {
+	opt := options.NewSchedulerSimulationCompareOptions()
+
+	var cmd = &cobra.Command{
+		Use:           "compare --kubeconfig KUBECONFIG --schedulerconfig BASE,TARGET",
+		Short:         "compare is used for comparing the scheduling of pods under two scheduler configurations",
+		Long:          compareLong,
+		SilenceErrors: false,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flag.Parse()
+
+			err := validateCompare(opt)
+			if err != nil {
+				return err
+			}
+
+			err = runCompare(opt)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.SetNormalizeFunc(cliflag.WordSepNormalizeFunc)
+	flags.AddGoFlagSet(flag.CommandLine)
+	opt.AddFlags(flags)
+
+	return cmd
+}
+
+func validateCompare(opt *options.SchedulerSimulationCompareOptions) error {
+	if len(opt.SchedulerConfigs) != 2 {
+		return errors.New("two schedulerconfigs must be specified for comparison")
+	}
+
+	// the scheduler config is checked separately
+	ssOpt := opt.SchedulerSimulationOptions
+	ssOpt.SchedulerConfig = opt.SchedulerConfigs[0]
+
+	return validate(&ssOpt)
+}
+
+func runCompare(opt *options.SchedulerSimulationCompareOptions) error {
+	defer klog.Flush()
+	conf := options.NewSchedulerSimulationCompareConfig(opt)
+
+	// load the world only once so that both simulations replay exactly the same pods
+	kubeConfig, err := utils.BuildRestConfig(opt.KubeConfig)
+	if err != nil {
+		return err
+	}
+	conf.InitObjs, err = framework.GetInitObjectsFromCluster(kubeConfig)
+	if err != nil {
+		return err
+	}
+
+	reports, err := runCompareSimulator(conf)
+	if err != nil {
+		return err
+	}
+
+	if err := reports.Print(opt.Verbose, opt.OutputFormat); err != nil {
+		return fmt.Errorf("error while printing: %v", err)
+	}
+
+	if len(opt.History) > 0 {
+		if err := history.Append(opt.History, history.CommandSSCompare, reports); err != nil {
+			return fmt.Errorf("failed to append the result to the history: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func runCompareSimulator(conf *options.SchedulerSimulationCompareConfig) (pkg.Printer, error) {
+	s, err := schedulersimulation.NewSSCompareExecutor(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Initialize(conf.InitObjs...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Report(), nil
+}
@@ -1,8 +1,11 @@
 package options
 
 import (
+	"time"
+
 	"github.com/spf13/pflag"
 	"k8s.io/apimachinery/pkg/runtime"
+	kubeschedulerconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
 
 	"github.com/k-cloud-labs/kluster-capacity/app/cmds"
 )
@@ -30,15 +33,29 @@ type SchedulerSimulationOptions struct {
 	SourceFrom               string
 	ExitCondition            string
 	IgnorePodsOnExcludeNodes bool
+	// order of the pods entering the scheduling queue, empty means the queue sort of the scheduler configuration
+	ReplayOrder string
+	// seed of the Random replay order, 0 means a seed generated from the current time
+	ReplaySeed int64
+	// max duration of the simulation, 0 means no timeout
+	Timeout time.Duration
 }
 
 type SchedulerSimulationConfig struct {
 	Options  *SchedulerSimulationOptions
 	InitObjs []runtime.Object
+	// used instead of the scheduler configuration file of the options if it's not nil
+	KubeSchedulerConfig *kubeschedulerconfig.KubeSchedulerConfiguration
 }
 
 func NewSchedulerSimulationOptions() *SchedulerSimulationOptions {
-	return &SchedulerSimulationOptions{}
+	return &SchedulerSimulationOptions{
+		Options: cmds.Options{
+			UsagePercentile: cmds.DefaultUsagePercentile,
+			UsageMargin:     cmds.DefaultUsageMargin,
+			OutputVersion:   cmds.DefaultOutputVersion,
+		},
+	}
 }
 
 func NewSchedulerSimulationConfig(option *SchedulerSimulationOptions) *SchedulerSimulationConfig {
@@ -48,14 +65,122 @@ func NewSchedulerSimulationConfig(option *SchedulerSimulationOptions) *Scheduler
 }
 
 func (s *SchedulerSimulationOptions) AddFlags(fs *pflag.FlagSet) {
-	fs.StringVar(&s.KubeConfig, "kubeconfig", s.KubeConfig, "Path to the kubeconfig file to use for the analysis")
 	fs.StringVar(&s.SchedulerConfig, "schedulerconfig", s.SchedulerConfig, "Path to JSON or YAML file containing scheduler configuration. Used when source-from is cluster")
-	fs.StringVarP(&s.OutputFormat, "output", "o", s.OutputFormat, "Output format. One of: json|yaml")
+	fs.StringVarP(&s.SaveTo, "save", "s", s.SaveTo, "File path to save the simulation result")
+	fs.StringVar(&s.UsageFile, "usage-file", s.UsageFile, "Path to a CSV or JSON file of the usage percentiles of the pods, e.g. exported from Prometheus. The simulation runs on the world as it's requested and on the world with the requests of the pods right-sized to the usage, and the capacity gained is reported")
+	fs.IntVar(&s.UsagePercentile, "usage-percentile", s.UsagePercentile, "Percentile of the usage the requests are right-sized to, the usage file must record it. By default 95")
+	fs.Float64Var(&s.UsageMargin, "usage-margin", s.UsageMargin, "Margin added to the usage when right-sizing the requests, e.g. 0.1 for 10%. By default 0.1")
+	s.addSimulationFlags(fs)
+}
+
+func (s *SchedulerSimulationOptions) addSimulationFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.KubeConfig, "kubeconfig", s.KubeConfig, "Path to the kubeconfig file to use for the analysis")
+	fs.StringVarP(&s.OutputFormat, "output", "o", s.OutputFormat, "Output format. One of: json|yaml|html")
+	fs.StringVar(&s.OutputVersion, "output-version", s.OutputVersion, "Version of the output api printed with json or yaml. One of: v1|v1alpha1. By default v1alpha1, the unversioned shape printed before the output was versioned")
 	fs.StringSliceVar(&s.ExcludeNodes, "exclude-nodes", s.ExcludeNodes, "Exclude nodes to be scheduled")
+	fs.StringSliceVar(&s.EnableAdmissionPlugins, "enable-admission-plugins", s.EnableAdmissionPlugins, "Built-in admission plugins called for the pods created besides the default ones. Any of: LimitRanger|ServiceAccount|Priority|PodTolerationRestriction|RuntimeClass. By default LimitRanger, ServiceAccount, Priority and RuntimeClass are called")
+	fs.StringSliceVar(&s.DisableAdmissionPlugins, "disable-admission-plugins", s.DisableAdmissionPlugins, "Built-in admission plugins not called for the pods created. Any of: LimitRanger|ServiceAccount|Priority|PodTolerationRestriction|RuntimeClass")
+	fs.StringVar(&s.AdmissionWebhookConfig, "admission-webhook-config", s.AdmissionWebhookConfig, "Path to a JSON or YAML file of the MutatingWebhookConfigurations called for the pods created, the webhooks must have a url")
 	fs.BoolVarP(&s.IgnorePodsOnExcludeNodes, "ignore-pods-on-excludes-nodes", "i", true, "Whether ignore the pods on the excludes nodes. By default true")
 	fs.StringVar(&s.Snapshot, "snapshot", s.Snapshot, "Path of snapshot to initialize the world. Used when source-from is snapshot")
 	fs.StringVar(&s.SourceFrom, "source-from", "Cluster", "Source of the init data. One of: Cluster|Snapshot")
 	fs.StringVar(&s.ExitCondition, "exit-condition", "AllSucceed", "Exit condition of the simulator. One of: AllScheduled|AllSucceed")
 	fs.BoolVar(&s.Verbose, "verbose", s.Verbose, "Verbose mode")
-	fs.StringVarP(&s.SaveTo, "save", "s", s.SaveTo, "File path to save the simulation result")
+	fs.StringVar(&s.Trace, "trace", s.Trace, "File path to write the scheduling trace of each pod as NDJSON, including the filter and score plugin results of each node")
+	fs.StringVar(&s.History, "history", s.History, "Directory of the history store to append the result to, the trends of the results are shown by the history command")
+	fs.StringVar(&s.ReplayOrder, "replay-order", s.ReplayOrder, "Order of the pods to be replayed. One of: CreationTimestamp|Priority|LargestRequestFirst|Owner|Random. By default the queue sort of the scheduler configuration")
+	fs.DurationVar(&s.Timeout, "timeout", s.Timeout, "Max duration of the simulation, the simulation stops with a partial result when it's exceeded. By default no timeout")
+	fs.Int64Var(&s.ReplaySeed, "replay-seed", s.ReplaySeed, "Seed of the Random replay order, the same seed gives the same order. By default generated from the current time")
+}
+
+type SchedulerSimulationCompareOptions struct {
+	SchedulerSimulationOptions
+	// the first one is the base, the second one is compared with it
+	SchedulerConfigs []string
+}
+
+type SchedulerSimulationCompareConfig struct {
+	Options  *SchedulerSimulationCompareOptions
+	InitObjs []runtime.Object
+}
+
+func NewSchedulerSimulationCompareOptions() *SchedulerSimulationCompareOptions {
+	return &SchedulerSimulationCompareOptions{
+		SchedulerSimulationOptions: *NewSchedulerSimulationOptions(),
+	}
+}
+
+func NewSchedulerSimulationCompareConfig(option *SchedulerSimulationCompareOptions) *SchedulerSimulationCompareConfig {
+	return &SchedulerSimulationCompareConfig{
+		Options: option,
+	}
+}
+
+func (s *SchedulerSimulationCompareOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringSliceVar(&s.SchedulerConfigs, "schedulerconfig", s.SchedulerConfigs, "Paths to the two JSON or YAML files containing scheduler configuration to compare. Comma seperated, the first one is the base")
+	s.addSimulationFlags(fs)
+}
+
+const (
+	// ObjectiveNodesUsed minimizes the number of nodes used
+	ObjectiveNodesUsed = "NodesUsed"
+	// ObjectiveCPUPackingEfficiency maximizes the ratio of requested to allocatable cpu of the used nodes
+	ObjectiveCPUPackingEfficiency = "CPUPackingEfficiency"
+	// ObjectiveMemoryPackingEfficiency maximizes the ratio of requested to allocatable memory of the used nodes
+	ObjectiveMemoryPackingEfficiency = "MemoryPackingEfficiency"
+	// ObjectiveCPUUtilizationStdDev minimizes the standard deviation of the cpu requested ratios of the used nodes
+	ObjectiveCPUUtilizationStdDev = "CPUUtilizationStdDev"
+	// ObjectiveMemoryUtilizationStdDev minimizes the standard deviation of the memory requested ratios of the used nodes
+	ObjectiveMemoryUtilizationStdDev = "MemoryUtilizationStdDev"
+)
+
+type SchedulerSimulationTuneOptions struct {
+	SchedulerSimulationOptions
+	Objective string
+	// score plugins whose weights are tuned, each weight is tried for each plugin
+	TunedPlugins []string
+	Weights      []int
+	// scoring strategies of NodeResourcesFit
+	ScoringStrategies []string
+	// each item is a set of resources scored by NodeResourcesFit, e.g. cpu=1,memory=1
+	ScoringResources []string
+	// 0 means the adaptive percentage of the scheduler
+	PercentagesOfNodesToScore []int
+	// number of trials simulated in parallel
+	Parallelism int
+}
+
+type SchedulerSimulationTuneConfig struct {
+	Options  *SchedulerSimulationTuneOptions
+	InitObjs []runtime.Object
+}
+
+func NewSchedulerSimulationTuneOptions() *SchedulerSimulationTuneOptions {
+	return &SchedulerSimulationTuneOptions{
+		SchedulerSimulationOptions: *NewSchedulerSimulationOptions(),
+		Objective:                  ObjectiveNodesUsed,
+		TunedPlugins:               []string{"NodeResourcesFit", "NodeResourcesBalancedAllocation"},
+		Weights:                    []int{1, 2, 5},
+		ScoringStrategies:          []string{string(kubeschedulerconfig.LeastAllocated), string(kubeschedulerconfig.MostAllocated)},
+		PercentagesOfNodesToScore:  []int{0},
+		Parallelism:                4,
+	}
+}
+
+func NewSchedulerSimulationTuneConfig(option *SchedulerSimulationTuneOptions) *SchedulerSimulationTuneConfig {
+	return &SchedulerSimulationTuneConfig{
+		Options: option,
+	}
+}
+
+func (s *SchedulerSimulationTuneOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.SchedulerConfig, "schedulerconfig", s.SchedulerConfig, "Path to JSON or YAML file containing the base scheduler configuration to tune")
+	fs.StringVar(&s.Objective, "objective", s.Objective, "Objective of the tuning, unschedulable pods are always kept at zero first. One of: NodesUsed|CPUPackingEfficiency|MemoryPackingEfficiency|CPUUtilizationStdDev|MemoryUtilizationStdDev")
+	fs.StringSliceVar(&s.TunedPlugins, "tuned-plugins", s.TunedPlugins, "Score plugins whose weights are tuned")
+	fs.IntSliceVar(&s.Weights, "weights", s.Weights, "Weights tried for each tuned score plugin")
+	fs.StringSliceVar(&s.ScoringStrategies, "scoring-strategies", s.ScoringStrategies, "Scoring strategies of NodeResourcesFit to try. Any of: LeastAllocated|MostAllocated")
+	fs.StringArrayVar(&s.ScoringResources, "scoring-resources", s.ScoringResources, "Resources scored by NodeResourcesFit to try, e.g. cpu=1,memory=1. Can be repeated, by default the resources of the base scheduler configuration")
+	fs.IntSliceVar(&s.PercentagesOfNodesToScore, "percentages-of-nodes-to-score", s.PercentagesOfNodesToScore, "Values of percentageOfNodesToScore to try, 0 means the adaptive percentage of the scheduler")
+	fs.IntVar(&s.Parallelism, "parallelism", s.Parallelism, "Number of trials simulated in parallel, all the trials share the same snapshot of the cluster. By default 4")
+	s.addSimulationFlags(fs)
 }
@@ -23,12 +23,20 @@ import (
 
 	"github.com/lithammer/dedent"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
 	cliflag "k8s.io/component-base/cli/flag"
 	"k8s.io/klog/v2"
 
 	"github.com/k-cloud-labs/kluster-capacity/app/cmds/schedulersimulation/options"
 	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/admission"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/framework"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/history"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/output"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/plugins/replayorder"
 	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/schedulersimulation"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/usage"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
 )
 
 var schedulerSimulationLong = dedent.Dedent(`
@@ -68,6 +76,9 @@ func NewSchedulerSimulationCmd() *cobra.Command {
 	flags.AddGoFlagSet(flag.CommandLine)
 	opt.AddFlags(flags)
 
+	cmd.AddCommand(NewCompareCmd())
+	cmd.AddCommand(NewTuneCmd())
+
 	return cmd
 }
 
@@ -84,6 +95,16 @@ func validate(opt *options.SchedulerSimulationOptions) error {
 		return errors.New("exit condition must be AllSucceed or AllScheduled")
 	}
 
+	if opt.Timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
+
+	if len(opt.ReplayOrder) > 0 {
+		if _, err := replayorder.NewFactory(opt.ReplayOrder, opt.ReplaySeed); err != nil {
+			return err
+		}
+	}
+
 	if len(opt.KubeConfig) == 0 {
 		return errors.New("kubeconfig is missing")
 	}
@@ -92,6 +113,18 @@ func validate(opt *options.SchedulerSimulationOptions) error {
 		return errors.New("schedulerconfig is missing")
 	}
 
+	if err := usage.Validate(&opt.Options); err != nil {
+		return err
+	}
+
+	if err := output.Validate(opt.OutputVersion); err != nil {
+		return err
+	}
+
+	if err := admission.Validate(&opt.Options); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -103,15 +136,29 @@ func run(opt *options.SchedulerSimulationOptions) error {
 	//if opt.SourceFrom == options.FromSnapshot {
 	//}
 
-	reports, err := runSimulator(conf)
+	var (
+		reports pkg.Printer
+		err     error
+	)
+	if len(opt.UsageFile) > 0 {
+		reports, err = runUsageSimulator(conf)
+	} else {
+		reports, err = runSimulator(conf)
+	}
 	if err != nil {
 		return err
 	}
 
-	if err := reports.Print(opt.Verbose, opt.OutputFormat); err != nil {
+	if err := output.Print(reports, opt.Verbose, opt.OutputFormat, opt.OutputVersion); err != nil {
 		return fmt.Errorf("error while printing: %v", err)
 	}
 
+	if len(opt.History) > 0 {
+		if err := history.Append(opt.History, history.CommandSchedulerSimulation, usage.Requested(reports)); err != nil {
+			return fmt.Errorf("failed to append the result to the history: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -133,3 +180,21 @@ func runSimulator(conf *options.SchedulerSimulationConfig) (pkg.Printer, error)
 
 	return s.Report(), nil
 }
+
+// runUsageSimulator runs the simulation on the world as it's requested and on the world right-sized to the usage
+func runUsageSimulator(conf *options.SchedulerSimulationConfig) (pkg.Printer, error) {
+	kubeConfig, err := utils.BuildRestConfig(conf.Options.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	objs, err := framework.GetInitObjectsFromCluster(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return usage.Compare(&conf.Options.Options, objs, func(objs []runtime.Object) (pkg.Printer, error) {
+		c := *conf
+		c.InitObjs = objs
+		return runSimulator(&c)
+	})
+}
@@ -0,0 +1,160 @@
+/*
+Copyright © 2023 k-cloud-labs org
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulersimulation
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/klog/v2"
+	kubeschedulerconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/schedulersimulation/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/framework"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/history"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/schedulersimulation"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+var tuneLong = dedent.Dedent(`
+		tune replays the same pods of the Kubernetes environment specified in KUBECONFIG repeatedly, each time under
+		the scheduler configuration specified by --schedulerconfig with different score plugin weights, NodeResourcesFit
+		scoring strategy and resources, and percentageOfNodesToScore. The trials are ranked by the objective while
+		keeping unschedulable pods at zero, and the best configuration is reported.
+	`)
+
+func NewTuneCmd() *cobra.Command {
+	opt := options.NewSchedulerSimulationTuneOptions()
+
+	var cmd = &cobra.Command{
+		Use:           "tune --kubeconfig KUBECONFIG --schedulerconfig SCHEDULERCONFIG",
+		Short:         "tune is used for searching the scheduler configuration parameters which fit the pods best",
+		Long:          tuneLong,
+		SilenceErrors: false,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flag.Parse()
+
+			err := validateTune(opt)
+			if err != nil {
+				return err
+			}
+
+			err = runTune(opt)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.SetNormalizeFunc(cliflag.WordSepNormalizeFunc)
+	flags.AddGoFlagSet(flag.CommandLine)
+	opt.AddFlags(flags)
+
+	return cmd
+}
+
+func validateTune(opt *options.SchedulerSimulationTuneOptions) error {
+	switch opt.Objective {
+	case options.ObjectiveNodesUsed, options.ObjectiveCPUPackingEfficiency, options.ObjectiveMemoryPackingEfficiency,
+		options.ObjectiveCPUUtilizationStdDev, options.ObjectiveMemoryUtilizationStdDev:
+	default:
+		return fmt.Errorf("objective %q not recognized", opt.Objective)
+	}
+
+	if len(opt.Weights) == 0 && len(opt.TunedPlugins) > 0 {
+		return errors.New("weights must be specified when tuned plugins are specified")
+	}
+
+	if len(opt.ScoringStrategies) == 0 {
+		return errors.New("at least one scoring strategy must be specified")
+	}
+	for _, strategy := range opt.ScoringStrategies {
+		// RequestedToCapacityRatio needs a shape which can't be tuned by now
+		if strategy != string(kubeschedulerconfig.LeastAllocated) && strategy != string(kubeschedulerconfig.MostAllocated) {
+			return fmt.Errorf("scoring strategy %q is not supported, it must be LeastAllocated or MostAllocated", strategy)
+		}
+	}
+
+	if len(opt.PercentagesOfNodesToScore) == 0 {
+		return errors.New("at least one percentage of nodes to score must be specified")
+	}
+
+	if opt.Parallelism < 1 {
+		return errors.New("parallelism must be greater than 0")
+	}
+
+	return validate(&opt.SchedulerSimulationOptions)
+}
+
+func runTune(opt *options.SchedulerSimulationTuneOptions) error {
+	defer klog.Flush()
+	conf := options.NewSchedulerSimulationTuneConfig(opt)
+
+	// load the world only once so that all the trials replay exactly the same pods
+	kubeConfig, err := utils.BuildRestConfig(opt.KubeConfig)
+	if err != nil {
+		return err
+	}
+	conf.InitObjs, err = framework.GetInitObjectsFromCluster(kubeConfig)
+	if err != nil {
+		return err
+	}
+
+	reports, err := runTuneSimulator(conf)
+	if err != nil {
+		return err
+	}
+
+	if err := reports.Print(opt.Verbose, opt.OutputFormat); err != nil {
+		return fmt.Errorf("error while printing: %v", err)
+	}
+
+	if len(opt.History) > 0 {
+		if err := history.Append(opt.History, history.CommandSSTune, reports); err != nil {
+			return fmt.Errorf("failed to append the result to the history: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func runTuneSimulator(conf *options.SchedulerSimulationTuneConfig) (pkg.Printer, error) {
+	s, err := schedulersimulation.NewSSTuneExecutor(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Initialize(conf.InitObjs...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Report(), nil
+}
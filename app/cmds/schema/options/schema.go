@@ -0,0 +1,23 @@
+package options
+
+import (
+	"github.com/spf13/pflag"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds"
+)
+
+type SchemaOptions struct {
+	// kind of the output api, set from the argument
+	Kind          string
+	OutputVersion string
+}
+
+func NewSchemaOptions() *SchemaOptions {
+	return &SchemaOptions{
+		OutputVersion: cmds.DefaultOutputVersion,
+	}
+}
+
+func (s *SchemaOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.OutputVersion, "output-version", s.OutputVersion, "Version of the output api. One of: v1|v1alpha1. By default v1alpha1")
+}
@@ -0,0 +1,99 @@
+/*
+Copyright © 2023 k-cloud-labs org
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package schema
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	cliflag "k8s.io/component-base/cli/flag"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/schema/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/output"
+)
+
+var schemaLong = dedent.Dedent(`
+		schema prints the JSON Schema of a kind of the output api in the version specified by --output-version,
+		so that the results of ce, cc and ss printed with -o json or yaml can be validated by the tools reading
+		them. Without KIND the kinds of the version are listed. The results of v1alpha1 have no apiVersion and
+		kind, and the reviews of the templates of ce are printed one by one, the schema of CapacityEstimationReview
+		in v1alpha1 is the one of each of them.
+	`)
+
+func NewSchemaCmd() *cobra.Command {
+	opt := options.NewSchemaOptions()
+
+	var cmd = &cobra.Command{
+		Use:           "schema [KIND]",
+		Short:         "schema prints the JSON Schema of the results of ce, cc and ss",
+		Long:          schemaLong,
+		Args:          cobra.MaximumNArgs(1),
+		SilenceErrors: false,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flag.Parse()
+
+			if len(args) > 0 {
+				opt.Kind = args[0]
+			}
+			err := validate(opt)
+			if err != nil {
+				return err
+			}
+
+			err = run(opt)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.SetNormalizeFunc(cliflag.WordSepNormalizeFunc)
+	flags.AddGoFlagSet(flag.CommandLine)
+	opt.AddFlags(flags)
+
+	return cmd
+}
+
+func validate(opt *options.SchemaOptions) error {
+	return output.Validate(opt.OutputVersion)
+}
+
+func run(opt *options.SchemaOptions) error {
+	if len(opt.Kind) == 0 {
+		fmt.Println(strings.Join(output.Kinds(opt.OutputVersion), "\n"))
+		return nil
+	}
+
+	s, err := output.Schema(opt.OutputVersion, opt.Kind)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to create json: %v", err)
+	}
+	fmt.Println(string(data))
+
+	return nil
+}
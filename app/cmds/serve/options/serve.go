@@ -0,0 +1,50 @@
+package options
+
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds"
+)
+
+type ServeOptions struct {
+	cmds.Options
+	// address the http server listens on
+	Address string
+	// number of simulations running at the same time
+	Concurrency int
+	// number of simulations waiting to run, more requests are rejected
+	MaxQueue int
+	// how long the review of a finished simulation is kept
+	JobTTL time.Duration
+}
+
+type ServeConfig struct {
+	Options *ServeOptions
+}
+
+func NewServeOptions() *ServeOptions {
+	return &ServeOptions{
+		Address:     ":8080",
+		Concurrency: 2,
+		MaxQueue:    100,
+		JobTTL:      10 * time.Minute,
+	}
+}
+
+func NewServeConfig(opt *ServeOptions) *ServeConfig {
+	return &ServeConfig{
+		Options: opt,
+	}
+}
+
+func (s *ServeOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.KubeConfig, "kubeconfig", s.KubeConfig, "Path to the kubeconfig file of the cluster watched by the server. By default the in-cluster config")
+	fs.StringVar(&s.Snapshot, "snapshot", s.Snapshot, "Path of a JSON or YAML file of the objects to initialize the world, e.g. the output of kubectl get -o yaml. The cluster is not watched if it's specified")
+	fs.StringVar(&s.SchedulerConfig, "schedulerconfig", s.SchedulerConfig, "Path to JSON or YAML file containing scheduler configuration used by all the simulations. By default the default scheduler configuration")
+	fs.StringVar(&s.Address, "address", s.Address, "Address the HTTP server listens on")
+	fs.IntVar(&s.Concurrency, "concurrency", s.Concurrency, "Number of simulations running at the same time. By default 2")
+	fs.IntVar(&s.MaxQueue, "max-queue", s.MaxQueue, "Number of simulations waiting to run, more requests are rejected with 429. By default 100")
+	fs.DurationVar(&s.JobTTL, "job-ttl", s.JobTTL, "How long the review of a finished simulation can be fetched. By default 10m")
+}
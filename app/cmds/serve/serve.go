@@ -0,0 +1,144 @@
+/*
+Copyright © 2023 k-cloud-labs org
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serve
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/klog/v2"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/serve/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/framework"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/server"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+var serveLong = dedent.Dedent(`
+		serve runs kluster-capacity as an HTTP service. It keeps the objects of the Kubernetes environment specified
+		in KUBECONFIG up to date with informers, or loads them once from --snapshot, and runs the ce, cc and ss
+		simulations submitted to its REST endpoints, each in a fresh world initialized from the cached objects.
+	`)
+
+func NewServeCmd() *cobra.Command {
+	opt := options.NewServeOptions()
+
+	var cmd = &cobra.Command{
+		Use:           "serve --kubeconfig KUBECONFIG",
+		Short:         "serve runs the simulations submitted by http requests",
+		Long:          serveLong,
+		SilenceErrors: false,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flag.Parse()
+
+			err := validate(opt)
+			if err != nil {
+				return err
+			}
+
+			err = run(opt)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.SetNormalizeFunc(cliflag.WordSepNormalizeFunc)
+	flags.AddGoFlagSet(flag.CommandLine)
+	opt.AddFlags(flags)
+
+	return cmd
+}
+
+func validate(opt *options.ServeOptions) error {
+	if len(opt.Address) == 0 {
+		return errors.New("address is missing")
+	}
+
+	if opt.Concurrency < 1 {
+		return errors.New("concurrency must be greater than 0")
+	}
+
+	if opt.MaxQueue < 0 {
+		return errors.New("max queue must not be negative")
+	}
+
+	if opt.JobTTL <= 0 {
+		return errors.New("job ttl must be greater than 0")
+	}
+
+	// make sure the scheduler configuration is valid before serving
+	if _, err := utils.LoadKubeSchedulerConfiguration(opt.SchedulerConfig); err != nil {
+		return fmt.Errorf("invalid schedulerconfig: %v", err)
+	}
+
+	return nil
+}
+
+func run(opt *options.ServeOptions) error {
+	defer klog.Flush()
+	conf := options.NewServeConfig(opt)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	source, err := newObjectSource(ctx, opt)
+	if err != nil {
+		return err
+	}
+
+	return server.NewServer(conf, source).Run(ctx)
+}
+
+func newObjectSource(ctx context.Context, opt *options.ServeOptions) (framework.ObjectSource, error) {
+	if len(opt.Snapshot) > 0 {
+		objs, err := framework.GetInitObjectsFromFile(opt.Snapshot)
+		if err != nil {
+			return nil, err
+		}
+		if len(objs) == 0 {
+			return nil, fmt.Errorf("no objects to init the world in snapshot %s", opt.Snapshot)
+		}
+		return framework.StaticSource(objs), nil
+	}
+
+	kubeConfig, err := utils.BuildRestConfig(opt.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	cache, err := framework.NewClusterCache(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	klog.InfoS("Waiting for the cache of the cluster to be synced")
+	if err := cache.Start(ctx.Done()); err != nil {
+		return nil, err
+	}
+
+	return cache, nil
+}
@@ -0,0 +1,54 @@
+package options
+
+import (
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds"
+)
+
+type UpgradeOptions struct {
+	cmds.Options
+	// label selector of the nodes of the pool to upgrade
+	Pool string
+	// number of nodes added before the old ones are drained in each batch
+	MaxSurge int
+	// number of old nodes drained in each batch in addition to the surge ones
+	MaxUnavailable int
+	// labels of the new nodes, e.g. the version of the node image
+	NodeLabels map[string]string
+}
+
+type UpgradeConfig struct {
+	InitObjs []runtime.Object
+	Options  *UpgradeOptions
+}
+
+func NewUpgradeOptions() *UpgradeOptions {
+	return &UpgradeOptions{
+		MaxSurge: 1,
+	}
+}
+
+func NewUpgradeConfig(opt *UpgradeOptions) *UpgradeConfig {
+	return &UpgradeConfig{
+		Options: opt,
+	}
+}
+
+func (s *UpgradeOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.KubeConfig, "kubeconfig", s.KubeConfig, "Path to the kubeconfig file to use for the analysis")
+	fs.StringVar(&s.SchedulerConfig, "schedulerconfig", s.SchedulerConfig, "Path to JSON or YAML file containing scheduler configuration")
+	fs.StringVar(&s.Snapshot, "snapshot", s.Snapshot, "Path of a JSON or YAML file of the objects to initialize the world, e.g. the output of kubectl get -o yaml. Exclusive with --kubeconfig")
+	fs.StringVar(&s.Pool, "pool", s.Pool, "Label selector of the nodes of the pool to upgrade, e.g. pool=general")
+	fs.IntVar(&s.MaxSurge, "max-surge", s.MaxSurge, "Number of new nodes added before the old ones are drained in each batch. By default 1")
+	fs.IntVar(&s.MaxUnavailable, "max-unavailable", s.MaxUnavailable, "Number of old nodes drained in each batch in addition to the surge ones, they are replaced after being removed. By default 0")
+	fs.StringToStringVar(&s.NodeLabels, "node-labels", s.NodeLabels, "Labels of the new nodes in addition to the ones of the old nodes, e.g. the version of the node image")
+	fs.StringSliceVar(&s.ExcludeNodes, "exclude-nodes", s.ExcludeNodes, "Exclude nodes to be scheduled")
+	fs.StringSliceVar(&s.EnableAdmissionPlugins, "enable-admission-plugins", s.EnableAdmissionPlugins, "Built-in admission plugins called for the pods created besides the default ones. Any of: LimitRanger|ServiceAccount|Priority|PodTolerationRestriction|RuntimeClass. By default LimitRanger, ServiceAccount, Priority and RuntimeClass are called")
+	fs.StringSliceVar(&s.DisableAdmissionPlugins, "disable-admission-plugins", s.DisableAdmissionPlugins, "Built-in admission plugins not called for the pods created. Any of: LimitRanger|ServiceAccount|Priority|PodTolerationRestriction|RuntimeClass")
+	fs.StringVar(&s.AdmissionWebhookConfig, "admission-webhook-config", s.AdmissionWebhookConfig, "Path to a JSON or YAML file of the MutatingWebhookConfigurations called for the pods created, the webhooks must have a url")
+	fs.StringVarP(&s.OutputFormat, "output", "o", s.OutputFormat, "Output format. One of: json|yaml (Note: output is not versioned or guaranteed to be stable across releases)")
+	fs.StringVar(&s.History, "history", s.History, "Directory of the history store to append the result to, the trends of the results are shown by the history command")
+	fs.BoolVar(&s.Verbose, "verbose", s.Verbose, "Verbose mode, show the nodes, the PDB violations and the unschedulable pods of each batch")
+}
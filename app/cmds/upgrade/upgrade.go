@@ -0,0 +1,166 @@
+/*
+Copyright © 2023 k-cloud-labs org
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/klog/v2"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/upgrade/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/admission"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/framework"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/history"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/upgrade"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+var upgradeLong = dedent.Dedent(`
+		upgrade simulates an API server with initial state copied from the Kubernetes environment
+		with its configuration specified in KUBECONFIG, or from a snapshot. The nodes of the pool are
+		replaced batch by batch the same way as a surge upgrade: max-surge new nodes copied from the old
+		ones with the new labels are added, then max-surge + max-unavailable old nodes are cordoned,
+		drained and removed, and the nodes unavailable during the batch are replaced at last. The scheduler
+		settles after each batch, which is checked for the PDBs violated by the evictions and the pods
+		left pending.
+	`)
+
+func NewUpgradeCmd() *cobra.Command {
+	opt := options.NewUpgradeOptions()
+
+	var cmd = &cobra.Command{
+		Use:           "upgrade --kubeconfig KUBECONFIG | --snapshot SNAPSHOT --pool SELECTOR",
+		Aliases:       []string{"up"},
+		Short:         "upgrade is used to simulate the rolling replacement of the nodes of a pool",
+		Long:          upgradeLong,
+		SilenceErrors: false,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flag.Parse()
+
+			err := validate(opt)
+			if err != nil {
+				return err
+			}
+
+			err = run(opt)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.SetNormalizeFunc(cliflag.WordSepNormalizeFunc)
+	flags.AddGoFlagSet(flag.CommandLine)
+	opt.AddFlags(flags)
+
+	return cmd
+}
+
+func validate(opt *options.UpgradeOptions) error {
+	if len(opt.Pool) == 0 {
+		return errors.New("pool is missing")
+	}
+
+	if len(opt.KubeConfig) > 0 && len(opt.Snapshot) > 0 {
+		return errors.New("kubeconfig and snapshot is exclusive")
+	}
+
+	if opt.MaxSurge < 0 || opt.MaxUnavailable < 0 {
+		return errors.New("max-surge and max-unavailable can't be negative")
+	}
+
+	if opt.MaxSurge+opt.MaxUnavailable == 0 {
+		return errors.New("max-surge and max-unavailable can't both be 0")
+	}
+
+	if err := admission.Validate(&opt.Options); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func run(opt *options.UpgradeOptions) error {
+	defer klog.Flush()
+	conf := options.NewUpgradeConfig(opt)
+
+	var err error
+	conf.InitObjs, err = getInitObjects(opt)
+	if err != nil {
+		return err
+	}
+
+	report, err := runSimulator(conf)
+	if err != nil {
+		return err
+	}
+
+	if err := report.Print(conf.Options.Verbose, conf.Options.OutputFormat); err != nil {
+		return fmt.Errorf("error while printing: %v", err)
+	}
+
+	if len(conf.Options.History) > 0 {
+		if err := history.Append(conf.Options.History, history.CommandUpgrade, report); err != nil {
+			return fmt.Errorf("failed to append the result to the history: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// getInitObjects returns the objects of the snapshot or the cluster, the world is always initialized from
+// the objects
+func getInitObjects(opt *options.UpgradeOptions) ([]runtime.Object, error) {
+	if len(opt.Snapshot) > 0 {
+		return framework.GetInitObjectsFromFile(opt.Snapshot)
+	}
+
+	kubeConfig, err := utils.BuildRestConfig(opt.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	return framework.GetInitObjectsFromCluster(kubeConfig)
+}
+
+func runSimulator(conf *options.UpgradeConfig) (pkg.Printer, error) {
+	s, err := upgrade.NewUpgradeSimulatorExecutor(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Initialize(conf.InitObjs...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Report(), nil
+}
@@ -0,0 +1,234 @@
+package options
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// kinds of the workloads which could be scaled
+const (
+	KindDeployment  = "Deployment"
+	KindStatefulSet = "StatefulSet"
+	KindReplicaSet  = "ReplicaSet"
+)
+
+// Step either changes the world or measures it, exactly one of the actions must be specified. The steps
+// changing the world are applied to the running simulation one by one, each of them waits for the scheduler
+// to settle before the next step.
+type Step struct {
+	// optional, shown in the report
+	Name string `json:"name,omitempty"`
+
+	AddNodes        *AddNodes   `json:"addNodes,omitempty"`
+	DrainNodes      *DrainNodes `json:"drainNodes,omitempty"`
+	TaintNodes      *TaintNodes `json:"taintNodes,omitempty"`
+	Scale           *Scale      `json:"scale,omitempty"`
+	DeleteNamespace string      `json:"deleteNamespace,omitempty"`
+
+	// how many replicas of each pod template fit in the world as it is now
+	Estimate *Estimate `json:"estimate,omitempty"`
+	// how many pods can't be scheduled in the world as it is now
+	CountUnschedulable bool `json:"countUnschedulable,omitempty"`
+}
+
+// NodeSelection selects the nodes by names or by a label selector, e.g. topology.kubernetes.io/zone=zone-a
+type NodeSelection struct {
+	Nodes    []string `json:"nodes,omitempty"`
+	Selector string   `json:"selector,omitempty"`
+}
+
+// AddNodes adds nodes copied from an existing node, including its labels, taints, capacity and the
+// DaemonSet pods running on it
+type AddNodes struct {
+	Like  string `json:"like"`
+	Count int    `json:"count"`
+	// labels added to or overriding the ones of the node copied, e.g. to put the nodes in another zone
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// DrainNodes cordons the nodes and evicts the pods on them except the DaemonSet and mirror pods, the pods
+// managed by a controller are recreated to be scheduled again while the others are lost
+type DrainNodes struct {
+	NodeSelection
+	// delete the nodes after they are drained, as if they were scaled down
+	Delete bool `json:"delete,omitempty"`
+}
+
+// TaintNodes adds the taint to the nodes, the pods not tolerating a NoExecute taint are evicted
+type TaintNodes struct {
+	NodeSelection
+	Taint corev1.Taint `json:"taint"`
+}
+
+// Scale changes the replicas of a workload, the pods are created from its pod template or deleted
+type Scale struct {
+	// Deployment, StatefulSet or ReplicaSet
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Replicas  int32  `json:"replicas"`
+}
+
+type Estimate struct {
+	PodsFromTemplate []string `json:"podsFromTemplate"`
+	MaxLimit         int      `json:"maxLimit,omitempty"`
+}
+
+// LoadSteps reads the steps from a JSON or YAML file which is a list of steps, unknown fields are rejected
+// so that a misspelled option isn't ignored silently
+func LoadSteps(path string) ([]Step, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err = yaml.ToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []Step
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&steps); err != nil {
+		return nil, fmt.Errorf("failed to decode the steps: %v", err)
+	}
+
+	return steps, ValidateSteps(steps)
+}
+
+func ValidateSteps(steps []Step) error {
+	if len(steps) == 0 {
+		return errors.New("at least one step must be specified")
+	}
+
+	for i := range steps {
+		if err := steps[i].validate(); err != nil {
+			return fmt.Errorf("invalid step %d: %v", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// Mutating tells whether the step changes the world
+func (s *Step) Mutating() bool {
+	return s.Estimate == nil && !s.CountUnschedulable
+}
+
+func (s *Step) validate() error {
+	count := 0
+	for _, specified := range []bool{s.AddNodes != nil, s.DrainNodes != nil, s.TaintNodes != nil, s.Scale != nil,
+		len(s.DeleteNamespace) > 0, s.Estimate != nil, s.CountUnschedulable} {
+		if specified {
+			count++
+		}
+	}
+	if count != 1 {
+		return errors.New("exactly one of addNodes, drainNodes, taintNodes, scale, deleteNamespace, estimate and countUnschedulable must be specified")
+	}
+
+	switch {
+	case s.AddNodes != nil:
+		if len(s.AddNodes.Like) == 0 {
+			return errors.New("the node to copy is missing")
+		}
+		if s.AddNodes.Count < 1 {
+			return errors.New("count must be greater than 0")
+		}
+	case s.DrainNodes != nil:
+		return s.DrainNodes.NodeSelection.validate()
+	case s.TaintNodes != nil:
+		if len(s.TaintNodes.Taint.Key) == 0 {
+			return errors.New("key of the taint is missing")
+		}
+		switch s.TaintNodes.Taint.Effect {
+		case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		default:
+			return fmt.Errorf("effect %q of the taint not recognized", s.TaintNodes.Taint.Effect)
+		}
+		return s.TaintNodes.NodeSelection.validate()
+	case s.Scale != nil:
+		switch s.Scale.Kind {
+		case KindDeployment, KindStatefulSet, KindReplicaSet:
+		default:
+			return fmt.Errorf("kind %q can't be scaled", s.Scale.Kind)
+		}
+		if len(s.Scale.Name) == 0 {
+			return errors.New("name of the workload is missing")
+		}
+		if s.Scale.Replicas < 0 {
+			return errors.New("replicas must not be negative")
+		}
+	case s.Estimate != nil:
+		if len(s.Estimate.PodsFromTemplate) == 0 {
+			return errors.New("pod template file is missing")
+		}
+	}
+
+	return nil
+}
+
+func (s *NodeSelection) validate() error {
+	if len(s.Nodes) == 0 && len(s.Selector) == 0 {
+		return errors.New("nodes and selector both is missing")
+	}
+	if len(s.Nodes) > 0 && len(s.Selector) > 0 {
+		return errors.New("nodes and selector is exclusive")
+	}
+	if len(s.Selector) > 0 {
+		if _, err := labels.Parse(s.Selector); err != nil {
+			return fmt.Errorf("invalid selector: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *NodeSelection) String() string {
+	if len(s.Selector) > 0 {
+		return "nodes " + s.Selector
+	}
+	return strings.Join(s.Nodes, ",")
+}
+
+// String describes the step, e.g. add 3 nodes like node-1
+func (s *Step) String() string {
+	switch {
+	case s.AddNodes != nil:
+		return fmt.Sprintf("add %d nodes like %s", s.AddNodes.Count, s.AddNodes.Like)
+	case s.DrainNodes != nil:
+		if s.DrainNodes.Delete {
+			return fmt.Sprintf("drain and delete %s", s.DrainNodes.NodeSelection.String())
+		}
+		return fmt.Sprintf("drain %s", s.DrainNodes.NodeSelection.String())
+	case s.TaintNodes != nil:
+		return fmt.Sprintf("taint %s with %s", s.TaintNodes.NodeSelection.String(), s.TaintNodes.Taint.ToString())
+	case s.Scale != nil:
+		return fmt.Sprintf("scale %s %s to %d", strings.ToLower(s.Scale.Kind), s.Scale.key(), s.Scale.Replicas)
+	case len(s.DeleteNamespace) > 0:
+		return fmt.Sprintf("delete namespace %s", s.DeleteNamespace)
+	case s.Estimate != nil:
+		return fmt.Sprintf("estimate %s", strings.Join(s.Estimate.PodsFromTemplate, ","))
+	default:
+		return "count unschedulable"
+	}
+}
+
+func (s *Scale) namespace() string {
+	if len(s.Namespace) == 0 {
+		return corev1.NamespaceDefault
+	}
+	return s.Namespace
+}
+
+func (s *Scale) key() string {
+	return s.namespace() + "/" + s.Name
+}
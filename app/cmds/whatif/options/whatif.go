@@ -0,0 +1,54 @@
+package options
+
+import (
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds"
+)
+
+type WhatIfOptions struct {
+	cmds.Options
+	// path to the JSON or YAML file of the steps
+	Steps string
+}
+
+type WhatIfConfig struct {
+	Steps    []Step
+	InitObjs []runtime.Object
+	Options  *WhatIfOptions
+}
+
+func NewWhatIfOptions() *WhatIfOptions {
+	return &WhatIfOptions{}
+}
+
+func NewWhatIfConfig(opt *WhatIfOptions) *WhatIfConfig {
+	return &WhatIfConfig{
+		Options: opt,
+	}
+}
+
+func (s *WhatIfOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.KubeConfig, "kubeconfig", s.KubeConfig, "Path to the kubeconfig file to use for the analysis")
+	fs.StringVar(&s.SchedulerConfig, "schedulerconfig", s.SchedulerConfig, "Path to JSON or YAML file containing scheduler configuration")
+	fs.StringVar(&s.Snapshot, "snapshot", s.Snapshot, "Path of a JSON or YAML file of the objects to initialize the world, e.g. the output of kubectl get -o yaml. Exclusive with --kubeconfig")
+	fs.StringVar(&s.Steps, "steps", s.Steps, "Path to JSON or YAML file containing the list of steps applied to the world and the measurements between them")
+	fs.StringSliceVar(&s.ExcludeNodes, "exclude-nodes", s.ExcludeNodes, "Exclude nodes to be scheduled")
+	fs.StringSliceVar(&s.EnableAdmissionPlugins, "enable-admission-plugins", s.EnableAdmissionPlugins, "Built-in admission plugins called for the pods created besides the default ones. Any of: LimitRanger|ServiceAccount|Priority|PodTolerationRestriction|RuntimeClass. By default LimitRanger, ServiceAccount, Priority and RuntimeClass are called")
+	fs.StringSliceVar(&s.DisableAdmissionPlugins, "disable-admission-plugins", s.DisableAdmissionPlugins, "Built-in admission plugins not called for the pods created. Any of: LimitRanger|ServiceAccount|Priority|PodTolerationRestriction|RuntimeClass")
+	fs.StringVar(&s.AdmissionWebhookConfig, "admission-webhook-config", s.AdmissionWebhookConfig, "Path to a JSON or YAML file of the MutatingWebhookConfigurations called for the pods created, the webhooks must have a url")
+	fs.StringVarP(&s.OutputFormat, "output", "o", s.OutputFormat, "Output format. One of: json|yaml (Note: output is not versioned or guaranteed to be stable across releases)")
+	fs.StringVar(&s.History, "history", s.History, "Directory of the history store to append the result to, the trends of the results are shown by the history command")
+	fs.BoolVar(&s.Verbose, "verbose", s.Verbose, "Verbose mode, show the nodes changed and the unschedulable pods of each step")
+}
+
+func (s *WhatIfConfig) ParseAPISpec() error {
+	steps, err := LoadSteps(s.Options.Steps)
+	if err != nil {
+		return err
+	}
+	s.Steps = steps
+
+	return nil
+}
@@ -0,0 +1,161 @@
+/*
+Copyright © 2023 k-cloud-labs org
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package whatif
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/lithammer/dedent"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/klog/v2"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/whatif/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/admission"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/framework"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/history"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/whatif"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+var whatIfLong = dedent.Dedent(`
+		whatif simulates an API server with initial state copied from the Kubernetes environment
+		with its configuration specified in KUBECONFIG, or from a snapshot. The steps are applied to the
+		simulated world one by one, e.g. adding nodes like an existing one, draining or tainting nodes,
+		scaling workloads and deleting namespaces, and the scheduler settles after each of them. The
+		measurements between the steps, i.e. how many replicas of a pod template fit or how many pods
+		can't be scheduled, show how the capacity changes along the way.
+	`)
+
+func NewWhatIfCmd() *cobra.Command {
+	opt := options.NewWhatIfOptions()
+
+	var cmd = &cobra.Command{
+		Use:           "whatif --kubeconfig KUBECONFIG | --snapshot SNAPSHOT --steps STEPS",
+		Aliases:       []string{"wi"},
+		Short:         "whatif is used to apply a sequence of changes to the cluster and measure the capacity between them",
+		Long:          whatIfLong,
+		SilenceErrors: false,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flag.Parse()
+
+			err := validate(opt)
+			if err != nil {
+				return err
+			}
+
+			err = run(opt)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.SetNormalizeFunc(cliflag.WordSepNormalizeFunc)
+	flags.AddGoFlagSet(flag.CommandLine)
+	opt.AddFlags(flags)
+
+	return cmd
+}
+
+func validate(opt *options.WhatIfOptions) error {
+	if len(opt.Steps) == 0 {
+		return errors.New("steps is missing")
+	}
+
+	if len(opt.KubeConfig) > 0 && len(opt.Snapshot) > 0 {
+		return errors.New("kubeconfig and snapshot is exclusive")
+	}
+
+	if err := admission.Validate(&opt.Options); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func run(opt *options.WhatIfOptions) error {
+	defer klog.Flush()
+	conf := options.NewWhatIfConfig(opt)
+
+	err := conf.ParseAPISpec()
+	if err != nil {
+		return fmt.Errorf("failed to parse steps: %v", err)
+	}
+
+	conf.InitObjs, err = getInitObjects(opt)
+	if err != nil {
+		return err
+	}
+
+	report, err := runSimulator(conf)
+	if err != nil {
+		return err
+	}
+
+	if err := report.Print(conf.Options.Verbose, conf.Options.OutputFormat); err != nil {
+		return fmt.Errorf("error while printing: %v", err)
+	}
+
+	if len(conf.Options.History) > 0 {
+		if err := history.Append(conf.Options.History, history.CommandWhatIf, report); err != nil {
+			return fmt.Errorf("failed to append the result to the history: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// getInitObjects returns the objects of the snapshot or the cluster, the world is always initialized from
+// the objects so that the estimate steps could copy it
+func getInitObjects(opt *options.WhatIfOptions) ([]runtime.Object, error) {
+	if len(opt.Snapshot) > 0 {
+		return framework.GetInitObjectsFromFile(opt.Snapshot)
+	}
+
+	kubeConfig, err := utils.BuildRestConfig(opt.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	return framework.GetInitObjectsFromCluster(kubeConfig)
+}
+
+func runSimulator(conf *options.WhatIfConfig) (pkg.Printer, error) {
+	s, err := whatif.NewWhatIfSimulatorExecutor(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Initialize(conf.InitObjs...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Report(), nil
+}
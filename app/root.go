@@ -25,7 +25,20 @@ import (
 
 	"github.com/k-cloud-labs/kluster-capacity/app/cmds/capacityestimation"
 	"github.com/k-cloud-labs/kluster-capacity/app/cmds/clustercompression"
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/controller"
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/convert"
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/diff"
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/explain"
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/exporter"
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/history"
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/hpaburst"
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/resilience"
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/run"
 	"github.com/k-cloud-labs/kluster-capacity/app/cmds/schedulersimulation"
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/schema"
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/serve"
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/upgrade"
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/whatif"
 	"github.com/k-cloud-labs/kluster-capacity/pkg/version/sharedcommand"
 )
 
@@ -66,6 +79,19 @@ func init() {
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 
 	rootCmd.AddCommand(capacityestimation.NewCapacityEstimationCmd(), schedulersimulation.NewSchedulerSimulationCmd(), clustercompression.NewClusterCompressionCmd())
+	rootCmd.AddCommand(hpaburst.NewHPABurstCmd())
+	rootCmd.AddCommand(explain.NewExplainCmd())
+	rootCmd.AddCommand(serve.NewServeCmd())
+	rootCmd.AddCommand(controller.NewControllerCmd())
+	rootCmd.AddCommand(exporter.NewExporterCmd())
+	rootCmd.AddCommand(history.NewHistoryCmd())
+	rootCmd.AddCommand(diff.NewDiffCmd())
+	rootCmd.AddCommand(convert.NewConvertCmd())
+	rootCmd.AddCommand(schema.NewSchemaCmd())
+	rootCmd.AddCommand(run.NewRunCmd())
+	rootCmd.AddCommand(whatif.NewWhatIfCmd())
+	rootCmd.AddCommand(resilience.NewResilienceCmd())
+	rootCmd.AddCommand(upgrade.NewUpgradeCmd())
 	rootCmd.AddCommand(sharedcommand.NewCmdVersion(os.Stdout, "kluster-capacity"))
 }
 
@@ -0,0 +1,178 @@
+// cebench compares the capacity estimation with different batch sizes on a synthetic world.
+// Batch size 1 is the single-pod path, which creates the next pod only after the previous
+// one is bound, and is always run first as the baseline. Every other batch size is compared
+// against it: the tool fails if the replicas differ and prints the speedup otherwise, e.g.
+//
+//	go run ./hack/tools/cebench --nodes 5000 --batch-sizes 100,500
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/klog/v2"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/capacityestimation/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/capacityestimation"
+)
+
+var (
+	nodes      = flag.Int("nodes", 5000, "Number of nodes of the synthetic world")
+	nodeCPU    = flag.String("node-cpu", "32", "Allocatable cpu of each node")
+	nodeMemory = flag.String("node-memory", "128Gi", "Allocatable memory of each node")
+	podCPU     = flag.String("pod-cpu", "1", "Cpu request of the simulated pod")
+	podMemory  = flag.String("pod-memory", "1Gi", "Memory request of the simulated pod")
+	maxLimit   = flag.Int("max-limit", 0, "Number of instances of pod to be scheduled after which analysis stops. By default unlimited")
+	batchSizes = flag.String("batch-sizes", "100", "Comma separated batch sizes to compare against the single-pod path (batch size 1)")
+)
+
+func main() {
+	klog.InitFlags(nil)
+	flag.Parse()
+
+	sizes := []int{1}
+	for _, s := range strings.Split(*batchSizes, ",") {
+		size, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			log.Fatalf("invalid batch size %q: %v", s, err)
+		}
+		if size == 1 {
+			continue
+		}
+		sizes = append(sizes, size)
+	}
+
+	// the scheduler config requires a kubeconfig even though the world is synthetic
+	kubeConfig, err := writeKubeConfig()
+	if err != nil {
+		log.Fatalf("failed to write kubeconfig: %v", err)
+	}
+	defer os.RemoveAll(filepath.Dir(kubeConfig))
+
+	var baselineReplicas int32
+	var baselineDuration time.Duration
+	fmt.Printf("%-12s%-12s%-16s%-10s%s\n", "BATCH SIZE", "REPLICAS", "DURATION", "SPEEDUP", "STOP REASON")
+	for _, size := range sizes {
+		start := time.Now()
+		review, err := estimate(kubeConfig, size)
+		if err != nil {
+			log.Fatalf("failed to estimate with batch size %d: %v", size, err)
+		}
+		duration := time.Since(start)
+		if size == 1 {
+			baselineReplicas, baselineDuration = review.Status.Replicas, duration
+		}
+		fmt.Printf("%-12d%-12d%-16s%-10s%s\n", size, review.Status.Replicas, duration.Round(time.Millisecond),
+			fmt.Sprintf("%.2fx", baselineDuration.Seconds()/duration.Seconds()), review.Status.StopReason.StopType)
+		if review.Status.Replicas != baselineReplicas {
+			log.Fatalf("batch size %d estimated %d replicas, but the single-pod path estimated %d", size, review.Status.Replicas, baselineReplicas)
+		}
+	}
+}
+
+func estimate(kubeConfig string, batchSize int) (*capacityestimation.CapacityEstimationReview, error) {
+	opt := options.NewCapacityEstimationOptions()
+	opt.KubeConfig = kubeConfig
+	opt.MaxLimit = *maxLimit
+	opt.BatchSize = batchSize
+
+	conf := options.NewCapacityEstimationConfig(opt)
+	conf.Pods = []*corev1.Pod{newPod()}
+	conf.InitObjs = newWorld()
+
+	s, err := capacityestimation.NewCESimulatorExecutor(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Initialize(conf.InitObjs...); err != nil {
+		return nil, err
+	}
+
+	if err := s.Run(); err != nil {
+		return nil, err
+	}
+
+	return s.Report().(capacityestimation.CapacityEstimationReviews)[0], nil
+}
+
+func newWorld() []runtime.Object {
+	objs := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: metav1.NamespaceDefault}},
+	}
+
+	allocatable := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(*nodeCPU),
+		corev1.ResourceMemory: resource.MustParse(*nodeMemory),
+		corev1.ResourcePods:   resource.MustParse("110"),
+	}
+	for i := 0; i < *nodes; i++ {
+		name := fmt.Sprintf("node-%d", i)
+		objs = append(objs, &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{corev1.LabelHostname: name},
+			},
+			Status: corev1.NodeStatus{
+				Capacity:    allocatable,
+				Allocatable: allocatable,
+				Conditions: []corev1.NodeCondition{
+					{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				},
+			},
+		})
+	}
+
+	return objs
+}
+
+func newPod() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cebench",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  "cebench",
+					Image: "nginx",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse(*podCPU),
+							corev1.ResourceMemory: resource.MustParse(*podMemory),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func writeKubeConfig() (string, error) {
+	dir, err := os.MkdirTemp("", "cebench")
+	if err != nil {
+		return "", err
+	}
+
+	config := clientcmdapi.NewConfig()
+	config.Clusters["cebench"] = &clientcmdapi.Cluster{Server: "https://127.0.0.1:6443"}
+	config.AuthInfos["cebench"] = &clientcmdapi.AuthInfo{}
+	config.Contexts["cebench"] = &clientcmdapi.Context{Cluster: "cebench", AuthInfo: "cebench"}
+	config.CurrentContext = "cebench"
+
+	file := filepath.Join(dir, "kubeconfig")
+	return file, clientcmd.WriteToFile(*config, file)
+}
@@ -0,0 +1,128 @@
+package admission
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds"
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+)
+
+// the built-in plugins, named after the admission plugins of the apiserver they emulate
+const (
+	LimitRanger              = "LimitRanger"
+	ServiceAccount           = "ServiceAccount"
+	Priority                 = "Priority"
+	PodTolerationRestriction = "PodTolerationRestriction"
+	RuntimeClass             = "RuntimeClass"
+)
+
+// Plugins are the built-in plugins in the order they are called, which is the same as the one of the apiserver
+var Plugins = []string{LimitRanger, ServiceAccount, Priority, PodTolerationRestriction, RuntimeClass}
+
+// DefaultPlugins are the built-in plugins enabled by default, PodTolerationRestriction is off by default in the
+// apiserver as well
+var DefaultPlugins = []string{LimitRanger, ServiceAccount, Priority, RuntimeClass}
+
+var plugins = map[string]admitFunc{
+	LimitRanger:              admitLimitRanger,
+	ServiceAccount:           admitServiceAccount,
+	Priority:                 admitPriority,
+	PodTolerationRestriction: admitPodTolerationRestriction,
+	RuntimeClass:             admitRuntimeClass,
+}
+
+// Lister gets the objects of the world the admission depends on, testing.ObjectTracker implements it
+type Lister interface {
+	Get(gvr schema.GroupVersionResource, ns, name string) (runtime.Object, error)
+	List(gvr schema.GroupVersionResource, gvk schema.GroupVersionKind, ns string) (runtime.Object, error)
+}
+
+type admitFunc func(pod *corev1.Pod, lister Lister) error
+
+// Chain emulates the admission of the apiserver for the pods created by the simulations, so that they are
+// scheduled as they would be in the cluster: the built-in plugins enabled are called first and then the
+// mutating webhooks configured.
+type Chain struct {
+	plugins  []admitFunc
+	webhooks []*webhook
+}
+
+// New creates the chain of the admission options, the default plugins and the ones enabled are called unless
+// disabled
+func New(opt *cmds.Options) (*Chain, error) {
+	enabled := make(map[string]bool)
+	for _, name := range DefaultPlugins {
+		enabled[name] = true
+	}
+	for _, name := range opt.EnableAdmissionPlugins {
+		if _, ok := plugins[name]; !ok {
+			return nil, fmt.Errorf("admission plugin %q not recognized, must be one of %s", name, strings.Join(Plugins, "|"))
+		}
+		enabled[name] = true
+	}
+	for _, name := range opt.DisableAdmissionPlugins {
+		if _, ok := plugins[name]; !ok {
+			return nil, fmt.Errorf("admission plugin %q not recognized, must be one of %s", name, strings.Join(Plugins, "|"))
+		}
+		for _, e := range opt.EnableAdmissionPlugins {
+			if e == name {
+				return nil, fmt.Errorf("admission plugin %q is both enabled and disabled", name)
+			}
+		}
+		enabled[name] = false
+	}
+
+	c := &Chain{}
+	for _, name := range Plugins {
+		if enabled[name] {
+			c.plugins = append(c.plugins, plugins[name])
+		}
+	}
+
+	if len(opt.AdmissionWebhookConfig) > 0 {
+		webhooks, err := loadWebhooks(opt.AdmissionWebhookConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load admission webhook config %s: %v", opt.AdmissionWebhookConfig, err)
+		}
+		c.webhooks = webhooks
+	}
+
+	return c, nil
+}
+
+// Validate checks the admission options
+func Validate(opt *cmds.Options) error {
+	_, err := New(opt)
+	return err
+}
+
+// Admit mutates the pod as the apiserver would when it's created, the objects it depends on are got from the
+// lister. A pod is admitted only once, so the pods created from an admitted template are left as they are.
+func (c *Chain) Admit(pod *corev1.Pod, lister Lister) error {
+	if _, ok := pod.Annotations[pkg.PodAdmitted]; ok {
+		return nil
+	}
+
+	for _, admit := range c.plugins {
+		if err := admit(pod, lister); err != nil {
+			return err
+		}
+	}
+	for _, w := range c.webhooks {
+		if err := w.admit(pod, lister); err != nil {
+			return err
+		}
+	}
+
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[pkg.PodAdmitted] = "true"
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
+	schedulingapiv1 "k8s.io/kubernetes/pkg/apis/scheduling/v1"
+
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+const (
+	// defaultServiceAccountName is the service account the pods without one run as
+	defaultServiceAccountName = "default"
+	// namespaceDefaultTolerations is the annotation of the namespace with the tolerations added to its pods
+	namespaceDefaultTolerations = "scheduler.alpha.kubernetes.io/defaultTolerations"
+)
+
+// admitLimitRanger sets the default requests and limits of the containers from the LimitRanges of the namespace
+func admitLimitRanger(pod *corev1.Pod, lister Lister) error {
+	obj, err := lister.List(corev1.SchemeGroupVersion.WithResource("limitranges"), corev1.SchemeGroupVersion.WithKind("LimitRange"), pod.Namespace)
+	if err != nil {
+		return err
+	}
+
+	var limitRanges []corev1.LimitRange
+	for _, limitRange := range obj.(*corev1.LimitRangeList).Items {
+		// the default request defaults to the default limit, which the apiserver does when the LimitRange is
+		// created, but a snapshot may not have it
+		limitRange := limitRange.DeepCopy()
+		for i := range limitRange.Spec.Limits {
+			limit := &limitRange.Spec.Limits[i]
+			for name, quantity := range limit.Default {
+				if _, ok := limit.DefaultRequest[name]; ok {
+					continue
+				}
+				if limit.DefaultRequest == nil {
+					limit.DefaultRequest = corev1.ResourceList{}
+				}
+				limit.DefaultRequest[name] = quantity.DeepCopy()
+			}
+		}
+		limitRanges = append(limitRanges, *limitRange)
+	}
+
+	if err := utils.ApplyLimitRanges(pod, limitRanges); err != nil {
+		return fmt.Errorf("unable to apply limit ranges to pod %s/%s: %v", pod.Namespace, pod.Name, err)
+	}
+
+	return nil
+}
+
+// admitServiceAccount sets the default service account. The token volume isn't mounted since it doesn't affect
+// the scheduling, and the service account is not required to exist.
+func admitServiceAccount(pod *corev1.Pod, _ Lister) error {
+	if len(pod.Spec.ServiceAccountName) == 0 {
+		pod.Spec.ServiceAccountName = defaultServiceAccountName
+	}
+	if len(pod.Spec.DeprecatedServiceAccount) == 0 {
+		pod.Spec.DeprecatedServiceAccount = pod.Spec.ServiceAccountName
+	}
+
+	return nil
+}
+
+// admitPriority resolves the priority and the preemption policy from the PriorityClass of the pod, or from the
+// global default one if the pod has none. The pods with the priority already resolved are left as they are.
+func admitPriority(pod *corev1.Pod, lister Lister) error {
+	if pod.Spec.Priority != nil {
+		return nil
+	}
+
+	obj, err := lister.List(schedulingv1.SchemeGroupVersion.WithResource("priorityclasses"), schedulingv1.SchemeGroupVersion.WithKind("PriorityClass"), "")
+	if err != nil {
+		return err
+	}
+	priorityClasses := obj.(*schedulingv1.PriorityClassList).Items
+
+	var priorityClass *schedulingv1.PriorityClass
+	if len(pod.Spec.PriorityClassName) == 0 {
+		// the one with the lowest value if there are more than one global default
+		for i := range priorityClasses {
+			if priorityClasses[i].GlobalDefault && (priorityClass == nil || priorityClass.Value > priorityClasses[i].Value) {
+				priorityClass = &priorityClasses[i]
+			}
+		}
+	} else {
+		for i := range priorityClasses {
+			if priorityClasses[i].Name == pod.Spec.PriorityClassName {
+				priorityClass = &priorityClasses[i]
+				break
+			}
+		}
+		// the system ones are created by the apiserver, a snapshot may not have them
+		for _, systemPriorityClass := range schedulingapiv1.SystemPriorityClasses() {
+			if priorityClass == nil && systemPriorityClass.Name == pod.Spec.PriorityClassName {
+				priorityClass = systemPriorityClass
+			}
+		}
+		if priorityClass == nil {
+			return fmt.Errorf("pod %s/%s rejected: no PriorityClass with name %s was found", pod.Namespace, pod.Name, pod.Spec.PriorityClassName)
+		}
+	}
+
+	var priority int32
+	if priorityClass != nil {
+		pod.Spec.PriorityClassName = priorityClass.Name
+		priority = priorityClass.Value
+		if pod.Spec.PreemptionPolicy == nil && priorityClass.PreemptionPolicy != nil {
+			preemptionPolicy := *priorityClass.PreemptionPolicy
+			pod.Spec.PreemptionPolicy = &preemptionPolicy
+		}
+	}
+	pod.Spec.Priority = &priority
+
+	return nil
+}
+
+// admitPodTolerationRestriction adds the default tolerations of the namespace, and the toleration of the memory
+// pressure of the nodes if the pod is not BestEffort
+func admitPodTolerationRestriction(pod *corev1.Pod, lister Lister) error {
+	var extraTolerations []corev1.Toleration
+
+	obj, err := lister.Get(corev1.SchemeGroupVersion.WithResource("namespaces"), "", pod.Namespace)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if namespace, ok := obj.(*corev1.Namespace); ok && len(namespace.Annotations[namespaceDefaultTolerations]) > 0 {
+		if err := json.Unmarshal([]byte(namespace.Annotations[namespaceDefaultTolerations]), &extraTolerations); err != nil {
+			return fmt.Errorf("invalid default tolerations of namespace %s: %v", namespace.Name, err)
+		}
+	}
+
+	if qos.GetPodQOS(pod) != corev1.PodQOSBestEffort {
+		extraTolerations = append(extraTolerations, corev1.Toleration{
+			Key:      corev1.TaintNodeMemoryPressure,
+			Operator: corev1.TolerationOpExists,
+			Effect:   corev1.TaintEffectNoSchedule,
+		})
+	}
+	pod.Spec.Tolerations = mergeTolerations(pod.Spec.Tolerations, extraTolerations)
+
+	return nil
+}
+
+// admitRuntimeClass sets the overhead of the pod and merges the scheduling constraints from its RuntimeClass
+func admitRuntimeClass(pod *corev1.Pod, lister Lister) error {
+	if pod.Spec.RuntimeClassName == nil {
+		return nil
+	}
+
+	obj, err := lister.Get(nodev1.SchemeGroupVersion.WithResource("runtimeclasses"), "", *pod.Spec.RuntimeClassName)
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("pod %s/%s rejected: RuntimeClass %s not found", pod.Namespace, pod.Name, *pod.Spec.RuntimeClassName)
+	}
+	if err != nil {
+		return err
+	}
+	runtimeClass := obj.(*nodev1.RuntimeClass)
+
+	if runtimeClass.Overhead != nil {
+		if pod.Spec.Overhead != nil && !apiequality.Semantic.DeepEqual(pod.Spec.Overhead, runtimeClass.Overhead.PodFixed) {
+			return fmt.Errorf("pod %s/%s rejected: Pod's Overhead doesn't match RuntimeClass's defined Overhead", pod.Namespace, pod.Name)
+		}
+		pod.Spec.Overhead = runtimeClass.Overhead.PodFixed.DeepCopy()
+	}
+
+	if runtimeClass.Scheduling != nil {
+		for key, value := range runtimeClass.Scheduling.NodeSelector {
+			if podValue, ok := pod.Spec.NodeSelector[key]; ok && podValue != value {
+				return fmt.Errorf("pod %s/%s rejected: conflict: runtimeClass.scheduling.nodeSelector[%s] = %s; pod.spec.nodeSelector[%s] = %s", pod.Namespace, pod.Name, key, value, key, podValue)
+			}
+			if pod.Spec.NodeSelector == nil {
+				pod.Spec.NodeSelector = map[string]string{}
+			}
+			pod.Spec.NodeSelector[key] = value
+		}
+		pod.Spec.Tolerations = mergeTolerations(pod.Spec.Tolerations, runtimeClass.Scheduling.Tolerations)
+	}
+
+	return nil
+}
+
+// mergeTolerations appends the tolerations of second which are not in first
+func mergeTolerations(first, second []corev1.Toleration) []corev1.Toleration {
+	result := first
+	for i := range second {
+		found := false
+		for j := range result {
+			if result[j].MatchToleration(&second[i]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, second[i])
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,310 @@
+package admission
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	uuid "github.com/satori/go.uuid"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/klog/v2"
+
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+)
+
+// defaultWebhookTimeout is the timeout of the webhooks without one, the same as the one of the apiserver
+const defaultWebhookTimeout = 10 * time.Second
+
+// webhook is a mutating webhook called at its url, the services of the cluster can't be reached by the simulation
+type webhook struct {
+	admissionregistrationv1.MutatingWebhook
+	namespaceSelector labels.Selector
+	objectSelector    labels.Selector
+	client            *http.Client
+}
+
+// loadWebhooks reads the MutatingWebhookConfigurations from a JSON or YAML file, which may contain several
+// documents and lists. The webhooks are called in the order of the names of their configurations.
+func loadWebhooks(path string) ([]*webhook, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var configurations []admissionregistrationv1.MutatingWebhookConfiguration
+	decoder := yaml.NewYAMLOrJSONDecoder(file, 4096)
+	for {
+		u := &unstructured.Unstructured{}
+		if err := decoder.Decode(&u.Object); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		if len(u.Object) == 0 {
+			continue
+		}
+
+		items := []unstructured.Unstructured{*u}
+		if u.IsList() {
+			list, err := u.ToList()
+			if err != nil {
+				return nil, err
+			}
+			items = list.Items
+		}
+		for i := range items {
+			if items[i].GetKind() != "MutatingWebhookConfiguration" {
+				return nil, fmt.Errorf("kind %s not supported, must be MutatingWebhookConfiguration", items[i].GetKind())
+			}
+			configuration := admissionregistrationv1.MutatingWebhookConfiguration{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(items[i].UnstructuredContent(), &configuration); err != nil {
+				return nil, err
+			}
+			configurations = append(configurations, configuration)
+		}
+	}
+	sort.SliceStable(configurations, func(i, j int) bool {
+		return configurations[i].Name < configurations[j].Name
+	})
+
+	var webhooks []*webhook
+	for _, configuration := range configurations {
+		for _, mutatingWebhook := range configuration.Webhooks {
+			w, err := newWebhook(mutatingWebhook)
+			if err != nil {
+				return nil, fmt.Errorf("webhook %s of %s: %v", mutatingWebhook.Name, configuration.Name, err)
+			}
+			webhooks = append(webhooks, w)
+		}
+	}
+
+	return webhooks, nil
+}
+
+func newWebhook(mutatingWebhook admissionregistrationv1.MutatingWebhook) (*webhook, error) {
+	if mutatingWebhook.ClientConfig.URL == nil {
+		return nil, errors.New("only the webhooks with a url are supported")
+	}
+
+	supported := false
+	for _, version := range mutatingWebhook.AdmissionReviewVersions {
+		if version == admissionv1.SchemeGroupVersion.Version {
+			supported = true
+		}
+	}
+	if !supported {
+		return nil, fmt.Errorf("admission review version %s must be supported", admissionv1.SchemeGroupVersion.Version)
+	}
+
+	w := &webhook{
+		MutatingWebhook:   mutatingWebhook,
+		namespaceSelector: labels.Everything(),
+		objectSelector:    labels.Everything(),
+	}
+	var err error
+	if mutatingWebhook.NamespaceSelector != nil {
+		if w.namespaceSelector, err = metav1.LabelSelectorAsSelector(mutatingWebhook.NamespaceSelector); err != nil {
+			return nil, err
+		}
+	}
+	if mutatingWebhook.ObjectSelector != nil {
+		if w.objectSelector, err = metav1.LabelSelectorAsSelector(mutatingWebhook.ObjectSelector); err != nil {
+			return nil, err
+		}
+	}
+
+	timeout := defaultWebhookTimeout
+	if mutatingWebhook.TimeoutSeconds != nil {
+		timeout = time.Duration(*mutatingWebhook.TimeoutSeconds) * time.Second
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if len(mutatingWebhook.ClientConfig.CABundle) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(mutatingWebhook.ClientConfig.CABundle) {
+			return nil, errors.New("no certificate found in the ca bundle")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+	w.client = &http.Client{Transport: transport, Timeout: timeout}
+
+	return w, nil
+}
+
+// admit calls the webhook if it matches the creation of the pod, the errors of calling it are ignored if its
+// failure policy is Ignore
+func (w *webhook) admit(pod *corev1.Pod, lister Lister) error {
+	matched, err := w.matches(pod, lister)
+	if err != nil || !matched {
+		return err
+	}
+
+	err = w.call(pod)
+	var rejected *rejectedError
+	if err != nil && !errors.As(err, &rejected) && w.FailurePolicy != nil && *w.FailurePolicy == admissionregistrationv1.Ignore {
+		klog.ErrorS(err, "Failed calling webhook, failing open", "webhook", w.Name, "pod", klog.KObj(pod))
+		return nil
+	}
+
+	return err
+}
+
+// matches checks the rules and the selectors of the webhook against the creation of the pod
+func (w *webhook) matches(pod *corev1.Pod, lister Lister) (bool, error) {
+	matched := false
+	for _, rule := range w.Rules {
+		if contains(rule.Operations, admissionregistrationv1.Create, admissionregistrationv1.OperationAll) &&
+			contains(rule.APIGroups, corev1.GroupName, "*") &&
+			contains(rule.APIVersions, corev1.SchemeGroupVersion.Version, "*") &&
+			contains(rule.Resources, "pods", "*", "*/*") {
+			matched = true
+			break
+		}
+	}
+	if !matched || !w.objectSelector.Matches(labels.Set(pod.Labels)) {
+		return false, nil
+	}
+
+	if w.namespaceSelector.Empty() {
+		return true, nil
+	}
+	namespaceLabels := labels.Set{corev1.LabelMetadataName: pod.Namespace}
+	obj, err := lister.Get(corev1.SchemeGroupVersion.WithResource("namespaces"), "", pod.Namespace)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, err
+	}
+	if namespace, ok := obj.(*corev1.Namespace); ok {
+		for key, value := range namespace.Labels {
+			namespaceLabels[key] = value
+		}
+	}
+
+	return w.namespaceSelector.Matches(namespaceLabels), nil
+}
+
+// rejectedError is returned when the webhook doesn't allow the pod, which the failure policy doesn't apply to
+type rejectedError struct {
+	message string
+}
+
+func (e *rejectedError) Error() string {
+	return e.message
+}
+
+// call sends the admission review of the creation of the pod as a dry run, since the pod is never created, and
+// applies the patch returned
+func (w *webhook) call(pod *corev1.Pod) error {
+	obj := pod.DeepCopy()
+	obj.APIVersion, obj.Kind = corev1.SchemeGroupVersion.String(), "Pod"
+	podData, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	optionsData, err := json.Marshal(&metav1.CreateOptions{TypeMeta: metav1.TypeMeta{APIVersion: metav1.SchemeGroupVersion.String(), Kind: "CreateOptions"}})
+	if err != nil {
+		return err
+	}
+
+	dryRun := true
+	kind := metav1.GroupVersionKind{Group: corev1.GroupName, Version: corev1.SchemeGroupVersion.Version, Kind: "Pod"}
+	resource := metav1.GroupVersionResource{Group: corev1.GroupName, Version: corev1.SchemeGroupVersion.Version, Resource: "pods"}
+	request := &admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: admissionv1.SchemeGroupVersion.String(), Kind: "AdmissionReview"},
+		Request: &admissionv1.AdmissionRequest{
+			UID:             types.UID(uuid.NewV4().String()),
+			Kind:            kind,
+			Resource:        resource,
+			RequestKind:     &kind,
+			RequestResource: &resource,
+			Name:            pod.Name,
+			Namespace:       pod.Namespace,
+			Operation:       admissionv1.Create,
+			UserInfo:        authenticationv1.UserInfo{Username: pkg.SchedulerName},
+			Object:          runtime.RawExtension{Raw: podData},
+			DryRun:          &dryRun,
+			Options:         runtime.RawExtension{Raw: optionsData},
+		},
+	}
+	data, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.client.Post(*w.ClientConfig.URL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed calling webhook %s: %v", w.Name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed calling webhook %s: server reported %s", w.Name, resp.Status)
+	}
+
+	review := &admissionv1.AdmissionReview{}
+	if err := json.NewDecoder(resp.Body).Decode(review); err != nil {
+		return fmt.Errorf("failed to decode the response of webhook %s: %v", w.Name, err)
+	}
+	response := review.Response
+	if response == nil || response.UID != request.Request.UID {
+		return fmt.Errorf("webhook %s returned no response for the request", w.Name)
+	}
+	if !response.Allowed {
+		message := "no reason given"
+		if response.Result != nil && len(response.Result.Message) > 0 {
+			message = response.Result.Message
+		}
+		return &rejectedError{message: fmt.Sprintf("pod %s/%s rejected by webhook %s: %s", pod.Namespace, pod.Name, w.Name, message)}
+	}
+	if len(response.Patch) == 0 {
+		return nil
+	}
+	if response.PatchType == nil || *response.PatchType != admissionv1.PatchTypeJSONPatch {
+		return fmt.Errorf("webhook %s returned a patch of an unsupported type", w.Name)
+	}
+
+	patch, err := jsonpatch.DecodePatch(response.Patch)
+	if err != nil {
+		return fmt.Errorf("invalid patch of webhook %s: %v", w.Name, err)
+	}
+	if podData, err = patch.Apply(podData); err != nil {
+		return fmt.Errorf("failed to apply the patch of webhook %s: %v", w.Name, err)
+	}
+	patched := &corev1.Pod{}
+	if err := json.Unmarshal(podData, patched); err != nil {
+		return fmt.Errorf("failed to apply the patch of webhook %s: %v", w.Name, err)
+	}
+	patched.TypeMeta = pod.TypeMeta
+	*pod = *patched
+
+	return nil
+}
+
+func contains[T comparable](values []T, wanted ...T) bool {
+	for _, value := range values {
+		for _, w := range wanted {
+			if value == w {
+				return true
+			}
+		}
+	}
+	return false
+}
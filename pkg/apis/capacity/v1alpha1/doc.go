@@ -0,0 +1,5 @@
+// Package v1alpha1 contains the review API of kluster-capacity, the reviews are submitted as custom resources
+// and evaluated by the controller.
+// +k8s:deepcopy-gen=package
+// +groupName=capacity.k-cloud-labs.io
+package v1alpha1
@@ -0,0 +1,176 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConditionEvaluated is true when the last evaluation of the review succeeded
+	ConditionEvaluated = "Evaluated"
+
+	ReasonSimulationSucceeded = "SimulationSucceeded"
+	ReasonSimulationFailed    = "SimulationFailed"
+)
+
+// ReviewStatus is shared by the status of all the reviews
+type ReviewStatus struct {
+	// the generation of the spec evaluated last time
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// the review is evaluated again once the resync period of the spec is passed
+	LastEvaluationTime *metav1.Time       `json:"lastEvaluationTime,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// StopReason is the reason why the simulation stopped
+type StopReason struct {
+	StopType    string `json:"stopType"`
+	StopMessage string `json:"stopMessage"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+
+// CapacityEstimationReview estimates how many replicas of each template the cluster could hold
+type CapacityEstimationReview struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CapacityEstimationReviewSpec   `json:"spec"`
+	Status CapacityEstimationReviewStatus `json:"status,omitempty"`
+}
+
+type CapacityEstimationReviewSpec struct {
+	// each template is estimated separately, the name and namespace of the review are used if the
+	// template doesn't have them
+	Templates []corev1.PodTemplateSpec `json:"templates"`
+	// number of replicas after which the estimation stops, unlimited if 0
+	MaxLimit         int      `json:"maxLimit,omitempty"`
+	ExcludeNodes     []string `json:"excludeNodes,omitempty"`
+	EnablePreemption bool     `json:"enablePreemption,omitempty"`
+	BatchSize        int      `json:"batchSize,omitempty"`
+	// the review is evaluated again after the period, the default of the controller is used if not set
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+}
+
+type CapacityEstimationReviewStatus struct {
+	ReviewStatus `json:",inline"`
+	Results      []CapacityEstimationResult `json:"results,omitempty"`
+}
+
+type CapacityEstimationResult struct {
+	PodName  string `json:"podName"`
+	Replicas int32  `json:"replicas"`
+	// only available when preemption is enabled
+	ReplicasWithoutPreemption *int32           `json:"replicasWithoutPreemption,omitempty"`
+	StopReason                *StopReason      `json:"stopReason,omitempty"`
+	ReplicasOnNodes           []ReplicasOnNode `json:"replicasOnNodes,omitempty"`
+}
+
+type ReplicasOnNode struct {
+	NodeName string `json:"nodeName"`
+	Replicas int32  `json:"replicas"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type CapacityEstimationReviewList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []CapacityEstimationReview `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+
+// ClusterCompressionReview estimates how many nodes could be removed from the cluster
+type ClusterCompressionReview struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterCompressionReviewSpec   `json:"spec,omitempty"`
+	Status ClusterCompressionReviewStatus `json:"status,omitempty"`
+}
+
+type ClusterCompressionReviewSpec struct {
+	// number of nodes after which the compression stops, unlimited if 0
+	MaxLimit     int      `json:"maxLimit,omitempty"`
+	ExcludeNodes []string `json:"excludeNodes,omitempty"`
+	// the same as the flags of cc, all of them default to the defaults of the flags if not set
+	ExcludeNotReadyNode *bool `json:"excludeNotReadyNode,omitempty"`
+	ExcludeTaintNode    *bool `json:"excludeTaintNode,omitempty"`
+	IgnoreStaticPod     *bool `json:"ignoreStaticPod,omitempty"`
+	IgnoreMirrorPod     *bool `json:"ignoreMirrorPod,omitempty"`
+	IgnoreCloneSet      *bool `json:"ignoreCloneSet,omitempty"`
+	IgnoreVolumePod     *bool `json:"ignoreVolumePod,omitempty"`
+	// the review is evaluated again after the period, the default of the controller is used if not set
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+}
+
+type ClusterCompressionReviewStatus struct {
+	ReviewStatus       `json:",inline"`
+	StopReason         *StopReason `json:"stopReason,omitempty"`
+	ScaleDownNodeNames []string    `json:"scaleDownNodeNames,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type ClusterCompressionReviewList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ClusterCompressionReview `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+
+// SchedulerSimulationReview reschedules all the pods of the cluster and reports the placement
+type SchedulerSimulationReview struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SchedulerSimulationReviewSpec   `json:"spec,omitempty"`
+	Status SchedulerSimulationReviewStatus `json:"status,omitempty"`
+}
+
+type SchedulerSimulationReviewSpec struct {
+	// AllSucceed or AllScheduled, AllSucceed if not set
+	ExitCondition string   `json:"exitCondition,omitempty"`
+	ExcludeNodes  []string `json:"excludeNodes,omitempty"`
+	// true if not set
+	IgnorePodsOnExcludeNodes *bool  `json:"ignorePodsOnExcludeNodes,omitempty"`
+	ReplayOrder              string `json:"replayOrder,omitempty"`
+	ReplaySeed               int64  `json:"replaySeed,omitempty"`
+	// the simulation stops after the timeout, unlimited if not set
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// the review is evaluated again after the period, the default of the controller is used if not set
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+}
+
+type SchedulerSimulationReviewStatus struct {
+	ReviewStatus    `json:",inline"`
+	StopReason      string `json:"stopReason,omitempty"`
+	NodesUsed       int32  `json:"nodesUsed,omitempty"`
+	OnlyDSPodNodes  int32  `json:"onlyDSPodNodes,omitempty"`
+	NodesLowerBound int32  `json:"nodesLowerBound,omitempty"`
+	// ratios of requested to allocatable resources of the used nodes, formatted as decimals since floats
+	// are discouraged in the api
+	CPUPackingEfficiency    string `json:"cpuPackingEfficiency,omitempty"`
+	MemoryPackingEfficiency string `json:"memoryPackingEfficiency,omitempty"`
+	// namespace/name of the pods which couldn't be scheduled
+	UnschedulablePods []string `json:"unschedulablePods,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type SchedulerSimulationReviewList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []SchedulerSimulationReview `json:"items"`
+}
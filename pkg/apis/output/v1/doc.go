@@ -0,0 +1,5 @@
+// Package v1 contains the stable output API of ce, cc and ss, i.e. the results printed with -o json or yaml. It's
+// the hub of the conversion, the other versions are converted to and from it.
+// +k8s:deepcopy-gen=package
+// +groupName=output.capacity.k-cloud-labs.io
+package v1
@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupName is the group name of the output API
+const GroupName = "output.capacity.k-cloud-labs.io"
+
+// SchemeGroupVersion is group version used to register these objects
+var SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: "v1"}
+
+var (
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+	AddToScheme   = SchemeBuilder.AddToScheme
+)
+
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion,
+		&CapacityEstimationReview{},
+		&ClusterCompressionReview{},
+		&SchedulerSimulationReview{},
+	)
+	return nil
+}
+
+// Hub marks the versions of this package as the hub of the conversion
+func (*CapacityEstimationReview) Hub()  {}
+func (*ClusterCompressionReview) Hub()  {}
+func (*SchedulerSimulationReview) Hub() {}
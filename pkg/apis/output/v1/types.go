@@ -0,0 +1,152 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StopReason is the reason why the simulation stopped
+type StopReason struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// NodeUtilization is the resources requested by the pods bound to a node at the end of a simulation
+type NodeUtilization struct {
+	Name                string `json:"name"`
+	Zone                string `json:"zone,omitempty"`
+	Pods                int32  `json:"pods"`
+	MilliCPURequested   int64  `json:"milliCPURequested"`
+	MilliCPUAllocatable int64  `json:"milliCPUAllocatable"`
+	MemoryRequested     int64  `json:"memoryRequested"`
+	MemoryAllocatable   int64  `json:"memoryAllocatable"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CapacityEstimationReview is the result of ce, how many replicas of each template the cluster could hold
+type CapacityEstimationReview struct {
+	metav1.TypeMeta `json:",inline"`
+
+	CreationTimestamp metav1.Time `json:"creationTimestamp"`
+	// one result per template in the order of the templates
+	Templates []CapacityEstimationResult `json:"templates"`
+}
+
+type CapacityEstimationResult struct {
+	Name         string              `json:"name"`
+	Requests     corev1.ResourceList `json:"requests,omitempty"`
+	NodeSelector map[string]string   `json:"nodeSelector,omitempty"`
+	// number of replicas that could schedule
+	Replicas        int32            `json:"replicas"`
+	StopReason      StopReason       `json:"stopReason"`
+	ReplicasOnNodes []ReplicasOnNode `json:"replicasOnNodes,omitempty"`
+	// why no more replicas could schedule on the nodes
+	FitFailures []FitFailure `json:"fitFailures,omitempty"`
+	// only available when the namespace of the template has resource quotas
+	Quota *Quota `json:"quota,omitempty"`
+	// only available when preemption is enabled
+	Preemption *Preemption `json:"preemption,omitempty"`
+	// requested resources of the nodes with the replicas scheduled
+	Nodes []NodeUtilization `json:"nodes,omitempty"`
+}
+
+type ReplicasOnNode struct {
+	NodeName string `json:"nodeName"`
+	Replicas int32  `json:"replicas"`
+}
+
+type FitFailure struct {
+	Reason string `json:"reason"`
+	Count  int32  `json:"count"`
+}
+
+type Quota struct {
+	// number of replicas the nodes could hold
+	NodeBoundReplicas int32 `json:"nodeBoundReplicas"`
+	// number of replicas the resource quotas of the namespace allow
+	QuotaBoundReplicas int32 `json:"quotaBoundReplicas"`
+	// the quota and resource which limit the replicas most
+	QuotaName    string              `json:"quotaName"`
+	ResourceName corev1.ResourceName `json:"resourceName"`
+}
+
+type Preemption struct {
+	// number of replicas that could schedule without evicting any pod
+	ReplicasWithoutPreemption int32 `json:"replicasWithoutPreemption"`
+	// evicted pods grouped by owner and priority class
+	Victims []PreemptionVictims `json:"victims,omitempty"`
+}
+
+type PreemptionVictims struct {
+	Namespace         string   `json:"namespace"`
+	OwnerKind         string   `json:"ownerKind"`
+	OwnerName         string   `json:"ownerName"`
+	PriorityClassName string   `json:"priorityClassName,omitempty"`
+	Priority          int32    `json:"priority"`
+	Pods              []string `json:"pods"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterCompressionReview is the result of cc, which nodes could be removed from the cluster
+type ClusterCompressionReview struct {
+	metav1.TypeMeta `json:",inline"`
+
+	CreationTimestamp metav1.Time `json:"creationTimestamp"`
+	StopReason        StopReason  `json:"stopReason"`
+	// the nodes which could be scaled down in the order of their removal
+	ScaleDownNodes []string `json:"scaleDownNodes"`
+	// requested resources of the nodes after the nodes are scaled down
+	Nodes []NodeUtilization `json:"nodes,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SchedulerSimulationReview is the result of ss, how all the pods of the cluster are placed by the scheduler
+type SchedulerSimulationReview struct {
+	metav1.TypeMeta `json:",inline"`
+
+	StopReason StopReason `json:"stopReason"`
+	// order of the pods entering the scheduling queue
+	ReplayOrder string `json:"replayOrder"`
+	// only available when the replay order is Random
+	ReplaySeed        int64              `json:"replaySeed,omitempty"`
+	UnschedulablePods []UnschedulablePod `json:"unschedulablePods"`
+	// the nodes with pods bound
+	Nodes   []NodePlacement  `json:"nodes"`
+	Metrics PlacementMetrics `json:"metrics"`
+}
+
+type UnschedulablePod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+type NodePlacement struct {
+	Name              string              `json:"name"`
+	Zone              string              `json:"zone,omitempty"`
+	Pods              int32               `json:"pods"`
+	OnlyDaemonSetPods bool                `json:"onlyDaemonSetPods"`
+	Allocatable       corev1.ResourceList `json:"allocatable,omitempty"`
+	Requests          corev1.ResourceList `json:"requests,omitempty"`
+	// ratios of requested to allocatable resources, formatted as decimals since floats are discouraged in the api
+	CPURequestedRatio    string `json:"cpuRequestedRatio"`
+	MemoryRequestedRatio string `json:"memoryRequestedRatio"`
+}
+
+// PlacementMetrics measures the quality of the placement, the used nodes are the nodes running at least one pod
+// which doesn't belong to a DaemonSet
+type PlacementMetrics struct {
+	NodesUsed             int32 `json:"nodesUsed"`
+	OnlyDaemonSetPodNodes int32 `json:"onlyDaemonSetPodNodes"`
+	// minimum number of nodes needed by the placed pods which don't belong to a DaemonSet
+	NodesLowerBound int32 `json:"nodesLowerBound"`
+	// ratios of requested to allocatable resources of all the used nodes and their standard deviations,
+	// formatted as decimals
+	CPUPackingEfficiency    string `json:"cpuPackingEfficiency"`
+	MemoryPackingEfficiency string `json:"memoryPackingEfficiency"`
+	CPUUtilizationStdDev    string `json:"cpuUtilizationStdDev"`
+	MemoryUtilizationStdDev string `json:"memoryUtilizationStdDev"`
+}
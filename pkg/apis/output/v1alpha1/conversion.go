@@ -0,0 +1,431 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	outputv1 "github.com/k-cloud-labs/kluster-capacity/pkg/apis/output/v1"
+)
+
+var (
+	_ conversion.Convertible = &CapacityEstimationReviewList{}
+	_ conversion.Convertible = &ClusterCompressionReview{}
+	_ conversion.Convertible = &SchedulerSimulationReview{}
+)
+
+// ConvertTo converts the reviews of the templates to one review of v1
+func (src *CapacityEstimationReviewList) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*outputv1.CapacityEstimationReview)
+	if !ok {
+		return fmt.Errorf("can't convert %T to %T", src, dstRaw)
+	}
+
+	dst.APIVersion, dst.Kind = outputv1.SchemeGroupVersion.String(), "CapacityEstimationReview"
+	dst.Templates = make([]outputv1.CapacityEstimationResult, 0, len(src.Items))
+	for i := range src.Items {
+		item := &src.Items[i]
+		if i == 0 || item.Status.CreationTimestamp.Before(&dst.CreationTimestamp) {
+			dst.CreationTimestamp = item.Status.CreationTimestamp
+		}
+
+		result := outputv1.CapacityEstimationResult{
+			Replicas:   item.Status.Replicas,
+			StopReason: convertStopReasonTo(item.Status.StopReason),
+			Nodes:      convertNodesTo(item.Status.Nodes),
+		}
+		if len(item.Spec.PodRequirements) > 0 {
+			requirements := &item.Spec.PodRequirements[0]
+			result.Name = requirements.PodName
+			result.NodeSelector = requirements.NodeSelectors
+			result.Requests = requirements.Resources.toResourceList()
+		} else if len(item.Spec.Templates) > 0 {
+			result.Name = item.Spec.Templates[0].Name
+			result.NodeSelector = item.Spec.Templates[0].Spec.NodeSelector
+		}
+		for _, pod := range item.Status.Pods {
+			for _, ron := range pod.ReplicasOnNodes {
+				result.ReplicasOnNodes = append(result.ReplicasOnNodes, outputv1.ReplicasOnNode{NodeName: ron.NodeName, Replicas: int32(ron.Replicas)})
+			}
+			for _, summary := range pod.Summary {
+				result.FitFailures = append(result.FitFailures, outputv1.FitFailure{Reason: summary.Reason, Count: int32(summary.Count)})
+			}
+		}
+		if quota := item.Status.Quota; quota != nil {
+			result.Quota = &outputv1.Quota{
+				NodeBoundReplicas:  quota.NodeBoundReplicas,
+				QuotaBoundReplicas: quota.QuotaBoundReplicas,
+				QuotaName:          quota.QuotaName,
+				ResourceName:       quota.ResourceName,
+			}
+		}
+		if preemption := item.Status.Preemption; preemption != nil {
+			result.Preemption = &outputv1.Preemption{ReplicasWithoutPreemption: preemption.ReplicasWithoutPreemption}
+			for _, victims := range preemption.Victims {
+				result.Preemption.Victims = append(result.Preemption.Victims, outputv1.PreemptionVictims{
+					Namespace:         victims.Namespace,
+					OwnerKind:         victims.OwnerKind,
+					OwnerName:         victims.OwnerName,
+					PriorityClassName: victims.PriorityClassName,
+					Priority:          victims.Priority,
+					Pods:              victims.Pods,
+				})
+			}
+		}
+		dst.Templates = append(dst.Templates, result)
+	}
+
+	return nil
+}
+
+// ConvertFrom converts the review of v1 to one review per template, the templates only keep their names, node
+// selectors and requests
+func (dst *CapacityEstimationReviewList) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*outputv1.CapacityEstimationReview)
+	if !ok {
+		return fmt.Errorf("can't convert %T to %T", srcRaw, dst)
+	}
+
+	dst.APIVersion, dst.Kind = SchemeGroupVersion.String(), "CapacityEstimationReview"
+	dst.Items = make([]CapacityEstimationReview, 0, len(src.Templates))
+	for i := range src.Templates {
+		result := &src.Templates[i]
+		template := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: result.Name},
+			Spec: corev1.PodSpec{
+				NodeSelector: result.NodeSelector,
+				Containers: []corev1.Container{{
+					Name:      result.Name,
+					Resources: corev1.ResourceRequirements{Requests: result.Requests},
+				}},
+			},
+		}
+
+		pods := CapacityEstimationReviewResult{PodName: result.Name, ReplicasOnNodes: []ReplicasOnNode{}}
+		for _, ron := range result.ReplicasOnNodes {
+			pods.ReplicasOnNodes = append(pods.ReplicasOnNodes, ReplicasOnNode{NodeName: ron.NodeName, Replicas: int(ron.Replicas)})
+		}
+		for _, failure := range result.FitFailures {
+			pods.Summary = append(pods.Summary, StopReasonSummary{Reason: failure.Reason, Count: int(failure.Count)})
+		}
+
+		item := CapacityEstimationReview{
+			Spec: CapacityEstimationReviewSpec{
+				Templates: []corev1.Pod{template},
+				PodRequirements: []Requirements{{
+					PodName:       result.Name,
+					Resources:     fromResourceList(result.Requests),
+					NodeSelectors: result.NodeSelector,
+				}},
+			},
+			Status: CapacityEstimationReviewStatus{
+				CreationTimestamp: src.CreationTimestamp,
+				Replicas:          result.Replicas,
+				StopReason:        convertStopReasonFrom(result.StopReason),
+				Pods:              []CapacityEstimationReviewResult{pods},
+				Nodes:             convertNodesFrom(result.Nodes),
+			},
+		}
+		if quota := result.Quota; quota != nil {
+			item.Status.Quota = &CapacityEstimationReviewQuota{
+				NodeBoundReplicas:  quota.NodeBoundReplicas,
+				QuotaBoundReplicas: quota.QuotaBoundReplicas,
+				QuotaName:          quota.QuotaName,
+				ResourceName:       quota.ResourceName,
+			}
+		}
+		if preemption := result.Preemption; preemption != nil {
+			item.Status.Preemption = &CapacityEstimationReviewPreemption{
+				ReplicasWithoutPreemption: preemption.ReplicasWithoutPreemption,
+				ReplicasWithPreemption:    result.Replicas,
+				Victims:                   []PreemptionVictims{},
+			}
+			for _, victims := range preemption.Victims {
+				item.Status.Preemption.Victims = append(item.Status.Preemption.Victims, PreemptionVictims{
+					Namespace:         victims.Namespace,
+					OwnerKind:         victims.OwnerKind,
+					OwnerName:         victims.OwnerName,
+					PriorityClassName: victims.PriorityClassName,
+					Priority:          victims.Priority,
+					Count:             len(victims.Pods),
+					Pods:              victims.Pods,
+				})
+			}
+		}
+		dst.Items = append(dst.Items, item)
+	}
+
+	return nil
+}
+
+func (src *ClusterCompressionReview) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*outputv1.ClusterCompressionReview)
+	if !ok {
+		return fmt.Errorf("can't convert %T to %T", src, dstRaw)
+	}
+
+	dst.APIVersion, dst.Kind = outputv1.SchemeGroupVersion.String(), "ClusterCompressionReview"
+	dst.CreationTimestamp = src.Status.CreationTimestamp
+	dst.StopReason = convertStopReasonTo(src.Status.StopReason)
+	dst.ScaleDownNodes = src.Status.ScaleDownNodeNames
+	if dst.ScaleDownNodes == nil {
+		dst.ScaleDownNodes = []string{}
+	}
+	dst.Nodes = convertNodesTo(src.Status.Nodes)
+
+	return nil
+}
+
+func (dst *ClusterCompressionReview) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*outputv1.ClusterCompressionReview)
+	if !ok {
+		return fmt.Errorf("can't convert %T to %T", srcRaw, dst)
+	}
+
+	dst.APIVersion, dst.Kind = SchemeGroupVersion.String(), "ClusterCompressionReview"
+	dst.Status = ClusterCompressionReviewStatus{
+		CreationTimestamp:  src.CreationTimestamp,
+		StopReason:         convertStopReasonFrom(src.StopReason),
+		ScaleDownNodeNames: src.ScaleDownNodes,
+		Nodes:              convertNodesFrom(src.Nodes),
+	}
+
+	return nil
+}
+
+func (src *SchedulerSimulationReview) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*outputv1.SchedulerSimulationReview)
+	if !ok {
+		return fmt.Errorf("can't convert %T to %T", src, dstRaw)
+	}
+
+	dst.APIVersion, dst.Kind = outputv1.SchemeGroupVersion.String(), "SchedulerSimulationReview"
+	// the stop reason is formatted as <type>: <message>
+	stopType, stopMessage, _ := strings.Cut(src.StopReason, ":")
+	dst.StopReason = outputv1.StopReason{Type: stopType, Message: strings.TrimSpace(stopMessage)}
+	dst.ReplayOrder = src.ReplayOrder
+	dst.ReplaySeed = src.ReplaySeed
+
+	dst.UnschedulablePods = make([]outputv1.UnschedulablePod, 0, len(src.UnschedulablePods))
+	for i := range src.UnschedulablePods {
+		pod := &src.UnschedulablePods[i]
+		unschedulable := outputv1.UnschedulablePod{Namespace: pod.Namespace, Name: pod.Name}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse {
+				unschedulable.Reason = condition.Message
+			}
+		}
+		dst.UnschedulablePods = append(dst.UnschedulablePods, unschedulable)
+	}
+
+	dst.Nodes = make([]outputv1.NodePlacement, 0, len(src.Details))
+	for i := range src.Details {
+		detail := &src.Details[i]
+		dst.Nodes = append(dst.Nodes, outputv1.NodePlacement{
+			Name:                 detail.NodeName,
+			Zone:                 detail.Zone,
+			Pods:                 int32(detail.Replicas),
+			OnlyDaemonSetPods:    detail.OnlyDSPod,
+			Allocatable:          detail.NodeAllocatable,
+			Requests:             detail.PodRequest.toResourceList(),
+			CPURequestedRatio:    formatRatio(detail.CPURequestedRatio),
+			MemoryRequestedRatio: formatRatio(detail.MemoryRequestedRatio),
+		})
+	}
+
+	dst.Metrics = outputv1.PlacementMetrics{
+		NodesUsed:               int32(src.Metrics.NodesUsed),
+		OnlyDaemonSetPodNodes:   int32(src.Metrics.OnlyDSPodNodes),
+		NodesLowerBound:         int32(src.Metrics.NodesLowerBound),
+		CPUPackingEfficiency:    formatRatio(src.Metrics.CPUPackingEfficiency),
+		MemoryPackingEfficiency: formatRatio(src.Metrics.MemoryPackingEfficiency),
+		CPUUtilizationStdDev:    formatRatio(src.Metrics.CPUUtilizationStdDev),
+		MemoryUtilizationStdDev: formatRatio(src.Metrics.MemoryUtilizationStdDev),
+	}
+
+	return nil
+}
+
+// ConvertFrom converts the review of v1, the unschedulable pods only keep their names and the reasons
+func (dst *SchedulerSimulationReview) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*outputv1.SchedulerSimulationReview)
+	if !ok {
+		return fmt.Errorf("can't convert %T to %T", srcRaw, dst)
+	}
+
+	dst.APIVersion, dst.Kind = SchemeGroupVersion.String(), "SchedulerSimulationReview"
+	dst.StopReason = fmt.Sprintf("%s: %s", src.StopReason.Type, src.StopReason.Message)
+	dst.ReplayOrder = src.ReplayOrder
+	dst.ReplaySeed = src.ReplaySeed
+
+	dst.UnschedulablePods = make([]corev1.Pod, 0, len(src.UnschedulablePods))
+	for _, unschedulable := range src.UnschedulablePods {
+		pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: unschedulable.Namespace, Name: unschedulable.Name}}
+		if len(unschedulable.Reason) > 0 {
+			pod.Status.Conditions = []corev1.PodCondition{{
+				Type:    corev1.PodScheduled,
+				Status:  corev1.ConditionFalse,
+				Reason:  corev1.PodReasonUnschedulable,
+				Message: unschedulable.Reason,
+			}}
+		}
+		dst.UnschedulablePods = append(dst.UnschedulablePods, pod)
+	}
+
+	var err error
+	dst.Details = make([]ScheduleDetail, 0, len(src.Nodes))
+	for i := range src.Nodes {
+		node := &src.Nodes[i]
+		detail := ScheduleDetail{
+			NodeName:        node.Name,
+			Zone:            node.Zone,
+			Replicas:        int(node.Pods),
+			NodeAllocatable: node.Allocatable,
+			OnlyDSPod:       node.OnlyDaemonSetPods,
+		}
+		if resources := fromResourceList(node.Requests); resources != nil {
+			detail.PodRequest = *resources
+		}
+		if detail.CPURequestedRatio, err = parseRatio(node.CPURequestedRatio); err != nil {
+			return err
+		}
+		if detail.MemoryRequestedRatio, err = parseRatio(node.MemoryRequestedRatio); err != nil {
+			return err
+		}
+		dst.Details = append(dst.Details, detail)
+	}
+
+	dst.Metrics = PlacementMetrics{
+		NodesUsed:       int(src.Metrics.NodesUsed),
+		OnlyDSPodNodes:  int(src.Metrics.OnlyDaemonSetPodNodes),
+		NodesLowerBound: int(src.Metrics.NodesLowerBound),
+	}
+	for _, ratio := range []struct {
+		value string
+		dst   *float64
+	}{
+		{src.Metrics.CPUPackingEfficiency, &dst.Metrics.CPUPackingEfficiency},
+		{src.Metrics.MemoryPackingEfficiency, &dst.Metrics.MemoryPackingEfficiency},
+		{src.Metrics.CPUUtilizationStdDev, &dst.Metrics.CPUUtilizationStdDev},
+		{src.Metrics.MemoryUtilizationStdDev, &dst.Metrics.MemoryUtilizationStdDev},
+	} {
+		if *ratio.dst, err = parseRatio(ratio.value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func convertStopReasonTo(reason *StopReason) outputv1.StopReason {
+	if reason == nil {
+		return outputv1.StopReason{}
+	}
+	return outputv1.StopReason{Type: reason.StopType, Message: reason.StopMessage}
+}
+
+func convertStopReasonFrom(reason outputv1.StopReason) *StopReason {
+	return &StopReason{StopType: reason.Type, StopMessage: reason.Message}
+}
+
+func convertNodesTo(nodes []NodeUtilization) []outputv1.NodeUtilization {
+	if nodes == nil {
+		return nil
+	}
+	result := make([]outputv1.NodeUtilization, 0, len(nodes))
+	for _, node := range nodes {
+		result = append(result, outputv1.NodeUtilization{
+			Name:                node.Name,
+			Zone:                node.Zone,
+			Pods:                int32(node.Pods),
+			MilliCPURequested:   node.MilliCPURequested,
+			MilliCPUAllocatable: node.MilliCPUAllocatable,
+			MemoryRequested:     node.MemoryRequested,
+			MemoryAllocatable:   node.MemoryAllocatable,
+		})
+	}
+	return result
+}
+
+func convertNodesFrom(nodes []outputv1.NodeUtilization) []NodeUtilization {
+	if nodes == nil {
+		return nil
+	}
+	result := make([]NodeUtilization, 0, len(nodes))
+	for _, node := range nodes {
+		result = append(result, NodeUtilization{
+			Name:                node.Name,
+			Zone:                node.Zone,
+			Pods:                int(node.Pods),
+			MilliCPURequested:   node.MilliCPURequested,
+			MilliCPUAllocatable: node.MilliCPUAllocatable,
+			MemoryRequested:     node.MemoryRequested,
+			MemoryAllocatable:   node.MemoryAllocatable,
+		})
+	}
+	return result
+}
+
+func (r *Resource) toResourceList() corev1.ResourceList {
+	if r == nil {
+		return nil
+	}
+
+	result := corev1.ResourceList{
+		corev1.ResourceCPU:    *resource.NewMilliQuantity(r.MilliCPU, resource.DecimalSI),
+		corev1.ResourceMemory: *resource.NewQuantity(r.Memory, resource.BinarySI),
+	}
+	if r.EphemeralStorage > 0 {
+		result[corev1.ResourceEphemeralStorage] = *resource.NewQuantity(r.EphemeralStorage, resource.BinarySI)
+	}
+	for name, value := range r.ScalarResources {
+		result[name] = *resource.NewQuantity(value, resource.DecimalSI)
+	}
+	return result
+}
+
+func fromResourceList(list corev1.ResourceList) *Resource {
+	if list == nil {
+		return nil
+	}
+
+	result := &Resource{}
+	for name, quantity := range list {
+		switch name {
+		case corev1.ResourceCPU:
+			result.MilliCPU = quantity.MilliValue()
+		case corev1.ResourceMemory:
+			result.Memory = quantity.Value()
+		case corev1.ResourceEphemeralStorage:
+			result.EphemeralStorage = quantity.Value()
+		case corev1.ResourcePods:
+			result.AllowedPodNumber = int(quantity.Value())
+		default:
+			if result.ScalarResources == nil {
+				result.ScalarResources = make(map[corev1.ResourceName]int64)
+			}
+			result.ScalarResources[name] = quantity.Value()
+		}
+	}
+	return result
+}
+
+func formatRatio(ratio float64) string {
+	return strconv.FormatFloat(ratio, 'f', 4, 64)
+}
+
+func parseRatio(ratio string) (float64, error) {
+	if len(ratio) == 0 {
+		return 0, nil
+	}
+	value, err := strconv.ParseFloat(ratio, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ratio %q: %v", ratio, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,6 @@
+// Package v1alpha1 contains the output API of ce, cc and ss in the shape printed with -o json or yaml before the
+// output was versioned, so that the results saved before could be converted to the later versions. The results of
+// v1alpha1 are still printed in that shape, without apiVersion and kind.
+// +k8s:deepcopy-gen=package
+// +groupName=output.capacity.k-cloud-labs.io
+package v1alpha1
@@ -0,0 +1,26 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	outputv1 "github.com/k-cloud-labs/kluster-capacity/pkg/apis/output/v1"
+)
+
+// SchemeGroupVersion is group version used to register these objects
+var SchemeGroupVersion = schema.GroupVersion{Group: outputv1.GroupName, Version: "v1alpha1"}
+
+var (
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+	AddToScheme   = SchemeBuilder.AddToScheme
+)
+
+func addKnownTypes(scheme *runtime.Scheme) error {
+	// the result of ce is the same kind in all the versions
+	scheme.AddKnownTypeWithName(SchemeGroupVersion.WithKind("CapacityEstimationReview"), &CapacityEstimationReviewList{})
+	scheme.AddKnownTypes(SchemeGroupVersion,
+		&ClusterCompressionReview{},
+		&SchedulerSimulationReview{},
+	)
+	return nil
+}
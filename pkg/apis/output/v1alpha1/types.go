@@ -0,0 +1,168 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StopReason is the reason why the simulation stopped
+type StopReason struct {
+	StopType    string `json:"stopType"`
+	StopMessage string `json:"stopMessage"`
+}
+
+// NodeUtilization is the resources requested by the pods bound to a node at the end of a simulation
+type NodeUtilization struct {
+	Name                string `json:"name"`
+	Zone                string `json:"zone,omitempty"`
+	Pods                int    `json:"pods"`
+	MilliCPURequested   int64  `json:"milliCPURequested"`
+	MilliCPUAllocatable int64  `json:"milliCPUAllocatable"`
+	MemoryRequested     int64  `json:"memoryRequested"`
+	MemoryAllocatable   int64  `json:"memoryAllocatable"`
+}
+
+// Resource is the resources requested by a pod, the fields are capitalized as the ones of the scheduler
+type Resource struct {
+	MilliCPU         int64                         `json:"MilliCPU"`
+	Memory           int64                         `json:"Memory"`
+	EphemeralStorage int64                         `json:"EphemeralStorage"`
+	AllowedPodNumber int                           `json:"AllowedPodNumber"`
+	ScalarResources  map[corev1.ResourceName]int64 `json:"ScalarResources"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CapacityEstimationReviewList is the result of ce, one review per template. It's the kind CapacityEstimationReview
+// as in v1, and its reviews are printed one by one as before the output was versioned.
+type CapacityEstimationReviewList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	Items []CapacityEstimationReview `json:"items"`
+}
+
+// CapacityEstimationReview is how many replicas of a template the cluster could hold
+type CapacityEstimationReview struct {
+	Spec   CapacityEstimationReviewSpec   `json:"spec"`
+	Status CapacityEstimationReviewStatus `json:"status"`
+}
+
+type CapacityEstimationReviewSpec struct {
+	// the pod desired for scheduling
+	Templates       []corev1.Pod   `json:"templates"`
+	PodRequirements []Requirements `json:"podRequirements"`
+}
+
+type Requirements struct {
+	PodName       string            `json:"podName"`
+	Resources     *Resource         `json:"resources"`
+	NodeSelectors map[string]string `json:"nodeSelectors"`
+}
+
+type CapacityEstimationReviewStatus struct {
+	CreationTimestamp metav1.Time `json:"creationTimestamp"`
+	// actual number of replicas that could schedule
+	Replicas   int32       `json:"replicas"`
+	StopReason *StopReason `json:"stopReason"`
+	// per node information about the scheduling simulation
+	Pods []CapacityEstimationReviewResult `json:"pods"`
+	// only available when the namespace of the pod has resource quotas
+	Quota *CapacityEstimationReviewQuota `json:"quota,omitempty"`
+	// only available when preemption is enabled
+	Preemption *CapacityEstimationReviewPreemption `json:"preemption,omitempty"`
+	// requested resources of the nodes with the replicas scheduled
+	Nodes []NodeUtilization `json:"nodes,omitempty"`
+}
+
+type CapacityEstimationReviewQuota struct {
+	NodeBoundReplicas  int32               `json:"nodeBoundReplicas"`
+	QuotaBoundReplicas int32               `json:"quotaBoundReplicas"`
+	QuotaName          string              `json:"quotaName"`
+	ResourceName       corev1.ResourceName `json:"resourceName"`
+}
+
+type CapacityEstimationReviewPreemption struct {
+	ReplicasWithoutPreemption int32               `json:"replicasWithoutPreemption"`
+	ReplicasWithPreemption    int32               `json:"replicasWithPreemption"`
+	Victims                   []PreemptionVictims `json:"victims"`
+}
+
+type PreemptionVictims struct {
+	Namespace         string   `json:"namespace"`
+	OwnerKind         string   `json:"ownerKind"`
+	OwnerName         string   `json:"ownerName"`
+	PriorityClassName string   `json:"priorityClassName"`
+	Priority          int32    `json:"priority"`
+	Count             int      `json:"count"`
+	Pods              []string `json:"pods"`
+}
+
+type CapacityEstimationReviewResult struct {
+	PodName string `json:"podName"`
+	// numbers of replicas on nodes
+	ReplicasOnNodes []ReplicasOnNode `json:"replicasOnNodes"`
+	// reason why no more pods could schedule (if any on this node)
+	Summary []StopReasonSummary `json:"summary"`
+}
+
+type ReplicasOnNode struct {
+	NodeName string `json:"nodeName"`
+	Replicas int    `json:"replicas"`
+}
+
+type StopReasonSummary struct {
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterCompressionReview is the result of cc, which nodes could be removed from the cluster
+type ClusterCompressionReview struct {
+	metav1.TypeMeta `json:",inline"`
+
+	Status ClusterCompressionReviewStatus `json:"status"`
+}
+
+type ClusterCompressionReviewStatus struct {
+	CreationTimestamp  metav1.Time `json:"creationTimestamp"`
+	StopReason         *StopReason `json:"stopReason"`
+	ScaleDownNodeNames []string    `json:"scaleDownNodeNames"`
+	// requested resources of the nodes after the nodes are scaled down
+	Nodes []NodeUtilization `json:"nodes,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SchedulerSimulationReview is the result of ss, how all the pods of the cluster are placed by the scheduler
+type SchedulerSimulationReview struct {
+	metav1.TypeMeta `json:",inline"`
+
+	UnschedulablePods []corev1.Pod     `json:"unschedulablePods"`
+	Details           []ScheduleDetail `json:"details"`
+	StopReason        string           `json:"stopReason"`
+	ReplayOrder       string           `json:"replayOrder"`
+	ReplaySeed        int64            `json:"replaySeed,omitempty"`
+	Metrics           PlacementMetrics `json:"metrics"`
+}
+
+type PlacementMetrics struct {
+	NodesUsed               int     `json:"nodesUsed"`
+	OnlyDSPodNodes          int     `json:"onlyDSPodNodes"`
+	NodesLowerBound         int     `json:"nodesLowerBound"`
+	CPUPackingEfficiency    float64 `json:"cpuPackingEfficiency"`
+	MemoryPackingEfficiency float64 `json:"memoryPackingEfficiency"`
+	CPUUtilizationStdDev    float64 `json:"cpuUtilizationStdDev"`
+	MemoryUtilizationStdDev float64 `json:"memoryUtilizationStdDev"`
+}
+
+type ScheduleDetail struct {
+	NodeName             string              `json:"nodeName"`
+	Zone                 string              `json:"zone,omitempty"`
+	Replicas             int                 `json:"replicas"`
+	NodeAllocatable      corev1.ResourceList `json:"nodeAllocatable"`
+	PodRequest           Resource            `json:"podRequest"`
+	OnlyDSPod            bool                `json:"onlyDSPod"`
+	CPURequestedRatio    float64             `json:"cpuRequestedRatio"`
+	MemoryRequestedRatio float64             `json:"memoryRequestedRatio"`
+}
@@ -2,5 +2,7 @@ package pkg
 
 const (
 	PodProvisioner = "kc.k-cloud-labs.io/provisioned-by"
-	SchedulerName  = "simulator-scheduler"
+	// PodAdmitted is the annotation of the pods admitted by the simulation, they aren't admitted again
+	PodAdmitted   = "kc.k-cloud-labs.io/admitted"
+	SchedulerName = "simulator-scheduler"
 )
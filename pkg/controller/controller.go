@@ -0,0 +1,267 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/controller/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/apis/capacity/v1alpha1"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/framework"
+)
+
+// reviewKey identifies a review in the queue
+type reviewKey struct {
+	resource  string
+	namespace string
+	name      string
+}
+
+// reviewKind describes how the reviews of a resource are evaluated
+type reviewKind struct {
+	resource  string
+	newReview func() runtime.Object
+	// runs the simulation of the review and writes the results to its status
+	evaluate func(c *Controller, review runtime.Object, objs []runtime.Object) error
+	informer informers.GenericInformer
+}
+
+// Controller reconciles the reviews submitted to the cluster by running the simulations in a fresh world
+// initialized from the cached objects, and evaluates them again periodically.
+type Controller struct {
+	conf   *options.ControllerConfig
+	source framework.ObjectSource
+	client dynamic.Interface
+	queue  workqueue.RateLimitingInterface
+	kinds  map[string]*reviewKind
+}
+
+func NewController(conf *options.ControllerConfig, client dynamic.Interface, source framework.ObjectSource) *Controller {
+	c := &Controller{
+		conf:   conf,
+		source: source,
+		client: client,
+		queue:  workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		kinds:  make(map[string]*reviewKind),
+	}
+
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(client, 0)
+	for _, kind := range []*reviewKind{
+		{
+			resource:  "capacityestimationreviews",
+			newReview: func() runtime.Object { return &v1alpha1.CapacityEstimationReview{} },
+			evaluate:  (*Controller).evaluateCapacityEstimation,
+		},
+		{
+			resource:  "clustercompressionreviews",
+			newReview: func() runtime.Object { return &v1alpha1.ClusterCompressionReview{} },
+			evaluate:  (*Controller).evaluateClusterCompression,
+		},
+		{
+			resource:  "schedulersimulationreviews",
+			newReview: func() runtime.Object { return &v1alpha1.SchedulerSimulationReview{} },
+			evaluate:  (*Controller).evaluateSchedulerSimulation,
+		},
+	} {
+		kind.informer = factory.ForResource(v1alpha1.SchemeGroupVersion.WithResource(kind.resource))
+		resource := kind.resource
+		kind.informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				c.enqueue(resource, obj)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				c.enqueue(resource, newObj)
+			},
+		})
+		c.kinds[kind.resource] = kind
+	}
+
+	return c
+}
+
+// Run reconciles the reviews until the context is done
+func (c *Controller) Run(ctx context.Context) error {
+	defer c.queue.ShutDown()
+
+	// the informers never sync if the crds are not installed
+	for resource := range c.kinds {
+		_, err := c.client.Resource(v1alpha1.SchemeGroupVersion.WithResource(resource)).List(ctx, metav1.ListOptions{Limit: 1})
+		if err != nil {
+			return fmt.Errorf("unable to list %s, make sure the crds are installed: %v", resource, err)
+		}
+	}
+
+	for resource, kind := range c.kinds {
+		go kind.informer.Informer().Run(ctx.Done())
+		if !cache.WaitForCacheSync(ctx.Done(), kind.informer.Informer().HasSynced) {
+			return fmt.Errorf("unable to sync the cache of %s", resource)
+		}
+	}
+
+	for i := 0; i < c.conf.Options.Concurrency; i++ {
+		go wait.UntilWithContext(ctx, c.work, time.Second)
+	}
+
+	klog.InfoS("Reconciling reviews", "concurrency", c.conf.Options.Concurrency)
+	<-ctx.Done()
+
+	return nil
+}
+
+func (c *Controller) enqueue(resource string, obj interface{}) {
+	review, err := meta.Accessor(obj)
+	if err != nil {
+		klog.ErrorS(err, "Unable to get the meta of the review", "resource", resource)
+		return
+	}
+
+	c.queue.Add(reviewKey{
+		resource:  resource,
+		namespace: review.GetNamespace(),
+		name:      review.GetName(),
+	})
+}
+
+func (c *Controller) work(ctx context.Context) {
+	for c.processNextItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextItem(ctx context.Context) bool {
+	item, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.queue.Done(item)
+
+	key := item.(reviewKey)
+	err := c.reconcile(ctx, key)
+	if err != nil {
+		klog.ErrorS(err, "Unable to reconcile the review", "resource", key.resource, "namespace", key.namespace, "name", key.name)
+		c.queue.AddRateLimited(item)
+		return true
+	}
+
+	c.queue.Forget(item)
+	return true
+}
+
+// reconcile evaluates the review if its spec is changed or the resync period is passed, otherwise it's
+// queued again when the next evaluation is due.
+func (c *Controller) reconcile(ctx context.Context, key reviewKey) error {
+	kind := c.kinds[key.resource]
+	obj, err := kind.informer.Lister().ByNamespace(key.namespace).Get(key.name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	review := kind.newReview()
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.(*unstructured.Unstructured).UnstructuredContent(), review)
+	if err != nil {
+		return err
+	}
+	objectMeta, err := meta.Accessor(review)
+	if err != nil {
+		return err
+	}
+	status, resyncPeriod := c.reviewStatus(review)
+
+	if delay := nextEvaluation(objectMeta.GetGeneration(), status, resyncPeriod); delay > 0 {
+		c.queue.AddAfter(key, delay)
+		return nil
+	}
+
+	condition := metav1.Condition{
+		Type:               v1alpha1.ConditionEvaluated,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: objectMeta.GetGeneration(),
+		Reason:             v1alpha1.ReasonSimulationSucceeded,
+		Message:            "The simulation succeeded",
+	}
+	err = c.evaluateSafely(kind, review)
+	if err != nil {
+		klog.ErrorS(err, "Simulation failed", "resource", key.resource, "namespace", key.namespace, "name", key.name)
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = v1alpha1.ReasonSimulationFailed
+		condition.Message = err.Error()
+	}
+	now := metav1.Now()
+	status.ObservedGeneration = objectMeta.GetGeneration()
+	status.LastEvaluationTime = &now
+	meta.SetStatusCondition(&status.Conditions, condition)
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(review)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.Resource(v1alpha1.SchemeGroupVersion.WithResource(key.resource)).Namespace(key.namespace).
+		UpdateStatus(ctx, &unstructured.Unstructured{Object: content}, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+
+	c.queue.AddAfter(key, resyncPeriod)
+	return nil
+}
+
+// evaluateSafely keeps the controller running when a simulation panics
+func (c *Controller) evaluateSafely(kind *reviewKind, review runtime.Object) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("simulation panicked: %v", r)
+		}
+	}()
+
+	objs, err := c.source.Objects()
+	if err != nil {
+		return err
+	}
+
+	return kind.evaluate(c, review, objs)
+}
+
+// reviewStatus returns the common status and the resync period of the review
+func (c *Controller) reviewStatus(review runtime.Object) (*v1alpha1.ReviewStatus, time.Duration) {
+	var (
+		status       *v1alpha1.ReviewStatus
+		resyncPeriod *metav1.Duration
+	)
+	switch r := review.(type) {
+	case *v1alpha1.CapacityEstimationReview:
+		status, resyncPeriod = &r.Status.ReviewStatus, r.Spec.ResyncPeriod
+	case *v1alpha1.ClusterCompressionReview:
+		status, resyncPeriod = &r.Status.ReviewStatus, r.Spec.ResyncPeriod
+	case *v1alpha1.SchedulerSimulationReview:
+		status, resyncPeriod = &r.Status.ReviewStatus, r.Spec.ResyncPeriod
+	}
+
+	if resyncPeriod == nil || resyncPeriod.Duration <= 0 {
+		return status, c.conf.Options.ResyncPeriod
+	}
+	return status, resyncPeriod.Duration
+}
+
+// nextEvaluation returns how long to wait before the review is evaluated again
+func nextEvaluation(generation int64, status *v1alpha1.ReviewStatus, resyncPeriod time.Duration) time.Duration {
+	if status.ObservedGeneration != generation || status.LastEvaluationTime == nil {
+		return 0
+	}
+
+	return time.Until(status.LastEvaluationTime.Add(resyncPeriod))
+}
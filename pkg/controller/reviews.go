@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	ceoptions "github.com/k-cloud-labs/kluster-capacity/app/cmds/capacityestimation/options"
+	ccoptions "github.com/k-cloud-labs/kluster-capacity/app/cmds/clustercompression/options"
+	ssoptions "github.com/k-cloud-labs/kluster-capacity/app/cmds/schedulersimulation/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/apis/capacity/v1alpha1"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/plugins/replayorder"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/capacityestimation"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/clustercompression"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/schedulersimulation"
+)
+
+func (c *Controller) evaluateCapacityEstimation(obj runtime.Object, objs []runtime.Object) error {
+	review := obj.(*v1alpha1.CapacityEstimationReview)
+	// the results of the last evaluation are outdated whether the simulation succeeds or not
+	review.Status = v1alpha1.CapacityEstimationReviewStatus{ReviewStatus: review.Status.ReviewStatus}
+
+	spec := review.Spec
+	if len(spec.Templates) == 0 {
+		return errors.New("at least one template must be specified")
+	}
+	if spec.BatchSize == 0 {
+		spec.BatchSize = 1
+	}
+	if spec.BatchSize < 0 {
+		return errors.New("batch size must be greater than 0")
+	}
+	if spec.BatchSize > 1 && spec.EnablePreemption {
+		return errors.New("batch size and enable preemption is exclusive")
+	}
+
+	opt := ceoptions.NewCapacityEstimationOptions()
+	opt.SchedulerConfig = c.conf.Options.SchedulerConfig
+	opt.MaxLimit = spec.MaxLimit
+	opt.ExcludeNodes = spec.ExcludeNodes
+	opt.EnablePreemption = spec.EnablePreemption
+	opt.BatchSize = spec.BatchSize
+	conf := ceoptions.NewCapacityEstimationConfig(opt)
+	conf.InitObjs = objs
+	for i, template := range spec.Templates {
+		pod := &corev1.Pod{
+			ObjectMeta: *template.ObjectMeta.DeepCopy(),
+			Spec:       *template.Spec.DeepCopy(),
+		}
+		if len(pod.Name) == 0 {
+			pod.Name = fmt.Sprintf("%s-%d", review.Name, i)
+		}
+		if len(pod.Namespace) == 0 {
+			pod.Namespace = review.Namespace
+		}
+		conf.Pods = append(conf.Pods, pod)
+	}
+
+	sim, err := capacityestimation.NewCESimulatorExecutor(conf)
+	if err != nil {
+		return err
+	}
+	report, err := simulator.Run(sim, objs)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range report.(capacityestimation.CapacityEstimationReviews) {
+		result := v1alpha1.CapacityEstimationResult{
+			PodName:  r.Spec.Templates[0].Name,
+			Replicas: r.Status.Replicas,
+		}
+		if r.Status.Preemption != nil {
+			replicas := r.Status.Preemption.ReplicasWithoutPreemption
+			result.ReplicasWithoutPreemption = &replicas
+		}
+		if r.Status.StopReason != nil {
+			result.StopReason = &v1alpha1.StopReason{
+				StopType:    r.Status.StopReason.StopType,
+				StopMessage: r.Status.StopReason.StopMessage,
+			}
+		}
+		for _, pods := range r.Status.Pods {
+			for _, replicas := range pods.ReplicasOnNodes {
+				result.ReplicasOnNodes = append(result.ReplicasOnNodes, v1alpha1.ReplicasOnNode{
+					NodeName: replicas.NodeName,
+					Replicas: int32(replicas.Replicas),
+				})
+			}
+		}
+		review.Status.Results = append(review.Status.Results, result)
+	}
+
+	return nil
+}
+
+func (c *Controller) evaluateClusterCompression(obj runtime.Object, objs []runtime.Object) error {
+	review := obj.(*v1alpha1.ClusterCompressionReview)
+	review.Status = v1alpha1.ClusterCompressionReviewStatus{ReviewStatus: review.Status.ReviewStatus}
+
+	spec := review.Spec
+	opt := ccoptions.NewClusterCompressionOptions()
+	opt.SchedulerConfig = c.conf.Options.SchedulerConfig
+	opt.MaxLimit = spec.MaxLimit
+	opt.ExcludeNodes = spec.ExcludeNodes
+	// the defaults are the same as the ones of the flags
+	opt.FilterNodeOptions = ccoptions.FilterNodeOptions{
+		ExcludeNotReadyNode: boolOrDefault(spec.ExcludeNotReadyNode, true),
+		ExcludeTaintNode:    boolOrDefault(spec.ExcludeTaintNode, true),
+		IgnoreStaticPod:     boolOrDefault(spec.IgnoreStaticPod, true),
+		IgnoreMirrorPod:     boolOrDefault(spec.IgnoreMirrorPod, false),
+		IgnoreCloneSet:      boolOrDefault(spec.IgnoreCloneSet, false),
+		IgnoreVolumePod:     boolOrDefault(spec.IgnoreVolumePod, false),
+	}
+	conf := ccoptions.NewClusterCompressionConfig(opt)
+	conf.InitObjs = objs
+
+	sim, err := clustercompression.NewCCSimulatorExecutor(conf)
+	if err != nil {
+		return err
+	}
+	report, err := simulator.Run(sim, objs)
+	if err != nil {
+		return err
+	}
+
+	r := report.(*clustercompression.ClusterCompressionReview)
+	if r.Status.StopReason != nil {
+		review.Status.StopReason = &v1alpha1.StopReason{
+			StopType:    r.Status.StopReason.StopType,
+			StopMessage: r.Status.StopReason.StopMessage,
+		}
+	}
+	review.Status.ScaleDownNodeNames = r.Status.ScaleDownNodeNames
+
+	return nil
+}
+
+func (c *Controller) evaluateSchedulerSimulation(obj runtime.Object, objs []runtime.Object) error {
+	review := obj.(*v1alpha1.SchedulerSimulationReview)
+	review.Status = v1alpha1.SchedulerSimulationReviewStatus{ReviewStatus: review.Status.ReviewStatus}
+
+	spec := review.Spec
+	if len(spec.ExitCondition) == 0 {
+		spec.ExitCondition = ssoptions.ExitWhenAllSucceed
+	}
+	if spec.ExitCondition != ssoptions.ExitWhenAllSucceed && spec.ExitCondition != ssoptions.ExitWhenAllScheduled {
+		return errors.New("exit condition must be AllSucceed or AllScheduled")
+	}
+	if spec.Timeout != nil && spec.Timeout.Duration < 0 {
+		return errors.New("timeout must not be negative")
+	}
+	if len(spec.ReplayOrder) > 0 {
+		if _, err := replayorder.NewFactory(spec.ReplayOrder, spec.ReplaySeed); err != nil {
+			return fmt.Errorf("invalid replay order: %v", err)
+		}
+	}
+
+	opt := ssoptions.NewSchedulerSimulationOptions()
+	opt.SchedulerConfig = c.conf.Options.SchedulerConfig
+	opt.ExitCondition = spec.ExitCondition
+	opt.ExcludeNodes = spec.ExcludeNodes
+	opt.IgnorePodsOnExcludeNodes = boolOrDefault(spec.IgnorePodsOnExcludeNodes, true)
+	opt.ReplayOrder = spec.ReplayOrder
+	opt.ReplaySeed = spec.ReplaySeed
+	if spec.Timeout != nil {
+		opt.Timeout = spec.Timeout.Duration
+	}
+	conf := ssoptions.NewSchedulerSimulationConfig(opt)
+	conf.InitObjs = objs
+
+	sim, err := schedulersimulation.NewSSSimulatorExecutor(conf)
+	if err != nil {
+		return err
+	}
+	report, err := simulator.Run(sim, objs)
+	if err != nil {
+		return err
+	}
+
+	r := report.(*schedulersimulation.SchedulerSimulationReview)
+	review.Status.StopReason = r.StopReason
+	review.Status.NodesUsed = int32(r.Metrics.NodesUsed)
+	review.Status.OnlyDSPodNodes = int32(r.Metrics.OnlyDSPodNodes)
+	review.Status.NodesLowerBound = int32(r.Metrics.NodesLowerBound)
+	review.Status.CPUPackingEfficiency = strconv.FormatFloat(r.Metrics.CPUPackingEfficiency, 'f', 4, 64)
+	review.Status.MemoryPackingEfficiency = strconv.FormatFloat(r.Metrics.MemoryPackingEfficiency, 'f', 4, 64)
+	for _, pod := range r.UnschedulablePods {
+		review.Status.UnschedulablePods = append(review.Status.UnschedulablePods, pod.Namespace+"/"+pod.Name)
+	}
+
+	return nil
+}
+
+func boolOrDefault(b *bool, defaultValue bool) bool {
+	if b == nil {
+		return defaultValue
+	}
+	return *b
+}
@@ -0,0 +1,129 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	outputv1 "github.com/k-cloud-labs/kluster-capacity/pkg/apis/output/v1"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/history"
+)
+
+// Diff compares two results of the same command converted to the hub version, only the changes are returned
+func Diff(oldReview, newReview conversion.Hub) (*DiffReview, error) {
+	oldCommand, newCommand := command(oldReview), command(newReview)
+	if oldCommand != newCommand {
+		return nil, fmt.Errorf("can't compare the result of %s with the one of %s", oldCommand, newCommand)
+	}
+
+	r := &DiffReview{Command: oldCommand}
+	switch o := oldReview.(type) {
+	case *outputv1.CapacityEstimationReview:
+		n := newReview.(*outputv1.CapacityEstimationReview)
+		r.Templates = replicasDiffs(templateReplicas(o), templateReplicas(n))
+		r.Nodes = replicasDiffs(templateNodeReplicas(o), templateNodeReplicas(n))
+	case *outputv1.ClusterCompressionReview:
+		n := newReview.(*outputv1.ClusterCompressionReview)
+		oldNodes := sets.New[string](o.ScaleDownNodes...)
+		newNodes := sets.New[string](n.ScaleDownNodes...)
+		r.NewlyRemovableNodes = sets.List(newNodes.Difference(oldNodes))
+		r.NewlyBlockedNodes = sets.List(oldNodes.Difference(newNodes))
+	case *outputv1.SchedulerSimulationReview:
+		n := newReview.(*outputv1.SchedulerSimulationReview)
+		r.Nodes = replicasDiffs(nodeReplicas(o), nodeReplicas(n))
+		oldPods, newPods := unschedulablePods(o), unschedulablePods(n)
+		r.NewlyUnschedulablePods = sets.List(newPods.Difference(oldPods))
+		r.NewlySchedulablePods = sets.List(oldPods.Difference(newPods))
+	default:
+		return nil, fmt.Errorf("review %T is not supported", oldReview)
+	}
+
+	return r, nil
+}
+
+func command(review conversion.Hub) string {
+	switch review.(type) {
+	case *outputv1.CapacityEstimationReview:
+		return history.CommandCapacityEstimation
+	case *outputv1.ClusterCompressionReview:
+		return history.CommandClusterCompression
+	case *outputv1.SchedulerSimulationReview:
+		return history.CommandSchedulerSimulation
+	default:
+		return ""
+	}
+}
+
+type replicasKey struct {
+	template string
+	node     string
+}
+
+// replicasDiffs returns the replicas changed, missing ones are counted as 0
+func replicasDiffs(oldReplicas, newReplicas map[replicasKey]int) []ReplicasDiff {
+	keys := make(map[replicasKey]bool)
+	for key := range oldReplicas {
+		keys[key] = true
+	}
+	for key := range newReplicas {
+		keys[key] = true
+	}
+
+	var result []ReplicasDiff
+	for key := range keys {
+		if oldReplicas[key] == newReplicas[key] {
+			continue
+		}
+		result = append(result, ReplicasDiff{
+			Template: key.template,
+			Node:     key.node,
+			Old:      oldReplicas[key],
+			New:      newReplicas[key],
+			Delta:    newReplicas[key] - oldReplicas[key],
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Template != result[j].Template {
+			return result[i].Template < result[j].Template
+		}
+		return result[i].Node < result[j].Node
+	})
+
+	return result
+}
+
+func templateReplicas(review *outputv1.CapacityEstimationReview) map[replicasKey]int {
+	result := make(map[replicasKey]int)
+	for _, template := range review.Templates {
+		result[replicasKey{template: template.Name}] += int(template.Replicas)
+	}
+	return result
+}
+
+func templateNodeReplicas(review *outputv1.CapacityEstimationReview) map[replicasKey]int {
+	result := make(map[replicasKey]int)
+	for _, template := range review.Templates {
+		for _, node := range template.ReplicasOnNodes {
+			result[replicasKey{template: template.Name, node: node.NodeName}] += int(node.Replicas)
+		}
+	}
+	return result
+}
+
+func nodeReplicas(review *outputv1.SchedulerSimulationReview) map[replicasKey]int {
+	result := make(map[replicasKey]int)
+	for _, node := range review.Nodes {
+		result[replicasKey{node: node.Name}] += int(node.Pods)
+	}
+	return result
+}
+
+func unschedulablePods(review *outputv1.SchedulerSimulationReview) sets.Set[string] {
+	result := sets.New[string]()
+	for _, pod := range review.UnschedulablePods {
+		result.Insert(pod.Namespace + "/" + pod.Name)
+	}
+	return result
+}
@@ -0,0 +1,114 @@
+package diff
+
+import (
+	"fmt"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/k-cloud-labs/kluster-capacity/pkg/history"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+// DiffReview is what changed between two results of the same command
+type DiffReview struct {
+	// ce, cc or ss
+	Command string `json:"command"`
+	// files of the results
+	Old string `json:"old"`
+	New string `json:"new"`
+
+	// replicas of the templates of ce
+	Templates []ReplicasDiff `json:"templates,omitempty"`
+	// replicas on the nodes, of each template for ce and of all the pods for ss
+	Nodes []ReplicasDiff `json:"nodes,omitempty"`
+	// nodes which can be scaled down by cc only in the new result
+	NewlyRemovableNodes []string `json:"newlyRemovableNodes,omitempty"`
+	// nodes which can be scaled down by cc only in the old result
+	NewlyBlockedNodes []string `json:"newlyBlockedNodes,omitempty"`
+	// pods unschedulable by ss only in the new result
+	NewlyUnschedulablePods []string `json:"newlyUnschedulablePods,omitempty"`
+	// pods unschedulable by ss only in the old result
+	NewlySchedulablePods []string `json:"newlySchedulablePods,omitempty"`
+}
+
+// ReplicasDiff is the change of the replicas of a template, on a node or both
+type ReplicasDiff struct {
+	Template string `json:"template,omitempty"`
+	Node     string `json:"node,omitempty"`
+	Old      int    `json:"old"`
+	New      int    `json:"new"`
+	Delta    int    `json:"delta"`
+}
+
+// Changed returns true if anything changed between the results
+func (r *DiffReview) Changed() bool {
+	return len(r.Templates) > 0 || len(r.Nodes) > 0 || len(r.NewlyRemovableNodes) > 0 || len(r.NewlyBlockedNodes) > 0 ||
+		len(r.NewlyUnschedulablePods) > 0 || len(r.NewlySchedulablePods) > 0
+}
+
+func (r *DiffReview) Print(verbose bool, format string) error {
+	switch format {
+	case "json":
+		return utils.PrintJson(r)
+	case "yaml":
+		return utils.PrintYaml(r)
+	case "":
+		diffPrettyPrint(r, verbose)
+		return nil
+	default:
+		return fmt.Errorf("output format %q not recognized", format)
+	}
+}
+
+func diffPrettyPrint(r *DiffReview, verbose bool) {
+	if verbose {
+		fmt.Printf("Comparing the %s result %s with %s.\n\n", r.Command, r.New, r.Old)
+	}
+
+	if !r.Changed() {
+		fmt.Println("No changes.")
+		return
+	}
+
+	if len(r.Templates) > 0 {
+		t := table.NewWriter()
+		t.AppendHeader(table.Row{"template", "old", "new", "delta"})
+		for _, d := range r.Templates {
+			t.AppendRow(table.Row{d.Template, d.Old, d.New, fmt.Sprintf("%+d", d.Delta)})
+		}
+		fmt.Println(t.Render())
+	}
+
+	if len(r.Nodes) > 0 {
+		t := table.NewWriter()
+		if r.Command == history.CommandCapacityEstimation {
+			t.AppendHeader(table.Row{"template", "node", "old", "new", "delta"})
+		} else {
+			t.AppendHeader(table.Row{"node", "old", "new", "delta"})
+		}
+		for _, d := range r.Nodes {
+			if r.Command == history.CommandCapacityEstimation {
+				t.AppendRow(table.Row{d.Template, d.Node, d.Old, d.New, fmt.Sprintf("%+d", d.Delta)})
+			} else {
+				t.AppendRow(table.Row{d.Node, d.Old, d.New, fmt.Sprintf("%+d", d.Delta)})
+			}
+		}
+		fmt.Println(t.Render())
+	}
+
+	printList("Nodes newly removable", r.NewlyRemovableNodes)
+	printList("Nodes newly blocked", r.NewlyBlockedNodes)
+	printList("Pods newly unschedulable", r.NewlyUnschedulablePods)
+	printList("Pods newly schedulable", r.NewlySchedulablePods)
+}
+
+func printList(title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+
+	fmt.Printf("%s:\n", title)
+	for _, item := range items {
+		fmt.Printf("\t- %s\n", item)
+	}
+}
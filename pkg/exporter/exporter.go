@@ -0,0 +1,176 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	ceoptions "github.com/k-cloud-labs/kluster-capacity/app/cmds/capacityestimation/options"
+	ccoptions "github.com/k-cloud-labs/kluster-capacity/app/cmds/clustercompression/options"
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/exporter/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/framework"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/capacityestimation"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/clustercompression"
+)
+
+// Exporter runs ce for the reference templates and cc for the cluster periodically, and exposes the results
+// as prometheus metrics.
+type Exporter struct {
+	conf     *options.ExporterConfig
+	source   framework.ObjectSource
+	registry *prometheus.Registry
+	metrics  *metrics
+}
+
+func NewExporter(conf *options.ExporterConfig, source framework.ObjectSource) *Exporter {
+	registry := prometheus.NewRegistry()
+	return &Exporter{
+		conf:     conf,
+		source:   source,
+		registry: registry,
+		metrics:  newMetrics(registry),
+	}
+}
+
+// Run serves the metrics and runs the simulations until the context is done
+func (e *Exporter) Run(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(e.registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	server := &http.Server{
+		Addr:    e.conf.Options.Address,
+		Handler: mux,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	go wait.UntilWithContext(ctx, e.collect, e.conf.Options.Interval)
+
+	klog.InfoS("Serving metrics", "address", e.conf.Options.Address)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// collect runs the simulations in worlds initialized from the same objects
+func (e *Exporter) collect(ctx context.Context) {
+	objs, err := e.source.Objects()
+	if err != nil {
+		klog.ErrorS(err, "Unable to get the objects of the cluster")
+		now := time.Now()
+		e.record(simulationCapacityEstimation, now, err)
+		e.record(simulationClusterCompression, now, err)
+		return
+	}
+
+	if len(e.conf.Pods) > 0 {
+		start := time.Now()
+		e.record(simulationCapacityEstimation, start, e.runCapacityEstimation(objs))
+	}
+
+	start := time.Now()
+	e.record(simulationClusterCompression, start, e.runClusterCompression(objs))
+}
+
+func (e *Exporter) record(simulation string, start time.Time, err error) {
+	now := time.Now()
+	e.metrics.lastRunDuration.WithLabelValues(simulation).Set(now.Sub(start).Seconds())
+	e.metrics.lastRunTimestamp.WithLabelValues(simulation).Set(float64(now.Unix()))
+	if err != nil {
+		klog.ErrorS(err, "Simulation failed", "simulation", simulation)
+		e.metrics.lastRunSuccess.WithLabelValues(simulation).Set(0)
+		return
+	}
+	e.metrics.lastRunSuccess.WithLabelValues(simulation).Set(1)
+}
+
+func (e *Exporter) runCapacityEstimation(objs []runtime.Object) error {
+	opt := ceoptions.NewCapacityEstimationOptions()
+	opt.SchedulerConfig = e.conf.Options.SchedulerConfig
+	opt.ExcludeNodes = e.conf.Options.ExcludeNodes
+	conf := ceoptions.NewCapacityEstimationConfig(opt)
+	conf.InitObjs = objs
+	for _, pod := range e.conf.Pods {
+		conf.Pods = append(conf.Pods, pod.DeepCopy())
+	}
+
+	sim, err := capacityestimation.NewCESimulatorExecutor(conf)
+	if err != nil {
+		return err
+	}
+	report, err := simulator.Run(sim, objs)
+	if err != nil {
+		return err
+	}
+
+	nodeLabels := make(map[string]map[string]string)
+	for _, obj := range objs {
+		if node, ok := obj.(*corev1.Node); ok {
+			nodeLabels[node.Name] = node.Labels
+		}
+	}
+
+	// the zones and node groups without any replica left are not reported
+	e.metrics.templateReplicas.Reset()
+	e.metrics.templateZoneReplicas.Reset()
+	e.metrics.templateNodeGroupReplicas.Reset()
+	for _, review := range report.(capacityestimation.CapacityEstimationReviews) {
+		template := review.Spec.Templates[0].Name
+		e.metrics.templateReplicas.WithLabelValues(template).Set(float64(review.Status.Replicas))
+		for _, pods := range review.Status.Pods {
+			for _, replicas := range pods.ReplicasOnNodes {
+				labels := nodeLabels[replicas.NodeName]
+				e.metrics.templateZoneReplicas.WithLabelValues(template, labels[e.conf.Options.ZoneLabel]).Add(float64(replicas.Replicas))
+				if len(e.conf.Options.NodeGroupLabel) > 0 {
+					e.metrics.templateNodeGroupReplicas.WithLabelValues(template, labels[e.conf.Options.NodeGroupLabel]).Add(float64(replicas.Replicas))
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (e *Exporter) runClusterCompression(objs []runtime.Object) error {
+	opt := ccoptions.NewClusterCompressionOptions()
+	opt.SchedulerConfig = e.conf.Options.SchedulerConfig
+	opt.ExcludeNodes = e.conf.Options.ExcludeNodes
+	// the same as the defaults of the flags of cc
+	opt.FilterNodeOptions = ccoptions.FilterNodeOptions{
+		ExcludeNotReadyNode: true,
+		ExcludeTaintNode:    true,
+		IgnoreStaticPod:     true,
+	}
+	conf := ccoptions.NewClusterCompressionConfig(opt)
+	conf.InitObjs = objs
+
+	sim, err := clustercompression.NewCCSimulatorExecutor(conf)
+	if err != nil {
+		return err
+	}
+	report, err := simulator.Run(sim, objs)
+	if err != nil {
+		return err
+	}
+
+	e.metrics.removableNodes.Set(float64(len(report.(*clustercompression.ClusterCompressionReview).Status.ScaleDownNodeNames)))
+
+	return nil
+}
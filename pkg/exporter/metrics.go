@@ -0,0 +1,74 @@
+package exporter
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	namespace = "kluster_capacity"
+
+	simulationCapacityEstimation = "ce"
+	simulationClusterCompression = "cc"
+)
+
+type metrics struct {
+	templateReplicas          *prometheus.GaugeVec
+	templateZoneReplicas      *prometheus.GaugeVec
+	templateNodeGroupReplicas *prometheus.GaugeVec
+	removableNodes            prometheus.Gauge
+	lastRunDuration           *prometheus.GaugeVec
+	lastRunSuccess            *prometheus.GaugeVec
+	lastRunTimestamp          *prometheus.GaugeVec
+}
+
+func newMetrics(registry prometheus.Registerer) *metrics {
+	m := &metrics{
+		templateReplicas: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "template_replicas",
+			Help:      "Number of replicas of the reference template the cluster could still hold.",
+		}, []string{"template"}),
+		templateZoneReplicas: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "template_zone_replicas",
+			Help:      "Number of replicas of the reference template the nodes of the zone could still hold.",
+		}, []string{"template", "zone"}),
+		templateNodeGroupReplicas: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "template_node_group_replicas",
+			Help:      "Number of replicas of the reference template the nodes of the node group could still hold.",
+		}, []string{"template", "node_group"}),
+		removableNodes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "removable_nodes",
+			Help:      "Number of nodes which could be removed from the cluster.",
+		}),
+		lastRunDuration: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "last_run_duration_seconds",
+			Help:      "Duration of the last run of the simulation.",
+		}, []string{"simulation"}),
+		lastRunSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "last_run_success",
+			Help:      "Whether the last run of the simulation succeeded, the capacity metrics are kept from the last successful run.",
+		}, []string{"simulation"}),
+		lastRunTimestamp: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "last_run_timestamp_seconds",
+			Help:      "Unix time when the last run of the simulation finished.",
+		}, []string{"simulation"}),
+	}
+
+	registry.MustRegister(
+		m.templateReplicas,
+		m.templateZoneReplicas,
+		m.templateNodeGroupReplicas,
+		m.removableNodes,
+		m.lastRunDuration,
+		m.lastRunSuccess,
+		m.lastRunTimestamp,
+	)
+
+	return m
+}
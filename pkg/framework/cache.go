@@ -0,0 +1,180 @@
+package framework
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/informers"
+	clientset "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// ObjectSource provides the objects used to init the world of each simulation
+type ObjectSource interface {
+	Objects() ([]runtime.Object, error)
+}
+
+// StaticSource is a source of objects which never change, e.g. the objects loaded from a snapshot
+type StaticSource []runtime.Object
+
+func (s StaticSource) Objects() ([]runtime.Object, error) {
+	return s, nil
+}
+
+// ClusterCache keeps the objects used to init the world up to date with informers, so that a world can be
+// created at any time without listing the objects from the cluster again.
+type ClusterCache struct {
+	factory   informers.SharedInformerFactory
+	informers []informers.GenericInformer
+}
+
+// NewClusterCache creates the informers of the init resources served by the cluster, Start must be called
+// before the objects are read.
+func NewClusterCache(restConfig *restclient.Config) (*ClusterCache, error) {
+	client, err := clientset.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	restMapper, err := apiutil.NewDynamicRESTMapper(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &ClusterCache{
+		factory: informers.NewSharedInformerFactory(client, 0),
+	}
+	for gvk, newObj := range initResources {
+		// the resource is disabled by a feature gate
+		if newObj() == nil {
+			continue
+		}
+
+		restMapping, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, fmt.Errorf("unable to get rest mapping for %s: %v", gvk.String(), err)
+		}
+
+		informer, err := c.factory.ForResource(restMapping.Resource)
+		if err != nil {
+			return nil, err
+		}
+		c.informers = append(c.informers, informer)
+	}
+
+	return c, nil
+}
+
+// Start starts the informers and waits until their caches are synced.
+func (c *ClusterCache) Start(stopCh <-chan struct{}) error {
+	c.factory.Start(stopCh)
+	for gvr, synced := range c.factory.WaitForCacheSync(stopCh) {
+		if !synced {
+			return fmt.Errorf("unable to sync the cache of %s", gvr.String())
+		}
+	}
+
+	return nil
+}
+
+// Objects returns the typed objects used to init the world, they are shared with the informers and must
+// not be modified.
+func (c *ClusterCache) Objects() ([]runtime.Object, error) {
+	var objs []runtime.Object
+	for _, informer := range c.informers {
+		list, err := informer.Lister().List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range list {
+			// the same as the field selector used to list the pods from the cluster
+			if pod, ok := obj.(*corev1.Pod); ok && (pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed) {
+				continue
+			}
+			objs = append(objs, obj)
+		}
+	}
+
+	return objs, nil
+}
+
+// GetInitObjectsFromFile reads the typed objects used to init the world from a JSON or YAML file, which may
+// contain several documents and lists, e.g. the output of kubectl get -o yaml. Objects of the resources not
+// used to init the world are ignored.
+func GetInitObjectsFromFile(path string) ([]runtime.Object, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var objs []runtime.Object
+	decoder := yaml.NewYAMLOrJSONDecoder(file, 4096)
+	for {
+		u := &unstructured.Unstructured{}
+		if err := decoder.Decode(&u.Object); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("failed to decode %s: %v", path, err)
+		}
+		if len(u.Object) == 0 {
+			continue
+		}
+
+		if u.IsList() {
+			list, err := u.ToList()
+			if err != nil {
+				return nil, err
+			}
+			for i := range list.Items {
+				obj, err := toTypedInitObject(&list.Items[i])
+				if err != nil {
+					return nil, err
+				}
+				if obj != nil {
+					objs = append(objs, obj)
+				}
+			}
+			continue
+		}
+
+		obj, err := toTypedInitObject(u)
+		if err != nil {
+			return nil, err
+		}
+		if obj != nil {
+			objs = append(objs, obj)
+		}
+	}
+
+	return objs, nil
+}
+
+// toTypedInitObject returns nil if the object is not used to init the world
+func toTypedInitObject(u *unstructured.Unstructured) (runtime.Object, error) {
+	newObj, ok := initResources[u.GroupVersionKind()]
+	if !ok {
+		return nil, nil
+	}
+	obj := newObj()
+	if obj == nil {
+		return nil, nil
+	}
+
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), obj); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
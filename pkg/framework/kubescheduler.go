@@ -26,8 +26,12 @@ import (
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	resourcev1alpha1 "k8s.io/api/resource/v1alpha1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -42,6 +46,7 @@ import (
 	"k8s.io/client-go/informers"
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	clientset "k8s.io/client-go/kubernetes"
+	fakeclientset "k8s.io/client-go/kubernetes/fake"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/cache"
@@ -61,6 +66,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
 	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/admission"
 	"github.com/k-cloud-labs/kluster-capacity/pkg/plugins/generic"
 	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
 )
@@ -83,12 +89,21 @@ var (
 		corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"): func() runtime.Object { return &corev1.PersistentVolumeClaim{} },
 		corev1.SchemeGroupVersion.WithKind("Service"):               func() runtime.Object { return &corev1.Service{} },
 		corev1.SchemeGroupVersion.WithKind("ReplicationController"): func() runtime.Object { return &corev1.ReplicationController{} },
+		corev1.SchemeGroupVersion.WithKind("ResourceQuota"):         func() runtime.Object { return &corev1.ResourceQuota{} },
+		corev1.SchemeGroupVersion.WithKind("LimitRange"):            func() runtime.Object { return &corev1.LimitRange{} },
 		appsv1.SchemeGroupVersion.WithKind("StatefulSet"):           func() runtime.Object { return &appsv1.StatefulSet{} },
 		appsv1.SchemeGroupVersion.WithKind("ReplicaSet"):            func() runtime.Object { return &appsv1.ReplicaSet{} },
+		appsv1.SchemeGroupVersion.WithKind("Deployment"):            func() runtime.Object { return &appsv1.Deployment{} },
+		autoscalingv2.SchemeGroupVersion.WithKind("HorizontalPodAutoscaler"): func() runtime.Object {
+			return &autoscalingv2.HorizontalPodAutoscaler{}
+		},
+		policyv1.SchemeGroupVersion.WithKind("PodDisruptionBudget"): func() runtime.Object { return &policyv1.PodDisruptionBudget{} },
+		schedulingv1.SchemeGroupVersion.WithKind("PriorityClass"):   func() runtime.Object { return &schedulingv1.PriorityClass{} },
 		storagev1.SchemeGroupVersion.WithKind("StorageClass"):       func() runtime.Object { return &storagev1.StorageClass{} },
 		storagev1.SchemeGroupVersion.WithKind("CSINode"):            func() runtime.Object { return &storagev1.CSINode{} },
 		storagev1.SchemeGroupVersion.WithKind("CSIDriver"):          func() runtime.Object { return &storagev1.CSIDriver{} },
 		storagev1.SchemeGroupVersion.WithKind("CSIStorageCapacity"): func() runtime.Object { return &storagev1.CSIStorageCapacity{} },
+		nodev1.SchemeGroupVersion.WithKind("RuntimeClass"):          func() runtime.Object { return &nodev1.RuntimeClass{} },
 		resourcev1alpha1.SchemeGroupVersion.WithKind("PodScheduling"): func() runtime.Object {
 			if utilfeature.DefaultFeatureGate.Enabled(features.DynamicResourceAllocation) {
 				return &resourcev1alpha1.PodScheduling{}
@@ -118,6 +133,10 @@ type kubeschedulerFramework struct {
 	restMapper         meta.RESTMapper
 	// real dynamic client to init the world
 	dynamicClient *dynamic.DynamicClient
+	// tracker of the fake clientset
+	tracker testing.ObjectTracker
+	// shared snapshot to init the world, nil means the world is initialized by InitTheWorld
+	world *World
 
 	// scheduler
 	scheduler                *scheduler.Scheduler
@@ -127,12 +146,27 @@ type kubeschedulerFramework struct {
 	ignorePodsOnExcludesNode bool
 	// deletionTimestamp is not nil and phase is not succeed or failed
 	withTerminatingPods bool
+	// keep DefaultPreemption enabled so that pods with higher priority can evict lower priority pods
+	withPreemption      bool
 	outOfTreeRegistry   frameworkruntime.Registry
+	customQueueSort     kubeschedulerconfig.PluginSet
 	customBind          kubeschedulerconfig.PluginSet
 	customPreBind       kubeschedulerconfig.PluginSet
 	customPostBind      kubeschedulerconfig.PluginSet
 	customEventHandlers []func()
 	postBindHook        func(*corev1.Pod) error
+	// nil means the scheduling attempts are not traced
+	tracer          *schedulingTracer
+	schedulePodHook SchedulePodHook
+	// called after a pod fails to be scheduled
+	schedulingFailureHook SchedulingFailureHook
+	// called when the scheduler has nothing to do
+	quiescedHook       func() error
+	quiescenceDetector *quiescenceDetector
+	// 0 means the simulation never times out
+	timeout time.Duration
+	// nil means the pods are created as they are
+	admission *admission.Chain
 
 	// for scheduler and informer
 	informerCh  chan struct{}
@@ -151,6 +185,15 @@ type kubeschedulerFramework struct {
 
 type Option func(*kubeschedulerFramework)
 
+// SchedulePodHook is called after the nodes are filtered and scored for a pod, the snapshot of the framework
+// is the one used to schedule the pod until the next pod is scheduled.
+type SchedulePodHook func(ctx context.Context, fwk framework.Framework, pod *corev1.Pod, result scheduler.ScheduleResult, err error)
+
+// SchedulingFailureHook is called after a pod fails to be scheduled. nominatingInfo is the result of the preemption
+// tried in this attempt, the pod has nominated a node by evicting other pods if it overrides the nominated node with
+// a non-empty one.
+type SchedulingFailureHook func(pod *corev1.Pod, reason string, err error, nominatingInfo *framework.NominatingInfo)
+
 func WithExcludeNodes(excludeNodes []string) Option {
 	return func(s *kubeschedulerFramework) {
 		s.excludeNodes = sets.New[string](excludeNodes...)
@@ -163,6 +206,12 @@ func WithOutOfTreeRegistry(registry frameworkruntime.Registry) Option {
 	}
 }
 
+func WithCustomQueueSort(plugins kubeschedulerconfig.PluginSet) Option {
+	return func(s *kubeschedulerFramework) {
+		s.customQueueSort = plugins
+	}
+}
+
 func WithCustomBind(plugins kubeschedulerconfig.PluginSet) Option {
 	return func(s *kubeschedulerFramework) {
 		s.customBind = plugins
@@ -223,36 +272,93 @@ func WithTerminatingPods(with bool) Option {
 	}
 }
 
+func WithWorld(world *World) Option {
+	return func(s *kubeschedulerFramework) {
+		s.world = world
+	}
+}
+
+// WithTracer traces the scheduling attempts of the pods, simulation is used to tell the traces apart
+// when the tracer is shared by several frameworks.
+func WithTracer(tracer *Tracer, simulation string) Option {
+	return func(s *kubeschedulerFramework) {
+		if tracer != nil {
+			s.tracer = newSchedulingTracer(tracer, simulation)
+		}
+	}
+}
+
+func WithSchedulePodHook(hook SchedulePodHook) Option {
+	return func(s *kubeschedulerFramework) {
+		s.schedulePodHook = hook
+	}
+}
+
+func WithSchedulingFailureHook(hook SchedulingFailureHook) Option {
+	return func(s *kubeschedulerFramework) {
+		s.schedulingFailureHook = hook
+	}
+}
+
+// WithQuiescedHook calls the hook each time the scheduler is found to have nothing to do, which means no pods are
+// in the active or backoff queue and no pods are being scheduled or bound.
+func WithQuiescedHook(hook func() error) Option {
+	return func(s *kubeschedulerFramework) {
+		s.quiescedHook = hook
+	}
+}
+
+// WithTimeout stops the simulation when it doesn't finish in time, the status is kept for a partial report.
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *kubeschedulerFramework) {
+		s.timeout = timeout
+	}
+}
+
+func WithPreemption(with bool) Option {
+	return func(s *kubeschedulerFramework) {
+		s.withPreemption = with
+	}
+}
+
+// WithAdmission admits the pods before they are created and the pods replayed with the chain
+func WithAdmission(chain *admission.Chain) Option {
+	return func(s *kubeschedulerFramework) {
+		s.admission = chain
+	}
+}
+
 // NewKubeSchedulerFramework create a generic simulator for ce, cc, ss simulator which is completely independent of apiserver so no need
 // for kubeconfig nor for apiserver url
 func NewKubeSchedulerFramework(kubeSchedulerConfig *schedconfig.CompletedConfig, restConfig *restclient.Config, options ...Option) (pkg.Framework, error) {
 	kubeSchedulerConfig.InformerFactory.InformerFor(&corev1.Pod{}, newPodInformer)
 
-	dynamicClient := dynamic.NewForConfigOrDie(restConfig)
-	restMapper, err := apiutil.NewDynamicRESTMapper(restConfig)
-	if err != nil {
-		return nil, err
-	}
+	s := newKubeschedulerFramework(options...)
+	s.fakeClient = kubeSchedulerConfig.Client
+	s.fakeInformerFactory = kubeSchedulerConfig.InformerFactory
 
-	s := &kubeschedulerFramework{
-		fakeClient:               kubeSchedulerConfig.Client,
-		dynamicClient:            dynamicClient,
-		restMapper:               restMapper,
-		stopCh:                   make(chan struct{}),
-		fakeInformerFactory:      kubeSchedulerConfig.InformerFactory,
-		informerCh:               make(chan struct{}),
-		schedulerCh:              make(chan struct{}),
-		withScheduledPods:        true,
-		ignorePodsOnExcludesNode: false,
-		withNodeImages:           true,
-		withTerminatingPods:      true,
+	// objects of the shared world are read through a copy-on-write tracker
+	if s.world != nil {
+		tracker := newCOWTracker(s.world)
+		tracker.install(&s.fakeClient.(*fakeclientset.Clientset).Fake)
+		s.tracker = tracker
+	} else {
+		s.tracker = s.fakeClient.(testing.FakeClient).Tracker()
 	}
-	for _, option := range options {
-		option(s)
+	if s.admission != nil {
+		s.fakeClient.(*fakeclientset.Clientset).PrependReactor("create", "pods", s.admitPod)
 	}
 
-	// only for latest k8s version
+	// nil rest config means the world can only be initialized from objs passed to InitTheWorld
 	if restConfig != nil {
+		restMapper, err := apiutil.NewDynamicRESTMapper(restConfig)
+		if err != nil {
+			return nil, err
+		}
+		s.restMapper = restMapper
+		s.dynamicClient = dynamic.NewForConfigOrDie(restConfig)
+
+		// only for latest k8s version
 		dynClient := dynamic.NewForConfigOrDie(restConfig)
 		s.dynInformerFactory = dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynClient, 0, corev1.NamespaceAll, nil)
 	}
@@ -263,6 +369,20 @@ func NewKubeSchedulerFramework(kubeSchedulerConfig *schedconfig.CompletedConfig,
 	}
 
 	s.scheduler = scheduler
+	if s.tracer != nil {
+		s.tracer.wrap(scheduler)
+	}
+	if s.schedulePodHook != nil {
+		s.hookSchedulePod()
+	}
+	if s.schedulingFailureHook != nil {
+		s.hookSchedulingFailure()
+	}
+	if s.quiescedHook != nil {
+		s.quiescenceDetector = newQuiescenceDetector(s,
+			time.Duration(kubeSchedulerConfig.ComponentConfig.PodInitialBackoffSeconds)*time.Second,
+			time.Duration(kubeSchedulerConfig.ComponentConfig.PodMaxBackoffSeconds)*time.Second)
+	}
 
 	s.fakeInformerFactory.Start(s.informerCh)
 	if s.dynInformerFactory != nil {
@@ -272,6 +392,23 @@ func NewKubeSchedulerFramework(kubeSchedulerConfig *schedconfig.CompletedConfig,
 	return s, nil
 }
 
+func newKubeschedulerFramework(options ...Option) *kubeschedulerFramework {
+	s := &kubeschedulerFramework{
+		stopCh:                   make(chan struct{}),
+		informerCh:               make(chan struct{}),
+		schedulerCh:              make(chan struct{}),
+		withScheduledPods:        true,
+		ignorePodsOnExcludesNode: false,
+		withNodeImages:           true,
+		withTerminatingPods:      true,
+	}
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
+
 func (s *kubeschedulerFramework) GetPodsByNode(nodeName string) ([]*corev1.Pod, error) {
 	dump := s.scheduler.Cache.Dump()
 	var res []*corev1.Pod
@@ -290,32 +427,64 @@ func (s *kubeschedulerFramework) GetPodsByNode(nodeName string) ([]*corev1.Pod,
 	return res, nil
 }
 
+func (s *kubeschedulerFramework) Objects() ([]runtime.Object, error) {
+	var objs []runtime.Object
+	for gvk, newObj := range initResources {
+		// the resources disabled by the feature gates
+		if newObj() == nil {
+			continue
+		}
+
+		gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+		list, err := s.tracker.List(gvr, gvk, metav1.NamespaceAll)
+		if err != nil {
+			return nil, err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			objs = append(objs, item.DeepCopyObject())
+		}
+	}
+
+	return objs, nil
+}
+
 // InitTheWorld use objs outside or default init resources to initialize the scheduler
 // the objs outside must be typed object.
 func (s *kubeschedulerFramework) InitTheWorld(objs ...runtime.Object) error {
+	if s.world != nil {
+		klog.V(2).InfoS("Init the world form shared snapshot")
+		return nil
+	}
+
 	if len(objs) == 0 {
+		if s.dynamicClient == nil {
+			return errors.New("no objects to init the world and no kubeconfig to copy it from a running cluster")
+		}
 		// black magic
 		klog.V(2).InfoS("Init the world form running cluster")
-		initObjects := getInitObjects(s.restMapper, s.dynamicClient)
-		for _, unstructuredObj := range initObjects {
-			obj := initResources[unstructuredObj.GetObjectKind().GroupVersionKind()]()
-			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredObj.(*unstructured.Unstructured).UnstructuredContent(), obj); err != nil {
-				return err
-			}
-			if needAdd, obj := s.preAdd(obj); needAdd {
-				if err := s.fakeClient.(testing.FakeClient).Tracker().Add(obj); err != nil {
+		initObjects, err := getTypedInitObjects(s.restMapper, s.dynamicClient)
+		if err != nil {
+			return err
+		}
+		for _, obj := range podsLast(initObjects) {
+			if needAdd, obj := s.preAdd(obj, s.tracker); needAdd {
+				if err := s.tracker.Add(obj); err != nil {
 					return err
 				}
 			}
 		}
 	} else {
 		klog.V(2).InfoS("Init the world form snapshot")
-		for _, obj := range objs {
+		for _, obj := range podsLast(objs) {
 			if _, ok := obj.(runtime.Unstructured); ok {
 				return errors.New("type of objs used to init the world must not be unstructured")
 			}
-			if needAdd, obj := s.preAdd(obj); needAdd {
-				if err := s.fakeClient.(testing.FakeClient).Tracker().Add(obj); err != nil {
+			if needAdd, obj := s.preAdd(obj, s.tracker); needAdd {
+				if err := s.tracker.Add(obj); err != nil {
 					return err
 				}
 			}
@@ -325,15 +494,33 @@ func (s *kubeschedulerFramework) InitTheWorld(objs ...runtime.Object) error {
 	return nil
 }
 
+// the status is updated by the hooks and the event handlers of the simulators, which run in the goroutines of the
+// scheduler and the informers, so it's guarded by stopMux as Stop does
 func (s *kubeschedulerFramework) UpdateEstimationPods(pod ...*corev1.Pod) {
+	s.stopMux.Lock()
+	defer s.stopMux.Unlock()
+
 	s.status.PodsForEstimation = append(s.status.PodsForEstimation, pod...)
 }
 
+func (s *kubeschedulerFramework) UpdatePreemptedPods(pod ...*corev1.Pod) {
+	s.stopMux.Lock()
+	defer s.stopMux.Unlock()
+
+	s.status.PreemptedPods = append(s.status.PreemptedPods, pod...)
+}
+
 func (s *kubeschedulerFramework) UpdateNodesToScaleDown(nodeName string) {
+	s.stopMux.Lock()
+	defer s.stopMux.Unlock()
+
 	s.status.NodesToScaleDown = append(s.status.NodesToScaleDown, nodeName)
 }
 
 func (s *kubeschedulerFramework) Status() pkg.Status {
+	s.stopMux.Lock()
+	defer s.stopMux.Unlock()
+
 	return s.status
 }
 
@@ -359,6 +546,10 @@ func (s *kubeschedulerFramework) Stop(reason string) error {
 
 	s.status.StopReason = reason
 
+	if s.tracer != nil {
+		s.tracer.stop()
+	}
+
 	if len(s.saveTo) > 0 {
 		file, err := os.OpenFile(s.saveTo, os.O_CREATE|os.O_RDWR, 0755)
 		if err != nil {
@@ -393,15 +584,63 @@ func (s *kubeschedulerFramework) CreatePod(pod *corev1.Pod) error {
 	return err
 }
 
+func (s *kubeschedulerFramework) Admit(pod *corev1.Pod) (*corev1.Pod, error) {
+	pod = pod.DeepCopy()
+	if s.admission == nil {
+		return pod, nil
+	}
+
+	if err := s.admission.Admit(pod, s.tracker); err != nil {
+		return nil, err
+	}
+	return pod, nil
+}
+
+// admitPod admits the pods created through the fake client, the objects the admission depends on are read from
+// the tracker directly since the fake client is locked while reacting
+func (s *kubeschedulerFramework) admitPod(action testing.Action) (bool, runtime.Object, error) {
+	createAction := action.(testing.CreateAction)
+	pod, ok := createAction.GetObject().(*corev1.Pod)
+	if !ok {
+		return false, nil, nil
+	}
+
+	admitted, err := s.Admit(pod)
+	if err != nil {
+		return true, nil, apierrors.NewForbidden(createAction.GetResource().GroupResource(), pod.Name, err)
+	}
+
+	return testing.ObjectReaction(s.tracker)(testing.NewCreateAction(createAction.GetResource(), createAction.GetNamespace(), admitted))
+}
+
 func (s *kubeschedulerFramework) Run() error {
 	// wait for all informer cache synced
 	s.fakeInformerFactory.WaitForCacheSync(s.informerCh)
 	if s.dynInformerFactory != nil {
 		s.dynInformerFactory.WaitForCacheSync(s.informerCh)
 	}
-	go s.scheduler.Run(context.TODO())
+	// the scheduler must be stopped explicitly, otherwise it keeps running and holds the whole world
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	go s.scheduler.Run(ctx)
 
-	<-s.stopCh
+	if s.quiescenceDetector != nil {
+		go s.quiescenceDetector.run(ctx, s.quiescedHook)
+	}
+
+	var timeout <-chan time.Time
+	if s.timeout > 0 {
+		timer := time.NewTimer(s.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-s.stopCh:
+	case <-timeout:
+		klog.V(2).InfoS("Simulation timed out", "timeout", s.timeout)
+		return s.Stop(fmt.Sprintf("Timeout: the simulation didn't finish in %v", s.timeout))
+	}
 
 	return nil
 }
@@ -417,7 +656,7 @@ func (s *kubeschedulerFramework) createScheduler(cc *schedconfig.CompletedConfig
 		s.outOfTreeRegistry = make(frameworkruntime.Registry)
 	}
 	err := s.outOfTreeRegistry.Register(generic.Name, func(configuration runtime.Object, f framework.Handle) (framework.Plugin, error) {
-		return generic.New(s.postBindHook, s.fakeClient)
+		return generic.New(s.postBind, s.fakeClient)
 	})
 	if err != nil {
 		return nil, err
@@ -428,7 +667,13 @@ func (s *kubeschedulerFramework) createScheduler(cc *schedconfig.CompletedConfig
 	cc.ComponentConfig.Profiles[0].Plugins.Bind.Enabled = append(cc.ComponentConfig.Profiles[0].Plugins.Bind.Enabled, kubeschedulerconfig.Plugin{Name: generic.Name})
 	cc.ComponentConfig.Profiles[0].Plugins.Bind.Disabled = append(cc.ComponentConfig.Profiles[0].Plugins.Bind.Disabled, kubeschedulerconfig.Plugin{Name: defaultbinder.Name})
 	cc.ComponentConfig.Profiles[0].Plugins.PostBind.Enabled = append(cc.ComponentConfig.Profiles[0].Plugins.PostBind.Enabled, kubeschedulerconfig.Plugin{Name: generic.Name})
-	cc.ComponentConfig.Profiles[0].Plugins.PostFilter.Disabled = append(cc.ComponentConfig.Profiles[0].Plugins.PostFilter.Disabled, kubeschedulerconfig.Plugin{Name: defaultpreemption.Name})
+	if !s.withPreemption {
+		cc.ComponentConfig.Profiles[0].Plugins.PostFilter.Disabled = append(cc.ComponentConfig.Profiles[0].Plugins.PostFilter.Disabled, kubeschedulerconfig.Plugin{Name: defaultpreemption.Name})
+	}
+
+	// custom queue sort plugin
+	cc.ComponentConfig.Profiles[0].Plugins.QueueSort.Enabled = append(cc.ComponentConfig.Profiles[0].Plugins.QueueSort.Enabled, s.customQueueSort.Enabled...)
+	cc.ComponentConfig.Profiles[0].Plugins.QueueSort.Disabled = append(cc.ComponentConfig.Profiles[0].Plugins.QueueSort.Disabled, s.customQueueSort.Disabled...)
 
 	// custom bind plugin
 	cc.ComponentConfig.Profiles[0].Plugins.PreBind.Enabled = append(cc.ComponentConfig.Profiles[0].Plugins.PreBind.Enabled, s.customPreBind.Enabled...)
@@ -457,7 +702,38 @@ func (s *kubeschedulerFramework) createScheduler(cc *schedconfig.CompletedConfig
 	)
 }
 
-func (s *kubeschedulerFramework) preAdd(obj runtime.Object) (bool, runtime.Object) {
+// postBind is called at the end of the binding cycle of the pods bound
+func (s *kubeschedulerFramework) postBind(pod *corev1.Pod) error {
+	if s.quiescenceDetector != nil {
+		defer s.quiescenceDetector.bound()
+	}
+	if s.postBindHook == nil {
+		return nil
+	}
+
+	return s.postBindHook(pod)
+}
+
+func (s *kubeschedulerFramework) hookSchedulePod() {
+	schedulePod := s.scheduler.SchedulePod
+	s.scheduler.SchedulePod = func(ctx context.Context, fwk framework.Framework, state *framework.CycleState, pod *corev1.Pod) (scheduler.ScheduleResult, error) {
+		result, err := schedulePod(ctx, fwk, state, pod)
+		s.schedulePodHook(ctx, fwk, pod, result, err)
+		return result, err
+	}
+}
+
+func (s *kubeschedulerFramework) hookSchedulingFailure() {
+	failureHandler := s.scheduler.FailureHandler
+	s.scheduler.FailureHandler = func(ctx context.Context, fwk framework.Framework, podInfo *framework.QueuedPodInfo, err error, reason string, nominatingInfo *framework.NominatingInfo, start time.Time) {
+		failureHandler(ctx, fwk, podInfo, err, reason, nominatingInfo, start)
+		s.schedulingFailureHook(podInfo.Pod, reason, err, nominatingInfo)
+	}
+}
+
+// preAdd must be called for the pods after the other objects are added, so that the admission of the replayed pods
+// could get the objects it depends on from the lister
+func (s *kubeschedulerFramework) preAdd(obj runtime.Object, lister admission.Lister) (bool, runtime.Object) {
 	// filter exclude nodes and pods and update pod, node spec and status property
 	if pod, ok := obj.(*corev1.Pod); ok {
 		// ignore ds pods on exclude nodes
@@ -481,12 +757,23 @@ func (s *kubeschedulerFramework) preAdd(obj runtime.Object) (bool, runtime.Objec
 			pod := utils.InitPod(pod)
 			pod.Status.Phase = corev1.PodPending
 
+			// the pod was admitted by the cluster when it was created, so it's replayed as it is if it's rejected
+			if s.admission != nil {
+				admitted := pod.DeepCopy()
+				if err := s.admission.Admit(admitted, lister); err != nil {
+					klog.ErrorS(err, "Failed to admit the replayed pod", "pod", klog.KObj(pod))
+				} else {
+					pod = admitted
+				}
+			}
+
 			return true, pod
 		}
 	} else if node, ok := obj.(*corev1.Node); ok && s.excludeNodes != nil {
 		if _, ok := s.excludeNodes[node.Name]; ok {
 			return false, nil
 		} else if !s.withNodeImages {
+			node = node.DeepCopy()
 			node.Status.Images = nil
 
 			return true, node
@@ -496,6 +783,21 @@ func (s *kubeschedulerFramework) preAdd(obj runtime.Object) (bool, runtime.Objec
 	return true, obj
 }
 
+// podsLast returns the objects with the pods moved to the end
+func podsLast(objs []runtime.Object) []runtime.Object {
+	result := make([]runtime.Object, 0, len(objs))
+	var pods []runtime.Object
+	for _, obj := range objs {
+		if _, ok := obj.(*corev1.Pod); ok {
+			pods = append(pods, obj)
+			continue
+		}
+		result = append(result, obj)
+	}
+
+	return append(result, pods...)
+}
+
 func newPodInformer(cs clientset.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
 	selector := fmt.Sprintf("status.phase!=%v,status.phase!=%v", corev1.PodSucceeded, corev1.PodFailed)
 	tweakListOptions := func(options *metav1.ListOptions) {
@@ -510,6 +812,20 @@ func getRecorderFactory(cc *schedconfig.CompletedConfig) profile.RecorderFactory
 	}
 }
 
+// getTypedInitObjects converts the objects returned by getInitObjects to typed objects
+func getTypedInitObjects(restMapper meta.RESTMapper, dynClient dynamic.Interface) ([]runtime.Object, error) {
+	var objs []runtime.Object
+	for _, unstructuredObj := range getInitObjects(restMapper, dynClient) {
+		obj := initResources[unstructuredObj.GetObjectKind().GroupVersionKind()]()
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredObj.(*unstructured.Unstructured).UnstructuredContent(), obj); err != nil {
+			return nil, err
+		}
+		objs = append(objs, obj)
+	}
+
+	return objs, nil
+}
+
 // getInitObjects return all objects need to add to scheduler.
 // it's pkg scope for multi scheduler to avoid calling too much times of real kube-apiserver
 func getInitObjects(restMapper meta.RESTMapper, dynClient dynamic.Interface) []runtime.Object {
@@ -0,0 +1,170 @@
+package framework
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+const (
+	// quiescenceCheckInterval is the interval to check whether the scheduler has nothing to do
+	quiescenceCheckInterval = time.Second
+	// backoffFlushInterval is the interval the scheduling queue moves the pods done with backoff to the active queue
+	backoffFlushInterval = time.Second
+)
+
+// quiescenceDetector finds out when the scheduler has nothing to do. The scheduler is quiescent when no pod is
+// in its scheduling or binding cycle and all the pods left in the scheduling queue are parked: their last attempt
+// failed and their backoff is over, so they are the unschedulable ones waiting for cluster events.
+type quiescenceDetector struct {
+	s              *kubeschedulerFramework
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+
+	lock sync.Mutex
+	// number of pods popped from the scheduling queue
+	popped int64
+	// number of popped pods whose scheduling or binding cycle is not over
+	inFlight int
+	// whether the pod popped last has entered SchedulePod, the scheduler skips the pods deleted or assumed
+	// already without calling any hook
+	lastPopped, lastScheduled bool
+	// pods whose last attempt failed, the value is the time their backoff is over
+	parked map[types.UID]time.Time
+}
+
+func newQuiescenceDetector(s *kubeschedulerFramework, initialBackoff, maxBackoff time.Duration) *quiescenceDetector {
+	d := &quiescenceDetector{
+		s:              s,
+		initialBackoff: initialBackoff,
+		maxBackoff:     maxBackoff,
+		parked:         make(map[types.UID]time.Time),
+	}
+
+	// the scheduling cycles are serialized, the previous one is over when the next pod is requested
+	nextPod := s.scheduler.NextPod
+	s.scheduler.NextPod = func() *framework.QueuedPodInfo {
+		d.lock.Lock()
+		if d.lastPopped && !d.lastScheduled {
+			d.inFlight--
+		}
+		d.lastPopped = false
+		d.lock.Unlock()
+
+		podInfo := nextPod()
+		if podInfo == nil || podInfo.Pod == nil {
+			return podInfo
+		}
+
+		d.lock.Lock()
+		defer d.lock.Unlock()
+		d.popped++
+		d.inFlight++
+		d.lastPopped, d.lastScheduled = true, false
+		delete(d.parked, podInfo.Pod.UID)
+		return podInfo
+	}
+
+	schedulePod := s.scheduler.SchedulePod
+	s.scheduler.SchedulePod = func(ctx context.Context, fwk framework.Framework, state *framework.CycleState, pod *corev1.Pod) (scheduler.ScheduleResult, error) {
+		d.lock.Lock()
+		d.lastScheduled = true
+		d.lock.Unlock()
+
+		return schedulePod(ctx, fwk, state, pod)
+	}
+
+	// both the scheduling and the binding cycle end with the failure handler if they fail
+	failureHandler := s.scheduler.FailureHandler
+	s.scheduler.FailureHandler = func(ctx context.Context, fwk framework.Framework, podInfo *framework.QueuedPodInfo, err error, reason string, nominatingInfo *framework.NominatingInfo, start time.Time) {
+		// the attempts are only changed when the pod is popped
+		backoff := d.backoff(podInfo.Attempts)
+		failureHandler(ctx, fwk, podInfo, err, reason, nominatingInfo, start)
+
+		d.lock.Lock()
+		defer d.lock.Unlock()
+		d.inFlight--
+		d.parked[podInfo.Pod.UID] = time.Now().Add(backoff + backoffFlushInterval)
+	}
+
+	return d
+}
+
+// bound is called at the end of the binding cycle of the pods bound
+func (d *quiescenceDetector) bound() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.inFlight--
+}
+
+// backoff is the same as the backoff of the scheduling queue, the pods put into the backoff queue are moved to
+// the active queue once it is over
+func (d *quiescenceDetector) backoff(attempts int) time.Duration {
+	duration := d.initialBackoff
+	for i := 1; i < attempts; i++ {
+		if duration > d.maxBackoff-duration {
+			return d.maxBackoff
+		}
+		duration += duration
+	}
+	return duration
+}
+
+// run calls the hook each time the scheduler is found quiescent until the context is done
+func (d *quiescenceDetector) run(ctx context.Context, hook func() error) {
+	lastPopped := int64(-1)
+	wait.Until(func() {
+		// the pods created or moved to the active queue since the last check may not have been popped yet
+		popped, quiescent := d.quiescent()
+		if popped != lastPopped {
+			lastPopped = popped
+			return
+		}
+		if !quiescent {
+			return
+		}
+
+		klog.V(2).InfoS("Scheduler is quiescent", "popped", popped)
+		if err := hook(); err != nil {
+			klog.ErrorS(err, "Failed to handle quiescent scheduler")
+		}
+	}, quiescenceCheckInterval, ctx.Done())
+}
+
+// quiescent returns the number of pods popped so far and whether the scheduler is quiescent
+func (d *quiescenceDetector) quiescent() (int64, bool) {
+	pods, _ := d.s.scheduler.SchedulingQueue.PendingPods()
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.inFlight > 0 {
+		return d.popped, false
+	}
+
+	now := time.Now()
+	pending := make(map[types.UID]struct{}, len(pods))
+	for _, pod := range pods {
+		until, ok := d.parked[pod.UID]
+		if !ok || now.Before(until) {
+			return d.popped, false
+		}
+		pending[pod.UID] = struct{}{}
+	}
+
+	// forget the pods deleted from the queue
+	for uid := range d.parked {
+		if _, ok := pending[uid]; !ok {
+			delete(d.parked, uid)
+		}
+	}
+
+	return d.popped, true
+}
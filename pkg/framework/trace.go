@@ -0,0 +1,330 @@
+package framework
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/scheduler"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// SchedulingTrace records one scheduling attempt of a pod, it is written to the trace file as one line of json.
+type SchedulingTrace struct {
+	Timestamp time.Time `json:"timestamp"`
+	// name of the simulation the attempt belongs to, only set when several simulations share the trace file
+	Simulation string `json:"simulation,omitempty"`
+	Pod        string `json:"pod"`
+	// starts from 1 and increases each time the pod is scheduled again
+	Attempt int `json:"attempt"`
+	// nodes evaluated by the filter plugins
+	CandidateNodes []string       `json:"candidateNodes"`
+	RejectedNodes  []RejectedNode `json:"rejectedNodes,omitempty"`
+	// scores of the feasible nodes, empty when there is only one feasible node
+	Scores     []NodeScore `json:"scores,omitempty"`
+	ChosenNode string      `json:"chosenNode,omitempty"`
+	// node nominated by the post filter plugins of an unschedulable pod, e.g. by preemption
+	NominatedNode string `json:"nominatedNode,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+type RejectedNode struct {
+	Node    string   `json:"node"`
+	Plugin  string   `json:"plugin"`
+	Reasons []string `json:"reasons"`
+}
+
+type NodeScore struct {
+	Node string `json:"node"`
+	// normalized and weighted score of each score plugin
+	Plugins map[string]int64 `json:"plugins"`
+	Total   int64            `json:"total"`
+}
+
+// Tracer writes the scheduling traces as NDJSON, it can be shared by several frameworks.
+type Tracer struct {
+	lock    sync.Mutex
+	file    *os.File
+	writer  *bufio.Writer
+	encoder *json.Encoder
+	closed  bool
+}
+
+// NewTracer creates the trace file, an empty path means tracing is disabled and nil is returned.
+func NewTracer(path string) (*Tracer, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	writer := bufio.NewWriter(file)
+	return &Tracer{
+		file:    file,
+		writer:  writer,
+		encoder: json.NewEncoder(writer),
+	}, nil
+}
+
+func (t *Tracer) write(trace *SchedulingTrace) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.closed {
+		return errors.New("tracer is closed")
+	}
+
+	return t.encoder.Encode(trace)
+}
+
+// Close flushes the traces and closes the trace file, it must be called after all the frameworks using the tracer are stopped.
+func (t *Tracer) Close() error {
+	if t == nil {
+		return nil
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+
+	if err := t.writer.Flush(); err != nil {
+		_ = t.file.Close()
+		return err
+	}
+
+	return t.file.Close()
+}
+
+// attempt is the scheduling attempt in progress
+type attempt struct {
+	trace      *SchedulingTrace
+	candidates map[string]bool
+	rejected   map[string]RejectedNode
+}
+
+// schedulingTracer collects the results of the plugins of one scheduler. Scheduling cycles run one by one,
+// but the filter plugins run in parallel, so the attempt in progress is protected by the lock.
+type schedulingTracer struct {
+	tracer     *Tracer
+	simulation string
+
+	lock     sync.Mutex
+	attempts map[types.UID]int
+	current  *attempt
+	// unschedulable attempt waiting for the result of the post filter plugins
+	pending *attempt
+}
+
+func newSchedulingTracer(tracer *Tracer, simulation string) *schedulingTracer {
+	return &schedulingTracer{
+		tracer:     tracer,
+		simulation: simulation,
+		attempts:   make(map[types.UID]int),
+	}
+}
+
+// wrap makes the scheduler report the results of its plugins to the tracer
+func (t *schedulingTracer) wrap(sched *scheduler.Scheduler) {
+	for name, fwk := range sched.Profiles {
+		sched.Profiles[name] = &tracingFramework{Framework: fwk, tracer: t}
+	}
+
+	schedulePod := sched.SchedulePod
+	sched.SchedulePod = func(ctx context.Context, fwk framework.Framework, state *framework.CycleState, pod *corev1.Pod) (scheduler.ScheduleResult, error) {
+		t.begin(pod)
+		result, err := schedulePod(ctx, fwk, state, pod)
+		t.end(result, err)
+		return result, err
+	}
+}
+
+func (t *schedulingTracer) begin(pod *corev1.Pod) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.flushPending()
+	t.attempts[pod.UID]++
+	t.current = &attempt{
+		trace: &SchedulingTrace{
+			Timestamp:      time.Now(),
+			Simulation:     t.simulation,
+			Pod:            pod.Namespace + "/" + pod.Name,
+			Attempt:        t.attempts[pod.UID],
+			CandidateNodes: make([]string, 0),
+		},
+		candidates: make(map[string]bool),
+		rejected:   make(map[string]RejectedNode),
+	}
+}
+
+func (t *schedulingTracer) filtered(nodeName string, status *framework.Status) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.current == nil {
+		return
+	}
+
+	t.current.candidates[nodeName] = true
+	if status.IsSuccess() {
+		delete(t.current.rejected, nodeName)
+		return
+	}
+	t.current.rejected[nodeName] = RejectedNode{
+		Node:    nodeName,
+		Plugin:  status.FailedPlugin(),
+		Reasons: status.Reasons(),
+	}
+}
+
+func (t *schedulingTracer) scored(scores []framework.NodePluginScores) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.current == nil {
+		return
+	}
+
+	for _, nodeScores := range scores {
+		score := NodeScore{
+			Node:    nodeScores.Name,
+			Plugins: make(map[string]int64, len(nodeScores.Scores)),
+			Total:   nodeScores.TotalScore,
+		}
+		for _, pluginScore := range nodeScores.Scores {
+			score.Plugins[pluginScore.Name] = pluginScore.Score
+		}
+		t.current.trace.Scores = append(t.current.trace.Scores, score)
+	}
+}
+
+func (t *schedulingTracer) end(result scheduler.ScheduleResult, err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	a := t.current
+	t.current = nil
+	if a == nil {
+		return
+	}
+
+	if err == nil {
+		a.trace.ChosenNode = result.SuggestedHost
+		t.write(a)
+		return
+	}
+
+	a.trace.Error = err.Error()
+	fitErr := &framework.FitError{}
+	if !errors.As(err, &fitErr) {
+		t.write(a)
+		return
+	}
+
+	// nodes rejected by the pre filter plugins are never evaluated by the filter plugins
+	for nodeName, status := range fitErr.Diagnosis.NodeToStatusMap {
+		if _, ok := a.rejected[nodeName]; !ok {
+			a.rejected[nodeName] = RejectedNode{
+				Node:    nodeName,
+				Plugin:  status.FailedPlugin(),
+				Reasons: status.Reasons(),
+			}
+		}
+	}
+	t.pending = a
+}
+
+func (t *schedulingTracer) postFiltered(result *framework.PostFilterResult) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.pending == nil {
+		return
+	}
+
+	if result != nil && result.NominatingInfo != nil {
+		t.pending.trace.NominatedNode = result.NominatedNodeName
+	}
+	t.flushPending()
+}
+
+// stop writes the attempt left, it is called when the framework stops
+func (t *schedulingTracer) stop() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.flushPending()
+}
+
+// flushPending must be called with lock held
+func (t *schedulingTracer) flushPending() {
+	if t.pending != nil {
+		t.write(t.pending)
+		t.pending = nil
+	}
+}
+
+// write must be called with lock held
+func (t *schedulingTracer) write(a *attempt) {
+	for nodeName := range a.candidates {
+		a.trace.CandidateNodes = append(a.trace.CandidateNodes, nodeName)
+	}
+	sort.Strings(a.trace.CandidateNodes)
+	for _, rejected := range a.rejected {
+		a.trace.RejectedNodes = append(a.trace.RejectedNodes, rejected)
+	}
+	sort.Slice(a.trace.RejectedNodes, func(i, j int) bool {
+		return a.trace.RejectedNodes[i].Node < a.trace.RejectedNodes[j].Node
+	})
+	sort.Slice(a.trace.Scores, func(i, j int) bool {
+		return a.trace.Scores[i].Node < a.trace.Scores[j].Node
+	})
+
+	// tracing must not break the simulation
+	_ = t.tracer.write(a.trace)
+}
+
+// tracingFramework reports the results of the filter, score and post filter plugins to the tracer
+type tracingFramework struct {
+	framework.Framework
+	tracer *schedulingTracer
+}
+
+func (f *tracingFramework) RunFilterPluginsWithNominatedPods(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, info *framework.NodeInfo) *framework.Status {
+	status := f.Framework.RunFilterPluginsWithNominatedPods(ctx, state, pod, info)
+	if info.Node() != nil {
+		f.tracer.filtered(info.Node().Name, status)
+	}
+
+	return status
+}
+
+func (f *tracingFramework) RunScorePlugins(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, nodes []*corev1.Node) ([]framework.NodePluginScores, *framework.Status) {
+	scores, status := f.Framework.RunScorePlugins(ctx, state, pod, nodes)
+	if status.IsSuccess() {
+		f.tracer.scored(scores)
+	}
+
+	return scores, status
+}
+
+func (f *tracingFramework) RunPostFilterPlugins(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, filteredNodeStatusMap framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
+	result, status := f.Framework.RunPostFilterPlugins(ctx, state, pod, filteredNodeStatusMap)
+	f.tracer.postFiltered(result)
+
+	return result, status
+}
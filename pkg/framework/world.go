@@ -0,0 +1,356 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// World is an immutable snapshot of the objects used to init the scheduler. It is shared by all the
+// simulators running in parallel, each simulator only keeps the objects it changes in its own overlay,
+// so the memory used by the snapshot doesn't grow with the number of simulators.
+type World struct {
+	objects map[schema.GroupVersionResource]map[types.NamespacedName]runtime.Object
+}
+
+// NewWorld creates a world from typed objects, the options are the same as the ones used to create the
+// framework and decide which objects are kept in the world.
+func NewWorld(objs []runtime.Object, options ...Option) (*World, error) {
+	s := newKubeschedulerFramework(options...)
+	w := &World{
+		objects: make(map[schema.GroupVersionResource]map[types.NamespacedName]runtime.Object),
+	}
+
+	// the replayed pods are admitted against the objects already in the world
+	lister := newCOWTracker(w)
+	for _, obj := range podsLast(objs) {
+		if _, ok := obj.(runtime.Unstructured); ok {
+			return nil, errors.New("type of objs used to init the world must not be unstructured")
+		}
+		if needAdd, obj := s.preAdd(obj, lister); needAdd {
+			if err := w.add(obj); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return w, nil
+}
+
+// NewWorldFromCluster creates a world from the objects of a running cluster.
+func NewWorldFromCluster(restConfig *restclient.Config, options ...Option) (*World, error) {
+	objs, err := GetInitObjectsFromCluster(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWorld(objs, options...)
+}
+
+// GetInitObjectsFromCluster returns the typed objects of a running cluster used to init the world, so that
+// several simulations can be initialized from the same objects.
+func GetInitObjectsFromCluster(restConfig *restclient.Config) ([]runtime.Object, error) {
+	dynamicClient := dynamic.NewForConfigOrDie(restConfig)
+	restMapper, err := apiutil.NewDynamicRESTMapper(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return getTypedInitObjects(restMapper, dynamicClient)
+}
+
+func (w *World) add(obj runtime.Object) error {
+	objMeta, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	gvks, _, err := scheme.Scheme.ObjectKinds(obj)
+	if err != nil {
+		return err
+	}
+
+	for _, gvk := range gvks {
+		gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+		if _, ok := w.objects[gvr]; !ok {
+			w.objects[gvr] = make(map[types.NamespacedName]runtime.Object)
+		}
+		w.objects[gvr][types.NamespacedName{Namespace: objMeta.GetNamespace(), Name: objMeta.GetName()}] = obj
+	}
+
+	return nil
+}
+
+// cowTracker is a copy-on-write testing.ObjectTracker on top of a shared world, it is mostly lifted from
+// k8s.io/client-go/testing/fixture.go. Objects of the world are never modified, changes are written to
+// the overlay and deletions are recorded as tombstones.
+type cowTracker struct {
+	world *World
+	lock  sync.RWMutex
+	// objects created or updated by the simulator
+	objects map[schema.GroupVersionResource]map[types.NamespacedName]runtime.Object
+	// objects of the world deleted by the simulator
+	tombstones map[schema.GroupVersionResource]map[types.NamespacedName]struct{}
+	watchers   map[schema.GroupVersionResource]map[string][]*watch.RaceFreeFakeWatcher
+}
+
+var _ testing.ObjectTracker = &cowTracker{}
+
+func newCOWTracker(world *World) *cowTracker {
+	return &cowTracker{
+		world:      world,
+		objects:    make(map[schema.GroupVersionResource]map[types.NamespacedName]runtime.Object),
+		tombstones: make(map[schema.GroupVersionResource]map[types.NamespacedName]struct{}),
+		watchers:   make(map[schema.GroupVersionResource]map[string][]*watch.RaceFreeFakeWatcher),
+	}
+}
+
+// get must be called with lock held, the object returned must not be modified
+func (t *cowTracker) get(gvr schema.GroupVersionResource, nn types.NamespacedName) (runtime.Object, bool) {
+	if obj, ok := t.objects[gvr][nn]; ok {
+		return obj, true
+	}
+	if _, ok := t.tombstones[gvr][nn]; ok {
+		return nil, false
+	}
+	obj, ok := t.world.objects[gvr][nn]
+	return obj, ok
+}
+
+func (t *cowTracker) Get(gvr schema.GroupVersionResource, ns, name string) (runtime.Object, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	obj, ok := t.get(gvr, types.NamespacedName{Namespace: ns, Name: name})
+	if !ok {
+		return nil, apierrors.NewNotFound(gvr.GroupResource(), name)
+	}
+
+	// callers like the patch reaction modify the object returned
+	return obj.DeepCopyObject(), nil
+}
+
+// List returns deep copies of the objects like Get, so callers can't modify the shared world.
+func (t *cowTracker) List(gvr schema.GroupVersionResource, gvk schema.GroupVersionKind, ns string) (runtime.Object, error) {
+	listGVK := gvk
+	listGVK.Kind = listGVK.Kind + "List"
+	if listGVK.Version == "" {
+		listGVK.Version = runtime.APIVersionInternal
+	}
+
+	list, err := scheme.Scheme.New(listGVK)
+	if err != nil {
+		return nil, err
+	}
+	if !meta.IsListType(list) {
+		return nil, fmt.Errorf("%q is not a list type", listGVK.Kind)
+	}
+
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	var matchingObjs []runtime.Object
+	for nn, obj := range t.world.objects[gvr] {
+		if ns != "" && nn.Namespace != ns {
+			continue
+		}
+		if _, ok := t.objects[gvr][nn]; ok {
+			continue
+		}
+		if _, ok := t.tombstones[gvr][nn]; ok {
+			continue
+		}
+		matchingObjs = append(matchingObjs, obj.DeepCopyObject())
+	}
+	for nn, obj := range t.objects[gvr] {
+		if ns != "" && nn.Namespace != ns {
+			continue
+		}
+		matchingObjs = append(matchingObjs, obj.DeepCopyObject())
+	}
+
+	// sort objects to get deterministic order
+	sort.Slice(matchingObjs, func(i, j int) bool {
+		acc1, _ := meta.Accessor(matchingObjs[i])
+		acc2, _ := meta.Accessor(matchingObjs[j])
+		if acc1.GetNamespace() != acc2.GetNamespace() {
+			return acc1.GetNamespace() < acc2.GetNamespace()
+		}
+		return acc1.GetName() < acc2.GetName()
+	})
+
+	if err := meta.SetList(list, matchingObjs); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (t *cowTracker) Watch(gvr schema.GroupVersionResource, ns string) (watch.Interface, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	fakewatcher := watch.NewRaceFreeFake()
+	if _, exists := t.watchers[gvr]; !exists {
+		t.watchers[gvr] = make(map[string][]*watch.RaceFreeFakeWatcher)
+	}
+	t.watchers[gvr][ns] = append(t.watchers[gvr][ns], fakewatcher)
+
+	return fakewatcher, nil
+}
+
+func (t *cowTracker) Add(obj runtime.Object) error {
+	if meta.IsListType(obj) {
+		list, err := meta.ExtractList(obj)
+		if err != nil {
+			return err
+		}
+		for _, obj := range list {
+			if err := t.Add(obj); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if _, ok := obj.(*unstructured.Unstructured); ok {
+		return errors.New("unstructured objects are not supported")
+	}
+	objMeta, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	gvks, _, err := scheme.Scheme.ObjectKinds(obj)
+	if err != nil {
+		return err
+	}
+
+	for _, gvk := range gvks {
+		gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+		if err := t.add(gvr, obj, objMeta.GetNamespace(), false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *cowTracker) Create(gvr schema.GroupVersionResource, obj runtime.Object, ns string) error {
+	return t.add(gvr, obj, ns, false)
+}
+
+func (t *cowTracker) Update(gvr schema.GroupVersionResource, obj runtime.Object, ns string) error {
+	return t.add(gvr, obj, ns, true)
+}
+
+func (t *cowTracker) add(gvr schema.GroupVersionResource, obj runtime.Object, ns string, replaceExisting bool) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	gr := gvr.GroupResource()
+
+	// always store the deep copy to avoid the object from being modified by caller
+	obj = obj.DeepCopyObject()
+
+	newMeta, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+
+	if len(newMeta.GetNamespace()) == 0 {
+		newMeta.SetNamespace(ns)
+	}
+
+	if ns != newMeta.GetNamespace() {
+		msg := fmt.Sprintf("request namespace does not match object namespace, request: %q object: %q", ns, newMeta.GetNamespace())
+		return apierrors.NewBadRequest(msg)
+	}
+
+	nn := types.NamespacedName{Namespace: newMeta.GetNamespace(), Name: newMeta.GetName()}
+	_, exists := t.get(gvr, nn)
+	if exists && !replaceExisting {
+		return apierrors.NewAlreadyExists(gr, newMeta.GetName())
+	}
+	if !exists && replaceExisting {
+		return apierrors.NewNotFound(gr, newMeta.GetName())
+	}
+
+	if _, ok := t.objects[gvr]; !ok {
+		t.objects[gvr] = make(map[types.NamespacedName]runtime.Object)
+	}
+	t.objects[gvr][nn] = obj
+	delete(t.tombstones[gvr], nn)
+
+	for _, w := range t.getWatches(gvr, ns) {
+		if exists {
+			w.Modify(obj.DeepCopyObject())
+		} else {
+			w.Add(obj.DeepCopyObject())
+		}
+	}
+
+	return nil
+}
+
+func (t *cowTracker) Delete(gvr schema.GroupVersionResource, ns, name string) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	nn := types.NamespacedName{Namespace: ns, Name: name}
+	obj, ok := t.get(gvr, nn)
+	if !ok {
+		return apierrors.NewNotFound(gvr.GroupResource(), name)
+	}
+
+	delete(t.objects[gvr], nn)
+	if _, ok := t.world.objects[gvr][nn]; ok {
+		if _, ok := t.tombstones[gvr]; !ok {
+			t.tombstones[gvr] = make(map[types.NamespacedName]struct{})
+		}
+		t.tombstones[gvr][nn] = struct{}{}
+	}
+
+	for _, w := range t.getWatches(gvr, ns) {
+		w.Delete(obj.DeepCopyObject())
+	}
+
+	return nil
+}
+
+func (t *cowTracker) getWatches(gvr schema.GroupVersionResource, ns string) []*watch.RaceFreeFakeWatcher {
+	var watches []*watch.RaceFreeFakeWatcher
+	if t.watchers[gvr] != nil {
+		watches = append(watches, t.watchers[gvr][ns]...)
+		if ns != metav1.NamespaceAll {
+			watches = append(watches, t.watchers[gvr][metav1.NamespaceAll]...)
+		}
+	}
+
+	return watches
+}
+
+// install makes the fake client read and write objects through the tracker instead of its default one
+func (t *cowTracker) install(fake *testing.Fake) {
+	fake.PrependReactor("*", "*", testing.ObjectReaction(t))
+	fake.PrependWatchReactor("*", func(action testing.Action) (bool, watch.Interface, error) {
+		w, err := t.Watch(action.GetResource(), action.GetNamespace())
+		if err != nil {
+			return false, nil, err
+		}
+		return true, w, nil
+	})
+}
@@ -0,0 +1,130 @@
+package history
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	FitLinear   = "linear"
+	FitSeasonal = "seasonal"
+)
+
+const day = 24 * time.Hour
+
+// the residuals of the seasonal fit are averaged per hour of the season
+const seasonalBucket = time.Hour
+
+type Point struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     int64     `json:"value"`
+}
+
+// model is a linear trend, optionally plus the mean residual of each hour of the season
+type model struct {
+	origin    time.Time
+	intercept float64
+	// change of the value per day
+	slope   float64
+	season  time.Duration
+	offsets []float64
+}
+
+// fit fits the points which are in time order, at least 2 points are needed
+func fit(points []Point, fitType string, season time.Duration) (*model, error) {
+	if len(points) < 2 {
+		return nil, fmt.Errorf("at least 2 points are needed to fit, got %d", len(points))
+	}
+
+	m := &model{origin: points[0].Timestamp}
+	var sumX, sumY, sumXX, sumXY float64
+	for _, p := range points {
+		x := m.days(p.Timestamp)
+		y := float64(p.Value)
+		sumX += x
+		sumY += y
+		sumXX += x * x
+		sumXY += x * y
+	}
+	n := float64(len(points))
+	if denominator := n*sumXX - sumX*sumX; denominator != 0 {
+		m.slope = (n*sumXY - sumX*sumY) / denominator
+	}
+	m.intercept = (sumY - m.slope*sumX) / n
+
+	if fitType != FitSeasonal {
+		return m, nil
+	}
+
+	if span := points[len(points)-1].Timestamp.Sub(points[0].Timestamp); span < season {
+		return nil, fmt.Errorf("seasonal fit needs at least one season %v of history, got %v", season, span)
+	}
+	m.season = season
+	m.offsets = make([]float64, int(math.Ceil(float64(season)/float64(seasonalBucket))))
+	counts := make([]int, len(m.offsets))
+	for _, p := range points {
+		bucket := m.bucket(p.Timestamp)
+		m.offsets[bucket] += float64(p.Value) - m.trend(p.Timestamp)
+		counts[bucket]++
+	}
+	for i := range m.offsets {
+		if counts[i] > 0 {
+			m.offsets[i] /= float64(counts[i])
+		}
+	}
+
+	return m, nil
+}
+
+func (m *model) days(t time.Time) float64 {
+	return float64(t.Sub(m.origin)) / float64(day)
+}
+
+func (m *model) bucket(t time.Time) int {
+	phase := t.Sub(m.origin) % m.season
+	if phase < 0 {
+		phase += m.season
+	}
+	return int(phase / seasonalBucket)
+}
+
+func (m *model) trend(t time.Time) float64 {
+	return m.intercept + m.slope*m.days(t)
+}
+
+func (m *model) predict(t time.Time) float64 {
+	if m.season == 0 {
+		return m.trend(t)
+	}
+	return m.trend(t) + m.offsets[m.bucket(t)]
+}
+
+// exhaustion returns the first time since from when the predicted value reaches zero, nil if it doesn't
+// happen within the horizon
+func (m *model) exhaustion(from time.Time, horizon time.Duration) *time.Time {
+	if m.predict(from) <= 0 {
+		return &from
+	}
+
+	if m.season == 0 {
+		if m.slope >= 0 {
+			return nil
+		}
+		// compared in days first so that a tiny slope doesn't overflow the duration
+		days := -m.intercept / m.slope
+		if days > m.days(from.Add(horizon)) {
+			return nil
+		}
+		t := m.origin.Add(time.Duration(days * float64(day)))
+		return &t
+	}
+
+	// the seasonal offsets could make the value reach zero even if the trend is flat
+	for t := from; !t.After(from.Add(horizon)); t = t.Add(seasonalBucket) {
+		if m.predict(t) <= 0 {
+			return &t
+		}
+	}
+	return nil
+}
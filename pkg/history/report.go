@@ -0,0 +1,172 @@
+package history
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+type HistoryReview struct {
+	Fit string `json:"fit"`
+	// only available when the fit is seasonal
+	Season  string         `json:"season,omitempty"`
+	Horizon string         `json:"horizon"`
+	Series  []SeriesReview `json:"series"`
+}
+
+// SeriesReview is the trend of a value tracked over time, e.g. the remaining replicas of a template
+type SeriesReview struct {
+	Command string  `json:"command"`
+	Name    string  `json:"name"`
+	Points  []Point `json:"points"`
+	// change of the value per day of the linear trend, only available when there are at least 2 points
+	TrendPerDay *float64 `json:"trendPerDay,omitempty"`
+	// when the value is projected to reach zero, only forecast for the remaining replicas of ce and the
+	// removable nodes of cc
+	Exhaustion *time.Time `json:"exhaustion,omitempty"`
+	// why the value couldn't be forecast
+	ForecastError string `json:"forecastError,omitempty"`
+}
+
+// Analyze groups the values of the records, which are in time order, into series and forecasts them
+func Analyze(records []*Record, fitType string, season, horizon time.Duration) *HistoryReview {
+	r := &HistoryReview{
+		Fit:     fitType,
+		Horizon: horizon.String(),
+	}
+	if fitType == FitSeasonal {
+		r.Season = season.String()
+	}
+
+	type seriesKey struct {
+		command string
+		name    string
+	}
+	series := make(map[seriesKey]*SeriesReview)
+	for _, record := range records {
+		for name, value := range record.Values {
+			key := seriesKey{command: record.Command, name: name}
+			if series[key] == nil {
+				series[key] = &SeriesReview{Command: record.Command, Name: name}
+			}
+			series[key].Points = append(series[key].Points, Point{Timestamp: record.Timestamp, Value: value})
+		}
+	}
+
+	for _, s := range series {
+		forecast(s, fitType, season, horizon)
+		r.Series = append(r.Series, *s)
+	}
+	sort.Slice(r.Series, func(i, j int) bool {
+		if r.Series[i].Command != r.Series[j].Command {
+			return r.Series[i].Command < r.Series[j].Command
+		}
+		return r.Series[i].Name < r.Series[j].Name
+	})
+
+	return r
+}
+
+func forecast(s *SeriesReview, fitType string, season, horizon time.Duration) {
+	if len(s.Points) < 2 {
+		s.ForecastError = "not enough history"
+		return
+	}
+
+	// the trend is always linear
+	linear, _ := fit(s.Points, FitLinear, season)
+	s.TrendPerDay = &linear.slope
+
+	if s.Command != CommandCapacityEstimation && s.Command != CommandClusterCompression {
+		return
+	}
+
+	m, err := fit(s.Points, fitType, season)
+	if err != nil {
+		s.ForecastError = err.Error()
+		return
+	}
+	s.Exhaustion = m.exhaustion(s.Points[len(s.Points)-1].Timestamp, horizon)
+}
+
+func (r *HistoryReview) Print(verbose bool, format string) error {
+	switch format {
+	case "json":
+		return utils.PrintJson(r)
+	case "yaml":
+		return utils.PrintYaml(r)
+	case "":
+		historyPrettyPrint(r, verbose)
+		return nil
+	default:
+		return fmt.Errorf("output format %q not recognized", format)
+	}
+}
+
+func historyPrettyPrint(r *HistoryReview, verbose bool) {
+	if r.Season != "" {
+		fmt.Printf("Fit: %s (season: %s)\n", r.Fit, r.Season)
+	} else {
+		fmt.Printf("Fit: %s\n", r.Fit)
+	}
+	fmt.Printf("Horizon: %s\n\n", r.Horizon)
+
+	if len(r.Series) == 0 {
+		fmt.Println("No history recorded.")
+		return
+	}
+
+	now := time.Now()
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"command", "series", "samples", "since", "first", "last", "trend/day", "runs out"})
+	for _, s := range r.Series {
+		first, last := s.Points[0], s.Points[len(s.Points)-1]
+		trend := "-"
+		if s.TrendPerDay != nil {
+			trend = fmt.Sprintf("%+.2f", *s.TrendPerDay)
+		}
+		t.AppendRow(table.Row{s.Command, s.Name, len(s.Points), first.Timestamp.Local().Format(time.RFC3339),
+			first.Value, last.Value, trend, formatExhaustion(s, now)})
+	}
+	fmt.Println(t.Render())
+
+	if !verbose {
+		return
+	}
+	for _, s := range r.Series {
+		fmt.Printf("\n%s %s:\n", s.Command, s.Name)
+		t := table.NewWriter()
+		t.AppendHeader(table.Row{"timestamp", "value"})
+		for _, p := range s.Points {
+			t.AppendRow(table.Row{p.Timestamp.Local().Format(time.RFC3339), p.Value})
+		}
+		fmt.Println(t.Render())
+	}
+}
+
+func formatExhaustion(s SeriesReview, now time.Time) string {
+	if len(s.ForecastError) > 0 {
+		return s.ForecastError
+	}
+	if s.Command != CommandCapacityEstimation && s.Command != CommandClusterCompression {
+		return "-"
+	}
+	if s.Exhaustion == nil {
+		return "not within the horizon"
+	}
+
+	date := s.Exhaustion.Local().Format("2006-01-02")
+	until := s.Exhaustion.Sub(now)
+	switch {
+	case until <= 0:
+		return fmt.Sprintf("%s (already)", date)
+	case until < 14*day:
+		return fmt.Sprintf("%s (in %d days)", date, int(until/day))
+	default:
+		return fmt.Sprintf("%s (in %d weeks)", date, int(until/(7*day)))
+	}
+}
@@ -0,0 +1,213 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/capacityestimation"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/clustercompression"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/hpaburst"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/resilience"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/schedulersimulation"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/upgrade"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/whatif"
+)
+
+// Version is the version of the records written to the store, it's increased when the record is changed
+// incompatibly
+const Version = "v1"
+
+const (
+	CommandCapacityEstimation  = "ce"
+	CommandClusterCompression  = "cc"
+	CommandSchedulerSimulation = "ss"
+	CommandSSTune              = "ss-tune"
+	CommandSSCompare           = "ss-compare"
+	CommandHPABurst            = "hb"
+	CommandWhatIf              = "whatif"
+	CommandResilience          = "resilience"
+	CommandUpgrade             = "upgrade"
+
+	// names of the values tracked, the values of ce are named by the templates and the ones of whatif are
+	// prefixed by the steps
+	ValueRemovableNodes        = "removable-nodes"
+	ValueNodesUsed             = "nodes-used"
+	ValueUnschedulablePods     = "unschedulable-pods"
+	ValuePodsPlacedDifferently = "pods-placed-differently"
+	ValueMissingReplicas       = "missing-replicas"
+	ValueFailedDomains         = "failed-domains"
+	ValueDisruptedBatches      = "disrupted-batches"
+)
+
+const timestampLayout = "20060102T150405.000000000Z"
+
+// Record is the result of a run appended to the store
+type Record struct {
+	Version   string    `json:"version"`
+	Command   string    `json:"command"`
+	Timestamp time.Time `json:"timestamp"`
+	// the values tracked over time, e.g. the remaining replicas of each template for ce
+	Values map[string]int64 `json:"values"`
+	// the same as the output of the command with -o json
+	Review json.RawMessage `json:"review"`
+}
+
+// NewRecord returns the record of the review printed by the command
+func NewRecord(command string, review pkg.Printer) (*Record, error) {
+	values := make(map[string]int64)
+	switch r := review.(type) {
+	case capacityestimation.CapacityEstimationReviews:
+		for _, review := range r {
+			values[review.Spec.Templates[0].Name] = int64(review.Status.Replicas)
+		}
+	case *capacityestimation.CapacityEstimationReview:
+		values[r.Spec.Templates[0].Name] = int64(r.Status.Replicas)
+	case *clustercompression.ClusterCompressionReview:
+		values[ValueRemovableNodes] = int64(len(r.Status.ScaleDownNodeNames))
+	case *schedulersimulation.SchedulerSimulationReview:
+		values[ValueNodesUsed] = int64(r.Metrics.NodesUsed)
+	case *schedulersimulation.SchedulerSimulationTuneReview:
+		// the best trial, or the top ranked one if every trial leaves some pods unschedulable
+		trial := r.Best
+		if trial == nil && len(r.Trials) > 0 {
+			trial = &r.Trials[0]
+		}
+		if trial != nil {
+			values[ValueNodesUsed] = int64(trial.Metrics.NodesUsed)
+			values[ValueUnschedulablePods] = int64(trial.UnschedulablePods)
+		}
+	case *schedulersimulation.SchedulerSimulationComparison:
+		values["base/"+ValueNodesUsed] = int64(r.Base.NodesUsed)
+		values["target/"+ValueNodesUsed] = int64(r.Target.NodesUsed)
+		values[ValuePodsPlacedDifferently] = int64(len(r.PodsPlacedDifferently))
+	case *hpaburst.HPABurstReview:
+		var missing int64
+		for _, hpa := range r.Status.HPAs {
+			missing += int64(hpa.MissingReplicas)
+		}
+		values[ValueMissingReplicas] = missing
+	case *whatif.WhatIfReview:
+		for i, step := range r.Steps {
+			name := step.Name
+			if len(name) == 0 {
+				name = fmt.Sprintf("step-%d", i+1)
+			}
+			for _, estimate := range step.Estimates {
+				values[name+"/"+estimate.Template] = int64(estimate.Replicas)
+			}
+			if step.Unschedulable != nil {
+				values[name+"/"+ValueUnschedulablePods] = int64(*step.Unschedulable)
+			}
+		}
+	case *resilience.ResilienceReview:
+		values[ValueFailedDomains] = int64(len(r.Failed()))
+	case *upgrade.UpgradeReview:
+		disrupted := 0
+		for i := range r.Batches {
+			if r.Batches[i].Disrupted() {
+				disrupted++
+			}
+		}
+		values[ValueDisruptedBatches] = int64(disrupted)
+	default:
+		return nil, fmt.Errorf("review %T of %s is not supported by the history", review, command)
+	}
+
+	data, err := json.Marshal(review)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Record{
+		Version:   Version,
+		Command:   command,
+		Timestamp: time.Now().UTC(),
+		Values:    values,
+		Review:    data,
+	}, nil
+}
+
+// Store keeps the records in a directory, one JSON file per record
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Append records the review printed by the command
+func Append(dir, command string, review pkg.Printer) error {
+	record, err := NewRecord(command, review)
+	if err != nil {
+		return err
+	}
+
+	return NewStore(dir).Append(record)
+}
+
+// Append writes the record to the store, the directory is created if it doesn't exist
+func (s *Store) Append(record *Record) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.json", record.Command, record.Timestamp.UTC().Format(timestampLayout))
+	return os.WriteFile(filepath.Join(s.dir, name), data, 0644)
+}
+
+// List returns the records of the command written since the time in time order, all the commands are
+// returned if the command is empty
+func (s *Store) List(command string, since time.Time) ([]*Record, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []*Record
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		if len(command) > 0 && !strings.HasPrefix(entry.Name(), command+"-") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		record := &Record{}
+		if err := json.Unmarshal(data, record); err != nil {
+			return nil, fmt.Errorf("failed to decode record %s: %v", entry.Name(), err)
+		}
+		if record.Version != Version {
+			return nil, fmt.Errorf("version %q of record %s is not supported", record.Version, entry.Name())
+		}
+		// ss is a prefix of ss-tune and ss-compare
+		if len(command) > 0 && record.Command != command {
+			continue
+		}
+		if record.Timestamp.Before(since) {
+			continue
+		}
+		records = append(records, record)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Timestamp.Before(records[j].Timestamp)
+	})
+
+	return records, nil
+}
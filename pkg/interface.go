@@ -13,6 +13,8 @@ type Status struct {
 	Nodes map[string]corev1.Node `json:"nodes"`
 	// for ce
 	PodsForEstimation []*corev1.Pod `json:"pods_for_estimation"`
+	// for ce with preemption, pods evicted by the simulated pods
+	PreemptedPods []*corev1.Pod `json:"preempted_pods"`
 	// for cc
 	NodesToScaleDown []string `json:"nodes_to_scale_down"`
 	StopReason       string   `json:"stop_reason"`
@@ -23,10 +25,16 @@ type Framework interface {
 	Run() error
 	InitTheWorld(objs ...runtime.Object) error
 	CreatePod(pod *corev1.Pod) error
+	// Admit returns a copy of the pod admitted the same way as the pods created
+	Admit(pod *corev1.Pod) (*corev1.Pod, error)
 	UpdateEstimationPods(pod ...*corev1.Pod)
+	UpdatePreemptedPods(pod ...*corev1.Pod)
 	UpdateNodesToScaleDown(nodeName string)
 	Status() Status
 	GetPodsByNode(nodeName string) ([]*corev1.Pod, error)
+	// Objects returns copies of the objects of the world as it is now, so that another simulation could
+	// be initialized from them
+	Objects() ([]runtime.Object, error)
 	Stop(reason string) error
 }
 
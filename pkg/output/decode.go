@@ -0,0 +1,165 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	outputv1alpha1 "github.com/k-cloud-labs/kluster-capacity/pkg/apis/output/v1alpha1"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/history"
+)
+
+// Load reads a result of ce, cc or ss and converts it to the hub version, see Decode
+func Load(path string) (conversion.Hub, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	hub, err := Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read result %s: %v", path, err)
+	}
+
+	return hub, nil
+}
+
+// Decode converts a result of ce, cc or ss to the hub version. The result is either a versioned one in json or
+// yaml, an unversioned one printed with -o json before the output was versioned, or a record of the history
+// store. The unversioned reviews of the templates of ce are printed one by one separated by a line of dashes,
+// they are read as one result.
+func Decode(data []byte) (conversion.Hub, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New("no result found")
+	}
+
+	// the unversioned results are always json
+	if data[0] != '{' && data[0] != '[' {
+		var err error
+		if data, err = yaml.YAMLToJSON(data); err != nil {
+			return nil, err
+		}
+	}
+
+	var typeMeta struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+	}
+	// a stream of unversioned reviews fails to be decoded as one object
+	if err := json.Unmarshal(data, &typeMeta); err == nil && len(typeMeta.APIVersion) > 0 {
+		obj, _, err := Codecs.UniversalDeserializer().Decode(data, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		if hub, ok := obj.(conversion.Hub); ok {
+			return hub, nil
+		}
+		spoke, ok := obj.(conversion.Convertible)
+		if !ok {
+			return nil, fmt.Errorf("%s %s is not a result of ce, cc or ss", typeMeta.APIVersion, typeMeta.Kind)
+		}
+		return toHub(spoke)
+	}
+
+	spoke, err := decodeUnversioned(data)
+	if err != nil {
+		return nil, err
+	}
+	return toHub(spoke)
+}
+
+func decodeUnversioned(data []byte) (conversion.Convertible, error) {
+	// drop the separators between the reviews of ce
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if trimmed := strings.TrimSpace(line); len(trimmed) > 0 && strings.Trim(trimmed, "-") == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	var values []json.RawMessage
+	decoder := json.NewDecoder(strings.NewReader(strings.Join(lines, "\n")))
+	for {
+		var value json.RawMessage
+		err := decoder.Decode(&value)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	if len(values) == 0 {
+		return nil, errors.New("no result found")
+	}
+
+	// the reviews of ce are either printed one by one or recorded as a list
+	if len(values) > 1 || bytes.HasPrefix(bytes.TrimSpace(values[0]), []byte("[")) {
+		list := &outputv1alpha1.CapacityEstimationReviewList{}
+		for _, value := range values {
+			if bytes.HasPrefix(bytes.TrimSpace(value), []byte("[")) {
+				var items []outputv1alpha1.CapacityEstimationReview
+				if err := json.Unmarshal(value, &items); err != nil {
+					return nil, err
+				}
+				list.Items = append(list.Items, items...)
+				continue
+			}
+			item := outputv1alpha1.CapacityEstimationReview{}
+			if err := json.Unmarshal(value, &item); err != nil {
+				return nil, err
+			}
+			list.Items = append(list.Items, item)
+		}
+		return list, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(values[0], &fields); err != nil {
+		return nil, err
+	}
+	var spoke conversion.Convertible
+	switch {
+	case fields["review"] != nil && fields["command"] != nil:
+		record := &history.Record{}
+		if err := json.Unmarshal(values[0], record); err != nil {
+			return nil, err
+		}
+		if record.Version != history.Version {
+			return nil, fmt.Errorf("version %q of the record is not supported", record.Version)
+		}
+		switch record.Command {
+		case history.CommandCapacityEstimation, history.CommandClusterCompression, history.CommandSchedulerSimulation:
+		default:
+			return nil, fmt.Errorf("the record of %s is not a result of ce, cc or ss", record.Command)
+		}
+		return decodeUnversioned(record.Review)
+	case fields["spec"] != nil:
+		item := outputv1alpha1.CapacityEstimationReview{}
+		if err := json.Unmarshal(values[0], &item); err != nil {
+			return nil, err
+		}
+		return &outputv1alpha1.CapacityEstimationReviewList{Items: []outputv1alpha1.CapacityEstimationReview{item}}, nil
+	case fields["status"] != nil:
+		spoke = &outputv1alpha1.ClusterCompressionReview{}
+	case fields["details"] != nil || fields["metrics"] != nil:
+		spoke = &outputv1alpha1.SchedulerSimulationReview{}
+	default:
+		return nil, errors.New("not a result of ce, cc or ss")
+	}
+
+	if err := json.Unmarshal(values[0], spoke); err != nil {
+		return nil, err
+	}
+	return spoke, nil
+}
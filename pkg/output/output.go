@@ -0,0 +1,224 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	outputv1 "github.com/k-cloud-labs/kluster-capacity/pkg/apis/output/v1"
+	outputv1alpha1 "github.com/k-cloud-labs/kluster-capacity/pkg/apis/output/v1alpha1"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/capacityestimation"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/clustercompression"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/schedulersimulation"
+)
+
+const (
+	VersionV1       = "v1"
+	VersionV1alpha1 = "v1alpha1"
+)
+
+// reviewSeparator separates the reviews of the templates of ce printed one by one
+const reviewSeparator = "---------------------------------------------------------------"
+
+// Versions are the versions of the output API, the latest first
+var Versions = []string{VersionV1, VersionV1alpha1}
+
+var (
+	Scheme = runtime.NewScheme()
+	Codecs = serializer.NewCodecFactory(Scheme)
+)
+
+func init() {
+	utilruntime.Must(outputv1.AddToScheme(Scheme))
+	utilruntime.Must(outputv1alpha1.AddToScheme(Scheme))
+}
+
+// Validate checks the version of the output api
+func Validate(version string) error {
+	for _, v := range Versions {
+		if version == v {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("output version %q not recognized, must be one of %s", version, strings.Join(Versions, "|"))
+}
+
+// IsSupported returns true if the review is printed with the versioned output API
+func IsSupported(review pkg.Printer) bool {
+	switch review.(type) {
+	case capacityestimation.CapacityEstimationReviews, *capacityestimation.CapacityEstimationReview,
+		*clustercompression.ClusterCompressionReview, *schedulersimulation.SchedulerSimulationReview:
+		return true
+	default:
+		return false
+	}
+}
+
+// ToHub converts the review of ce, cc or ss to the hub version. The reviews are in the shape of v1alpha1, which
+// is the shape they were printed in before the output was versioned.
+func ToHub(review pkg.Printer) (conversion.Hub, error) {
+	var spoke conversion.Convertible
+	switch r := review.(type) {
+	case capacityestimation.CapacityEstimationReviews:
+		spoke = &outputv1alpha1.CapacityEstimationReviewList{}
+		if err := roundTrip(struct {
+			Items capacityestimation.CapacityEstimationReviews `json:"items"`
+		}{Items: r}, spoke); err != nil {
+			return nil, err
+		}
+	case *capacityestimation.CapacityEstimationReview:
+		return ToHub(capacityestimation.CapacityEstimationReviews{r})
+	case *clustercompression.ClusterCompressionReview:
+		spoke = &outputv1alpha1.ClusterCompressionReview{}
+		if err := roundTrip(r, spoke); err != nil {
+			return nil, err
+		}
+	case *schedulersimulation.SchedulerSimulationReview:
+		spoke = &outputv1alpha1.SchedulerSimulationReview{}
+		if err := roundTrip(r, spoke); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("review %T is not supported by the output api", review)
+	}
+
+	return toHub(spoke)
+}
+
+// Convert converts the object of any version to the version
+func Convert(obj runtime.Object, version string) (runtime.Object, error) {
+	hub, ok := obj.(conversion.Hub)
+	if !ok {
+		spoke, ok := obj.(conversion.Convertible)
+		if !ok {
+			return nil, fmt.Errorf("%T is not a type of the output api", obj)
+		}
+		var err error
+		if hub, err = toHub(spoke); err != nil {
+			return nil, err
+		}
+	}
+
+	switch version {
+	case VersionV1:
+		return hub, nil
+	case VersionV1alpha1:
+		var spoke conversion.Convertible
+		switch hub.(type) {
+		case *outputv1.CapacityEstimationReview:
+			spoke = &outputv1alpha1.CapacityEstimationReviewList{}
+		case *outputv1.ClusterCompressionReview:
+			spoke = &outputv1alpha1.ClusterCompressionReview{}
+		case *outputv1.SchedulerSimulationReview:
+			spoke = &outputv1alpha1.SchedulerSimulationReview{}
+		}
+		if err := spoke.ConvertFrom(hub); err != nil {
+			return nil, err
+		}
+		return spoke, nil
+	default:
+		return nil, fmt.Errorf("version %q of the output api not recognized", version)
+	}
+}
+
+// Print prints the review of ce, cc or ss in the version of the output api if the format is json or yaml, the
+// other formats and reviews are printed by the review itself. The reviews are in the shape of v1alpha1, so they
+// are printed by themselves in v1alpha1 too.
+func Print(review pkg.Printer, verbose bool, format, version string) error {
+	if !IsSupported(review) || (format != "json" && format != "yaml") || version == VersionV1alpha1 {
+		return review.Print(verbose, format)
+	}
+
+	hub, err := ToHub(review)
+	if err != nil {
+		return err
+	}
+
+	obj, err := Convert(hub, version)
+	if err != nil {
+		return err
+	}
+
+	return PrintObject(obj, format)
+}
+
+// PrintObject prints the object of the output api as json or yaml. The objects of v1alpha1 are printed in the
+// shape before the output was versioned: without apiVersion and kind, and the reviews of the templates of ce one
+// by one separated by a line of dashes.
+func PrintObject(obj runtime.Object, format string) error {
+	switch o := obj.(type) {
+	case *outputv1alpha1.CapacityEstimationReviewList:
+		for i := range o.Items {
+			if i > 0 {
+				fmt.Println(reviewSeparator)
+			}
+			if err := printValue(&o.Items[i], format); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *outputv1alpha1.ClusterCompressionReview, *outputv1alpha1.SchedulerSimulationReview:
+		obj = obj.DeepCopyObject()
+		obj.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{})
+	}
+
+	return printValue(obj, format)
+}
+
+// printValue prints the value the same as utils.PrintJson and utils.PrintYaml
+func printValue(v interface{}, format string) error {
+	switch format {
+	case "json":
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to create json: %v", err)
+		}
+		fmt.Println(string(data))
+	case "yaml":
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to create yaml: %v", err)
+		}
+		fmt.Print(string(data))
+	default:
+		return fmt.Errorf("output format %q not recognized", format)
+	}
+
+	return nil
+}
+
+func toHub(spoke conversion.Convertible) (conversion.Hub, error) {
+	var hub conversion.Hub
+	switch spoke.(type) {
+	case *outputv1alpha1.CapacityEstimationReviewList:
+		hub = &outputv1.CapacityEstimationReview{}
+	case *outputv1alpha1.ClusterCompressionReview:
+		hub = &outputv1.ClusterCompressionReview{}
+	case *outputv1alpha1.SchedulerSimulationReview:
+		hub = &outputv1.SchedulerSimulationReview{}
+	default:
+		return nil, fmt.Errorf("%T is not a type of the output api", spoke)
+	}
+
+	if err := spoke.ConvertTo(hub); err != nil {
+		return nil, err
+	}
+	return hub, nil
+}
+
+func roundTrip(in, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
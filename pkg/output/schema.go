@@ -0,0 +1,148 @@
+package output
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	outputv1 "github.com/k-cloud-labs/kluster-capacity/pkg/apis/output/v1"
+	outputv1alpha1 "github.com/k-cloud-labs/kluster-capacity/pkg/apis/output/v1alpha1"
+)
+
+const (
+	// JSONSchemaDraft is the draft of the JSON Schema generated
+	JSONSchemaDraft = "http://json-schema.org/draft-07/schema#"
+	corev1Path      = "k8s.io/api/core/v1"
+)
+
+var (
+	timeType     = reflect.TypeOf(metav1.Time{})
+	quantityType = reflect.TypeOf(resource.Quantity{})
+	typeMetaType = reflect.TypeOf(metav1.TypeMeta{})
+)
+
+// Kinds returns the kinds of the version of the output api
+func Kinds(version string) []string {
+	var kinds []string
+	for kind := range Scheme.KnownTypes(schema.GroupVersion{Group: outputv1.GroupName, Version: version}) {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	return kinds
+}
+
+// Schema returns the JSON Schema of the kind in the version of the output api. It's generated from the json tags
+// of the types, the fields with omitempty are optional and the objects of the core api, e.g. the templates of ce,
+// are left free-form. The results of v1alpha1 are printed without apiVersion and kind, and the reviews of the
+// templates of ce one by one, so the schema of ce in v1alpha1 is the one of each review.
+func Schema(version, kind string) (map[string]interface{}, error) {
+	gv := schema.GroupVersion{Group: outputv1.GroupName, Version: version}
+	t, ok := Scheme.KnownTypes(gv)[kind]
+	if !ok {
+		return nil, fmt.Errorf("kind %q not found in %s, must be one of %s", kind, gv, strings.Join(Kinds(version), "|"))
+	}
+
+	if version == VersionV1alpha1 && t == reflect.TypeOf(outputv1alpha1.CapacityEstimationReviewList{}) {
+		t = reflect.TypeOf(outputv1alpha1.CapacityEstimationReview{})
+	}
+
+	s := typeSchema(t)
+	s["$schema"] = JSONSchemaDraft
+	s["title"] = kind
+	if version == VersionV1alpha1 {
+		return s, nil
+	}
+
+	properties := s["properties"].(map[string]interface{})
+	properties["apiVersion"] = map[string]interface{}{"type": "string", "const": gv.String()}
+	properties["kind"] = map[string]interface{}{"type": "string", "const": kind}
+	s["required"] = append([]string{"apiVersion", "kind"}, s["required"].([]string)...)
+
+	return s, nil
+}
+
+func typeSchema(t reflect.Type) map[string]interface{} {
+	switch t {
+	case timeType:
+		return map[string]interface{}{"type": []string{"string", "null"}, "format": "date-time"}
+	case quantityType:
+		return map[string]interface{}{"anyOf": []interface{}{
+			map[string]interface{}{"type": "string"},
+			map[string]interface{}{"type": "number"},
+		}}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(typeSchema(t.Elem()))
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return nullable(map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())})
+	case reflect.Map:
+		return nullable(map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem())})
+	case reflect.Struct:
+		// the objects of the core api are documented by kubernetes
+		if t.PkgPath() == corev1Path {
+			return map[string]interface{}{"type": "object"}
+		}
+		properties := make(map[string]interface{})
+		required := make([]string, 0)
+		addFields(t, properties, &required)
+		return map[string]interface{}{"type": "object", "properties": properties, "required": required}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// addFields adds the fields of the struct to the properties, the inlined ones are added as the fields of the struct
+func addFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && len(name) == 0 {
+			// apiVersion and kind are set by Schema
+			if field.Type != typeMetaType {
+				addFields(field.Type, properties, required)
+			}
+			continue
+		}
+		if len(name) == 0 {
+			name = field.Name
+		}
+
+		properties[name] = typeSchema(field.Type)
+		if !strings.Contains(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// nullable allows null, which nil pointers, slices and maps are printed as
+func nullable(s map[string]interface{}) map[string]interface{} {
+	if typ, ok := s["type"].(string); ok {
+		s["type"] = []string{typ, "null"}
+		return s
+	}
+
+	return map[string]interface{}{"anyOf": []interface{}{s, map[string]interface{}{"type": "null"}}}
+}
@@ -0,0 +1,116 @@
+package replayorder
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+const Name = "ReplayOrder"
+
+const (
+	// CreationTimestamp replays pods as they were created
+	CreationTimestamp = "CreationTimestamp"
+	// Priority replays pods with higher priority first
+	Priority = "Priority"
+	// LargestRequestFirst replays pods with larger cpu request first and then larger memory request,
+	// which is the first-fit decreasing bin packing
+	LargestRequestFirst = "LargestRequestFirst"
+	// Owner replays pods grouped by their controller
+	Owner = "Owner"
+	// Random replays pods in a random order decided by the seed
+	Random = "Random"
+)
+
+// ReplayOrder is a queue sort plugin which decides the order of the pods entering the scheduling cycle.
+// Pods which are equal in the order are sorted by creation timestamp, namespace and name to keep the
+// replay deterministic.
+type ReplayOrder struct {
+	less func(pod1, pod2 *corev1.Pod) (less bool, equal bool)
+}
+
+var _ framework.QueueSortPlugin = &ReplayOrder{}
+
+// NewFactory returns the factory of the plugin for the order.
+func NewFactory(order string, seed int64) (frameworkruntime.PluginFactory, error) {
+	var less func(pod1, pod2 *corev1.Pod) (bool, bool)
+	switch order {
+	case CreationTimestamp:
+		less = func(pod1, pod2 *corev1.Pod) (bool, bool) {
+			return false, true
+		}
+	case Priority:
+		less = func(pod1, pod2 *corev1.Pod) (bool, bool) {
+			p1, p2 := corev1helpers.PodPriority(pod1), corev1helpers.PodPriority(pod2)
+			return p1 > p2, p1 == p2
+		}
+	case LargestRequestFirst:
+		less = func(pod1, pod2 *corev1.Pod) (bool, bool) {
+			r1, r2 := utils.ComputePodResourceRequest(pod1), utils.ComputePodResourceRequest(pod2)
+			if r1.MilliCPU != r2.MilliCPU {
+				return r1.MilliCPU > r2.MilliCPU, false
+			}
+			return r1.Memory > r2.Memory, r1.Memory == r2.Memory
+		}
+	case Owner:
+		less = func(pod1, pod2 *corev1.Pod) (bool, bool) {
+			o1, o2 := ownerKey(pod1), ownerKey(pod2)
+			return o1 < o2, o1 == o2
+		}
+	case Random:
+		less = func(pod1, pod2 *corev1.Pod) (bool, bool) {
+			h1, h2 := hash(seed, pod1), hash(seed, pod2)
+			return h1 < h2, h1 == h2
+		}
+	default:
+		return nil, fmt.Errorf("replay order %q not recognized", order)
+	}
+
+	return func(_ runtime.Object, _ framework.Handle) (framework.Plugin, error) {
+		return &ReplayOrder{less: less}, nil
+	}, nil
+}
+
+func (r *ReplayOrder) Name() string {
+	return Name
+}
+
+func (r *ReplayOrder) Less(pInfo1, pInfo2 *framework.QueuedPodInfo) bool {
+	pod1, pod2 := pInfo1.Pod, pInfo2.Pod
+	if less, equal := r.less(pod1, pod2); !equal {
+		return less
+	}
+
+	if !pod1.CreationTimestamp.Equal(&pod2.CreationTimestamp) {
+		return pod1.CreationTimestamp.Before(&pod2.CreationTimestamp)
+	}
+	if pod1.Namespace != pod2.Namespace {
+		return pod1.Namespace < pod2.Namespace
+	}
+
+	return pod1.Name < pod2.Name
+}
+
+func ownerKey(pod *corev1.Pod) string {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller != nil && *ref.Controller {
+			return pod.Namespace + "/" + ref.Kind + "/" + ref.Name
+		}
+	}
+
+	// pods without controller are groups of their own
+	return pod.Namespace + "/Pod/" + pod.Name
+}
+
+func hash(seed int64, pod *corev1.Pod) uint64 {
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%d/%s/%s", seed, pod.Namespace, pod.Name)
+	return h.Sum64()
+}
@@ -0,0 +1,79 @@
+package scenario
+
+import (
+	"fmt"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+// ScenarioReview combines the reviews of all the runs of a scenario
+type ScenarioReview struct {
+	// path of the scenario file
+	Scenario string      `json:"scenario"`
+	Runs     []RunReview `json:"runs"`
+}
+
+type RunReview struct {
+	Name     string `json:"name"`
+	Command  string `json:"command"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+	// the same as the output of the command with -o json, nil if the run failed
+	Review pkg.Printer `json:"review,omitempty"`
+}
+
+// Failed returns the number of the failed runs
+func (r *ScenarioReview) Failed() int {
+	failed := 0
+	for _, run := range r.Runs {
+		if len(run.Error) > 0 {
+			failed++
+		}
+	}
+
+	return failed
+}
+
+func (r *ScenarioReview) Print(verbose bool, format string) error {
+	switch format {
+	case "json":
+		return utils.PrintJson(r)
+	case "yaml":
+		return utils.PrintYaml(r)
+	case "":
+		return scenarioPrettyPrint(r, verbose)
+	default:
+		return fmt.Errorf("output format %q not recognized", format)
+	}
+}
+
+func scenarioPrettyPrint(r *ScenarioReview, verbose bool) error {
+	fmt.Printf("Scenario: %s\n", r.Scenario)
+
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"run", "command", "duration", "result"})
+	for _, run := range r.Runs {
+		result := "succeeded"
+		if len(run.Error) > 0 {
+			result = "failed: " + run.Error
+		}
+		t.AppendRow(table.Row{run.Name, run.Command, run.Duration, result})
+
+		fmt.Printf("\n=== %s (%s) ===\n", run.Name, run.Command)
+		if run.Review == nil {
+			fmt.Printf("Error: %s\n", run.Error)
+			continue
+		}
+		if err := run.Review.Print(verbose, ""); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("\nSummary:\n")
+	fmt.Println(t.Render())
+
+	return nil
+}
@@ -0,0 +1,216 @@
+package scenario
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+
+	ceoptions "github.com/k-cloud-labs/kluster-capacity/app/cmds/capacityestimation/options"
+	ccoptions "github.com/k-cloud-labs/kluster-capacity/app/cmds/clustercompression/options"
+	ssoptions "github.com/k-cloud-labs/kluster-capacity/app/cmds/schedulersimulation/options"
+	whatifoptions "github.com/k-cloud-labs/kluster-capacity/app/cmds/whatif/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/framework"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/history"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/capacityestimation"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/clustercompression"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/schedulersimulation"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/whatif"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/usage"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+// Execute loads the world once and executes the runs of the scenario one by one in it. A failed run doesn't
+// stop the following ones, its error is reported instead.
+func Execute(s *Scenario, path string) (*ScenarioReview, error) {
+	objs, err := loadWorld(s.World)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the world: %v", err)
+	}
+
+	review := &ScenarioReview{Scenario: path}
+	for i := range s.Runs {
+		run := &s.Runs[i]
+		klog.V(2).InfoS("Running", "run", run.Name, "command", run.Command())
+
+		start := time.Now()
+		report, err := s.execute(run, objs)
+		result := RunReview{
+			Name:     run.Name,
+			Command:  run.Command(),
+			Duration: time.Since(start).Round(time.Millisecond).String(),
+			Review:   report,
+		}
+		if err == nil && len(s.History) > 0 {
+			err = history.Append(s.History, run.Command(), usage.Requested(report))
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		review.Runs = append(review.Runs, result)
+	}
+
+	return review, nil
+}
+
+func loadWorld(world World) ([]runtime.Object, error) {
+	if len(world.Snapshot) > 0 {
+		return framework.GetInitObjectsFromFile(world.Snapshot)
+	}
+
+	kubeConfig, err := utils.BuildRestConfig(world.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	return framework.GetInitObjectsFromCluster(kubeConfig)
+}
+
+func (s *Scenario) execute(run *Run, objs []runtime.Object) (pkg.Printer, error) {
+	schedulerConfig := s.SchedulerConfig
+	if len(run.SchedulerConfig) > 0 {
+		schedulerConfig = run.SchedulerConfig
+	}
+
+	execute := func(objs []runtime.Object) (pkg.Printer, error) {
+		var (
+			sim pkg.Simulator
+			err error
+		)
+		switch {
+		case run.CE != nil:
+			sim, err = newCapacityEstimationSimulator(run.CE, schedulerConfig, objs)
+		case run.CC != nil:
+			sim, err = newClusterCompressionSimulator(run.CC, schedulerConfig, objs)
+		case run.WhatIf != nil:
+			sim, err = newWhatIfSimulator(run.WhatIf, schedulerConfig, objs)
+		default:
+			sim, err = newSchedulerSimulationSimulator(run.SS, schedulerConfig, objs)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return simulator.Run(sim, objs)
+	}
+
+	if run.Usage != nil {
+		review, err := usage.Compare(run.Usage.Options(), objs, execute)
+		if err != nil {
+			return nil, err
+		}
+		return review, nil
+	}
+	return execute(objs)
+}
+
+func newCapacityEstimationSimulator(run *CapacityEstimationRun, schedulerConfig string, objs []runtime.Object) (pkg.Simulator, error) {
+	opt := ceoptions.NewCapacityEstimationOptions()
+	opt.SchedulerConfig = schedulerConfig
+	opt.MaxLimit = run.MaxLimit
+	opt.ExcludeNodes = run.ExcludeNodes
+	opt.EnablePreemption = run.EnablePreemption
+	if run.BatchSize > 0 {
+		opt.BatchSize = run.BatchSize
+	}
+	if run.Parallelism > 0 {
+		opt.Parallelism = run.Parallelism
+	}
+	conf := ceoptions.NewCapacityEstimationConfig(opt)
+	conf.InitObjs = objs
+
+	for _, template := range run.PodsFromTemplate {
+		pod, err := utils.GetPodFromTemplate(template)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pod from template %s: %v", template, err)
+		}
+		conf.Pods = append(conf.Pods, pod)
+	}
+	// the pods are taken from the world instead of the cluster so that a snapshot could be used
+	for _, key := range run.PodsFromCluster {
+		pod := findPod(objs, key)
+		if pod == nil {
+			return nil, fmt.Errorf("pod %s not found in the world", key)
+		}
+		conf.Pods = append(conf.Pods, pod.DeepCopy())
+	}
+
+	return capacityestimation.NewCESimulatorExecutor(conf)
+}
+
+func newClusterCompressionSimulator(run *ClusterCompressionRun, schedulerConfig string, objs []runtime.Object) (pkg.Simulator, error) {
+	opt := ccoptions.NewClusterCompressionOptions()
+	opt.SchedulerConfig = schedulerConfig
+	opt.MaxLimit = run.MaxLimit
+	opt.ExcludeNodes = run.ExcludeNodes
+	// the defaults are the same as the ones of the flags
+	opt.FilterNodeOptions = ccoptions.FilterNodeOptions{
+		ExcludeNotReadyNode: boolOrDefault(run.ExcludeNotReadyNode, true),
+		ExcludeTaintNode:    boolOrDefault(run.ExcludeTaintNode, true),
+		IgnoreStaticPod:     boolOrDefault(run.IgnoreStaticPod, true),
+		IgnoreMirrorPod:     boolOrDefault(run.IgnoreMirrorPod, false),
+		IgnoreCloneSet:      boolOrDefault(run.IgnoreCloneSet, false),
+		IgnoreVolumePod:     boolOrDefault(run.IgnoreVolumePod, false),
+	}
+	conf := ccoptions.NewClusterCompressionConfig(opt)
+	conf.InitObjs = objs
+
+	return clustercompression.NewCCSimulatorExecutor(conf)
+}
+
+func newSchedulerSimulationSimulator(run *SchedulerSimulationRun, schedulerConfig string, objs []runtime.Object) (pkg.Simulator, error) {
+	opt := ssoptions.NewSchedulerSimulationOptions()
+	opt.SchedulerConfig = schedulerConfig
+	opt.ExitCondition = run.ExitCondition
+	if len(opt.ExitCondition) == 0 {
+		opt.ExitCondition = ssoptions.ExitWhenAllSucceed
+	}
+	opt.ExcludeNodes = run.ExcludeNodes
+	opt.IgnorePodsOnExcludeNodes = boolOrDefault(run.IgnorePodsOnExcludeNodes, true)
+	opt.ReplayOrder = run.ReplayOrder
+	opt.ReplaySeed = run.ReplaySeed
+	if run.Timeout != nil {
+		opt.Timeout = run.Timeout.Duration
+	}
+	conf := ssoptions.NewSchedulerSimulationConfig(opt)
+	conf.InitObjs = objs
+
+	return schedulersimulation.NewSSSimulatorExecutor(conf)
+}
+
+func newWhatIfSimulator(steps []whatifoptions.Step, schedulerConfig string, objs []runtime.Object) (pkg.Simulator, error) {
+	opt := whatifoptions.NewWhatIfOptions()
+	opt.SchedulerConfig = schedulerConfig
+	conf := whatifoptions.NewWhatIfConfig(opt)
+	conf.Steps = steps
+	conf.InitObjs = objs
+
+	return whatif.NewWhatIfSimulatorExecutor(conf)
+}
+
+// findPod returns the pod of the key namespace/name, the namespace is default if it's omitted
+func findPod(objs []runtime.Object, key string) *corev1.Pod {
+	namespace, name := corev1.NamespaceDefault, key
+	if i := strings.Index(key, "/"); i >= 0 {
+		namespace, name = key[:i], key[i+1:]
+	}
+
+	for _, obj := range objs {
+		if pod, ok := obj.(*corev1.Pod); ok && pod.Namespace == namespace && pod.Name == name {
+			return pod
+		}
+	}
+
+	return nil
+}
+
+func boolOrDefault(b *bool, defaultValue bool) bool {
+	if b == nil {
+		return defaultValue
+	}
+	return *b
+}
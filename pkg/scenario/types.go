@@ -0,0 +1,284 @@
+package scenario
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds"
+	ssoptions "github.com/k-cloud-labs/kluster-capacity/app/cmds/schedulersimulation/options"
+	whatifoptions "github.com/k-cloud-labs/kluster-capacity/app/cmds/whatif/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/history"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/plugins/replayorder"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/usage"
+)
+
+const (
+	APIVersion = "capacity.k-cloud-labs.io/v1alpha1"
+	Kind       = "Scenario"
+)
+
+// Scenario describes a set of runs sharing the same world, so that a capacity review could be reproduced
+// later. The relative paths in it are relative to the directory of the scenario file.
+type Scenario struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	World      World  `json:"world"`
+	// scheduler configuration used by the runs which don't specify their own, the default one if empty
+	SchedulerConfig string `json:"schedulerConfig,omitempty"`
+	// directory of the history store the results of the runs are appended to if specified
+	History string `json:"history,omitempty"`
+	Runs    []Run  `json:"runs"`
+}
+
+// World is where the objects used to init the world come from, the world is loaded once and shared by
+// all the runs. The in-cluster config is used if neither is specified.
+type World struct {
+	KubeConfig string `json:"kubeconfig,omitempty"`
+	// a JSON or YAML file of the objects, e.g. the output of kubectl get -o yaml
+	Snapshot string `json:"snapshot,omitempty"`
+}
+
+// Run is a named ce, cc, ss or whatif run, exactly one of them must be specified
+type Run struct {
+	Name string `json:"name"`
+	// used instead of the scheduler configuration of the scenario if specified
+	SchedulerConfig string                  `json:"schedulerConfig,omitempty"`
+	CE              *CapacityEstimationRun  `json:"ce,omitempty"`
+	CC              *ClusterCompressionRun  `json:"cc,omitempty"`
+	SS              *SchedulerSimulationRun `json:"ss,omitempty"`
+	// steps applied to the world one by one and the measurements between them
+	WhatIf []whatifoptions.Step `json:"whatif,omitempty"`
+	// the ce, cc or ss run is executed on the world right-sized to the usage as well if specified
+	Usage *Usage `json:"usage,omitempty"`
+}
+
+// Usage has the same fields as the usage flags of ce, cc and ss
+type Usage struct {
+	File       string   `json:"file"`
+	Percentile int      `json:"percentile,omitempty"`
+	Margin     *float64 `json:"margin,omitempty"`
+}
+
+// Options returns the options of the usage, the defaults are the same as the ones of the flags
+func (u *Usage) Options() *cmds.Options {
+	opt := &cmds.Options{
+		UsageFile:       u.File,
+		UsagePercentile: u.Percentile,
+		UsageMargin:     cmds.DefaultUsageMargin,
+	}
+	if opt.UsagePercentile == 0 {
+		opt.UsagePercentile = cmds.DefaultUsagePercentile
+	}
+	if u.Margin != nil {
+		opt.UsageMargin = *u.Margin
+	}
+
+	return opt
+}
+
+// CapacityEstimationRun has the same fields as the flags of ce
+type CapacityEstimationRun struct {
+	PodsFromTemplate []string `json:"podsFromTemplate,omitempty"`
+	// namespace/name of the pods of the world
+	PodsFromCluster  []string `json:"podsFromCluster,omitempty"`
+	MaxLimit         int      `json:"maxLimit,omitempty"`
+	ExcludeNodes     []string `json:"excludeNodes,omitempty"`
+	EnablePreemption bool     `json:"enablePreemption,omitempty"`
+	BatchSize        int      `json:"batchSize,omitempty"`
+	Parallelism      int      `json:"parallelism,omitempty"`
+}
+
+// ClusterCompressionRun has the same fields as the flags of cc, the filters default to the defaults of
+// the flags if not set
+type ClusterCompressionRun struct {
+	MaxLimit            int      `json:"maxLimit,omitempty"`
+	ExcludeNodes        []string `json:"excludeNodes,omitempty"`
+	ExcludeNotReadyNode *bool    `json:"excludeNotReadyNode,omitempty"`
+	ExcludeTaintNode    *bool    `json:"excludeTaintNode,omitempty"`
+	IgnoreStaticPod     *bool    `json:"ignoreStaticPod,omitempty"`
+	IgnoreMirrorPod     *bool    `json:"ignoreMirrorPod,omitempty"`
+	IgnoreCloneSet      *bool    `json:"ignoreCloneSet,omitempty"`
+	IgnoreVolumePod     *bool    `json:"ignoreVolumePod,omitempty"`
+}
+
+// SchedulerSimulationRun has the same fields as the flags of ss
+type SchedulerSimulationRun struct {
+	ExitCondition            string           `json:"exitCondition,omitempty"`
+	ExcludeNodes             []string         `json:"excludeNodes,omitempty"`
+	IgnorePodsOnExcludeNodes *bool            `json:"ignorePodsOnExcludeNodes,omitempty"`
+	ReplayOrder              string           `json:"replayOrder,omitempty"`
+	ReplaySeed               int64            `json:"replaySeed,omitempty"`
+	Timeout                  *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// Load reads the scenario from a JSON or YAML file and validates it, unknown fields are rejected so that a
+// misspelled option isn't ignored silently. The relative paths are resolved against the directory of the file.
+func Load(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err = yaml.ToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Scenario{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(s); err != nil {
+		return nil, fmt.Errorf("failed to decode the scenario: %v", err)
+	}
+
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	s.resolvePaths(filepath.Dir(path))
+
+	return s, nil
+}
+
+// Command returns the command of the run
+func (r *Run) Command() string {
+	switch {
+	case r.CE != nil:
+		return history.CommandCapacityEstimation
+	case r.CC != nil:
+		return history.CommandClusterCompression
+	case r.WhatIf != nil:
+		return history.CommandWhatIf
+	default:
+		return history.CommandSchedulerSimulation
+	}
+}
+
+func (s *Scenario) validate() error {
+	if s.APIVersion != APIVersion || s.Kind != Kind {
+		return fmt.Errorf("apiVersion and kind of the scenario must be %s and %s, got %q and %q", APIVersion, Kind, s.APIVersion, s.Kind)
+	}
+
+	if len(s.World.KubeConfig) > 0 && len(s.World.Snapshot) > 0 {
+		return errors.New("kubeconfig and snapshot of the world are exclusive")
+	}
+
+	if len(s.Runs) == 0 {
+		return errors.New("at least one run must be specified")
+	}
+
+	names := make(map[string]bool)
+	for i := range s.Runs {
+		run := &s.Runs[i]
+		if len(run.Name) == 0 {
+			return fmt.Errorf("name of run %d is missing", i+1)
+		}
+		if names[run.Name] {
+			return fmt.Errorf("run %q is duplicated", run.Name)
+		}
+		names[run.Name] = true
+
+		if err := run.validate(); err != nil {
+			return fmt.Errorf("invalid run %q: %v", run.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Run) validate() error {
+	count := 0
+	for _, specified := range []bool{r.CE != nil, r.CC != nil, r.SS != nil, r.WhatIf != nil} {
+		if specified {
+			count++
+		}
+	}
+	if count != 1 {
+		return errors.New("exactly one of ce, cc, ss and whatif must be specified")
+	}
+
+	if r.Usage != nil {
+		if r.WhatIf != nil {
+			return errors.New("usage is not supported by whatif")
+		}
+		if len(r.Usage.File) == 0 {
+			return errors.New("file of the usage is missing")
+		}
+		if err := usage.Validate(r.Usage.Options()); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case r.CE != nil:
+		if len(r.CE.PodsFromTemplate) == 0 && len(r.CE.PodsFromCluster) == 0 {
+			return errors.New("pod template file and pod from cluster both is missing")
+		}
+		if len(r.CE.PodsFromTemplate) != 0 && len(r.CE.PodsFromCluster) != 0 {
+			return errors.New("pod template file and pod from cluster is exclusive")
+		}
+		if r.CE.BatchSize < 0 {
+			return errors.New("batch size must be greater than 0")
+		}
+		if r.CE.Parallelism < 0 {
+			return errors.New("parallelism must be greater than 0")
+		}
+		if r.CE.BatchSize > 1 && r.CE.EnablePreemption {
+			return errors.New("batch size and enable preemption is exclusive")
+		}
+	case r.SS != nil:
+		if len(r.SS.ExitCondition) > 0 && r.SS.ExitCondition != ssoptions.ExitWhenAllSucceed && r.SS.ExitCondition != ssoptions.ExitWhenAllScheduled {
+			return errors.New("exit condition must be AllSucceed or AllScheduled")
+		}
+		if r.SS.Timeout != nil && r.SS.Timeout.Duration < 0 {
+			return errors.New("timeout must not be negative")
+		}
+		if len(r.SS.ReplayOrder) > 0 {
+			if _, err := replayorder.NewFactory(r.SS.ReplayOrder, r.SS.ReplaySeed); err != nil {
+				return fmt.Errorf("invalid replay order: %v", err)
+			}
+		}
+	case r.WhatIf != nil:
+		return whatifoptions.ValidateSteps(r.WhatIf)
+	}
+
+	return nil
+}
+
+func (s *Scenario) resolvePaths(dir string) {
+	resolve := func(path *string) {
+		// the pod templates could be urls
+		if len(*path) > 0 && !filepath.IsAbs(*path) && !strings.Contains(*path, "://") {
+			*path = filepath.Join(dir, *path)
+		}
+	}
+
+	resolve(&s.World.KubeConfig)
+	resolve(&s.World.Snapshot)
+	resolve(&s.SchedulerConfig)
+	resolve(&s.History)
+	for i := range s.Runs {
+		resolve(&s.Runs[i].SchedulerConfig)
+		if u := s.Runs[i].Usage; u != nil {
+			resolve(&u.File)
+		}
+		if ce := s.Runs[i].CE; ce != nil {
+			for j := range ce.PodsFromTemplate {
+				resolve(&ce.PodsFromTemplate[j])
+			}
+		}
+		for _, step := range s.Runs[i].WhatIf {
+			if step.Estimate != nil {
+				for j := range step.Estimate.PodsFromTemplate {
+					resolve(&step.Estimate.PodsFromTemplate[j])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+)
+
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "Queued"
+	JobRunning   JobStatus = "Running"
+	JobSucceeded JobStatus = "Succeeded"
+	JobFailed    JobStatus = "Failed"
+)
+
+const (
+	KindCapacityEstimation  = "ce"
+	KindClusterCompression  = "cc"
+	KindSchedulerSimulation = "ss"
+)
+
+// Job is a simulation submitted to the server, the review is only available when the job succeeded
+type Job struct {
+	ID         string     `json:"id"`
+	Kind       string     `json:"kind"`
+	Status     JobStatus  `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	// the same as the output of the command with -o json
+	Review pkg.Printer `json:"review,omitempty"`
+
+	// runs the simulation in a world initialized from the objects
+	run func(objs []runtime.Object) (pkg.Printer, error)
+	// closed when the job is finished
+	done chan struct{}
+}
+
+func newJob(kind string, run func(objs []runtime.Object) (pkg.Printer, error)) *Job {
+	return &Job{
+		ID:        uuid.NewV4().String(),
+		Kind:      kind,
+		Status:    JobQueued,
+		CreatedAt: time.Now(),
+		run:       run,
+		done:      make(chan struct{}),
+	}
+}
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	ceoptions "github.com/k-cloud-labs/kluster-capacity/app/cmds/capacityestimation/options"
+	ccoptions "github.com/k-cloud-labs/kluster-capacity/app/cmds/clustercompression/options"
+	ssoptions "github.com/k-cloud-labs/kluster-capacity/app/cmds/schedulersimulation/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/plugins/replayorder"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/capacityestimation"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/clustercompression"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/schedulersimulation"
+)
+
+// CapacityEstimationRequest is the body of a ce request, the fields are the same as the flags of ce
+type CapacityEstimationRequest struct {
+	// each pod is estimated separately
+	Pods             []corev1.Pod `json:"pods"`
+	MaxLimit         int          `json:"maxLimit,omitempty"`
+	ExcludeNodes     []string     `json:"excludeNodes,omitempty"`
+	EnablePreemption bool         `json:"enablePreemption,omitempty"`
+	BatchSize        int          `json:"batchSize,omitempty"`
+}
+
+// ClusterCompressionRequest is the body of a cc request, the fields are the same as the flags of cc
+type ClusterCompressionRequest struct {
+	MaxLimit            int      `json:"maxLimit,omitempty"`
+	ExcludeNodes        []string `json:"excludeNodes,omitempty"`
+	ExcludeNotReadyNode bool     `json:"excludeNotReadyNode"`
+	ExcludeTaintNode    bool     `json:"excludeTaintNode"`
+	IgnoreStaticPod     bool     `json:"ignoreStaticPod"`
+	IgnoreMirrorPod     bool     `json:"ignoreMirrorPod"`
+	IgnoreCloneSet      bool     `json:"ignoreCloneSet"`
+	IgnoreVolumePod     bool     `json:"ignoreVolumePod"`
+}
+
+// SchedulerSimulationRequest is the body of a ss request, the fields are the same as the flags of ss
+type SchedulerSimulationRequest struct {
+	ExitCondition            string          `json:"exitCondition"`
+	ExcludeNodes             []string        `json:"excludeNodes,omitempty"`
+	IgnorePodsOnExcludeNodes bool            `json:"ignorePodsOnExcludeNodes"`
+	ReplayOrder              string          `json:"replayOrder,omitempty"`
+	ReplaySeed               int64           `json:"replaySeed,omitempty"`
+	Timeout                  metav1.Duration `json:"timeout,omitempty"`
+}
+
+// the defaults are the same as the ones of the flags
+func newClusterCompressionRequest() *ClusterCompressionRequest {
+	return &ClusterCompressionRequest{
+		ExcludeNotReadyNode: true,
+		ExcludeTaintNode:    true,
+		IgnoreStaticPod:     true,
+	}
+}
+
+func newSchedulerSimulationRequest() *SchedulerSimulationRequest {
+	return &SchedulerSimulationRequest{
+		ExitCondition:            ssoptions.ExitWhenAllSucceed,
+		IgnorePodsOnExcludeNodes: true,
+	}
+}
+
+func (s *Server) newCapacityEstimationJob(req *CapacityEstimationRequest) (*Job, error) {
+	if len(req.Pods) == 0 {
+		return nil, errors.New("at least one pod must be specified")
+	}
+	if req.BatchSize == 0 {
+		req.BatchSize = 1
+	}
+	if req.BatchSize < 0 {
+		return nil, errors.New("batch size must be greater than 0")
+	}
+	if req.BatchSize > 1 && req.EnablePreemption {
+		return nil, errors.New("batch size and enable preemption is exclusive")
+	}
+
+	opt := ceoptions.NewCapacityEstimationOptions()
+	opt.SchedulerConfig = s.conf.Options.SchedulerConfig
+	opt.MaxLimit = req.MaxLimit
+	opt.ExcludeNodes = req.ExcludeNodes
+	opt.EnablePreemption = req.EnablePreemption
+	opt.BatchSize = req.BatchSize
+	conf := ceoptions.NewCapacityEstimationConfig(opt)
+	for i := range req.Pods {
+		conf.Pods = append(conf.Pods, &req.Pods[i])
+	}
+
+	return newJob(KindCapacityEstimation, func(objs []runtime.Object) (pkg.Printer, error) {
+		conf.InitObjs = objs
+		sim, err := capacityestimation.NewCESimulatorExecutor(conf)
+		if err != nil {
+			return nil, err
+		}
+		return simulator.Run(sim, objs)
+	}), nil
+}
+
+func (s *Server) newClusterCompressionJob(req *ClusterCompressionRequest) (*Job, error) {
+	opt := ccoptions.NewClusterCompressionOptions()
+	opt.SchedulerConfig = s.conf.Options.SchedulerConfig
+	opt.MaxLimit = req.MaxLimit
+	opt.ExcludeNodes = req.ExcludeNodes
+	opt.FilterNodeOptions = ccoptions.FilterNodeOptions{
+		ExcludeNotReadyNode: req.ExcludeNotReadyNode,
+		ExcludeTaintNode:    req.ExcludeTaintNode,
+		IgnoreStaticPod:     req.IgnoreStaticPod,
+		IgnoreMirrorPod:     req.IgnoreMirrorPod,
+		IgnoreCloneSet:      req.IgnoreCloneSet,
+		IgnoreVolumePod:     req.IgnoreVolumePod,
+	}
+	conf := ccoptions.NewClusterCompressionConfig(opt)
+
+	return newJob(KindClusterCompression, func(objs []runtime.Object) (pkg.Printer, error) {
+		conf.InitObjs = objs
+		sim, err := clustercompression.NewCCSimulatorExecutor(conf)
+		if err != nil {
+			return nil, err
+		}
+		return simulator.Run(sim, objs)
+	}), nil
+}
+
+func (s *Server) newSchedulerSimulationJob(req *SchedulerSimulationRequest) (*Job, error) {
+	if req.ExitCondition != ssoptions.ExitWhenAllSucceed && req.ExitCondition != ssoptions.ExitWhenAllScheduled {
+		return nil, errors.New("exit condition must be AllSucceed or AllScheduled")
+	}
+	if req.Timeout.Duration < 0 {
+		return nil, errors.New("timeout must not be negative")
+	}
+	if len(req.ReplayOrder) > 0 {
+		if _, err := replayorder.NewFactory(req.ReplayOrder, req.ReplaySeed); err != nil {
+			return nil, fmt.Errorf("invalid replay order: %v", err)
+		}
+	}
+
+	opt := ssoptions.NewSchedulerSimulationOptions()
+	opt.SchedulerConfig = s.conf.Options.SchedulerConfig
+	opt.ExitCondition = req.ExitCondition
+	opt.ExcludeNodes = req.ExcludeNodes
+	opt.IgnorePodsOnExcludeNodes = req.IgnorePodsOnExcludeNodes
+	opt.ReplayOrder = req.ReplayOrder
+	opt.ReplaySeed = req.ReplaySeed
+	opt.Timeout = req.Timeout.Duration
+	conf := ssoptions.NewSchedulerSimulationConfig(opt)
+
+	return newJob(KindSchedulerSimulation, func(objs []runtime.Object) (pkg.Printer, error) {
+		conf.InitObjs = objs
+		sim, err := schedulersimulation.NewSSSimulatorExecutor(conf)
+		if err != nil {
+			return nil, err
+		}
+		return simulator.Run(sim, objs)
+	}), nil
+}
@@ -0,0 +1,247 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/serve/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/framework"
+)
+
+const (
+	apiPrefix  = "/api/v1/"
+	jobsPrefix = apiPrefix + "jobs/"
+)
+
+// Server runs the simulations submitted by http requests, each simulation gets a fresh world initialized
+// from the objects of the source so the cluster is never listed again.
+type Server struct {
+	conf   *options.ServeConfig
+	source framework.ObjectSource
+	queue  chan *Job
+
+	lock sync.RWMutex
+	jobs map[string]*Job
+}
+
+func NewServer(conf *options.ServeConfig, source framework.ObjectSource) *Server {
+	return &Server{
+		conf:   conf,
+		source: source,
+		queue:  make(chan *Job, conf.Options.MaxQueue),
+		jobs:   make(map[string]*Job),
+	}
+}
+
+// Run serves the http requests until the context is done
+func (s *Server) Run(ctx context.Context) error {
+	for i := 0; i < s.conf.Options.Concurrency; i++ {
+		go s.work(ctx)
+	}
+	go wait.Until(s.removeExpiredJobs, time.Minute, ctx.Done())
+
+	server := &http.Server{
+		Addr:    s.conf.Options.Address,
+		Handler: s.Handler(),
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	klog.InfoS("Serving simulations", "address", s.conf.Options.Address)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	mux.HandleFunc(apiPrefix+KindCapacityEstimation, func(w http.ResponseWriter, r *http.Request) {
+		req := &CapacityEstimationRequest{}
+		s.submit(w, r, req, func() (*Job, error) {
+			return s.newCapacityEstimationJob(req)
+		})
+	})
+	mux.HandleFunc(apiPrefix+KindClusterCompression, func(w http.ResponseWriter, r *http.Request) {
+		req := newClusterCompressionRequest()
+		s.submit(w, r, req, func() (*Job, error) {
+			return s.newClusterCompressionJob(req)
+		})
+	})
+	mux.HandleFunc(apiPrefix+KindSchedulerSimulation, func(w http.ResponseWriter, r *http.Request) {
+		req := newSchedulerSimulationRequest()
+		s.submit(w, r, req, func() (*Job, error) {
+			return s.newSchedulerSimulationJob(req)
+		})
+	})
+	mux.HandleFunc(jobsPrefix, s.getJob)
+
+	return mux
+}
+
+// submit decodes the request into req and queues the job created by newJob. The job is returned at once
+// unless the query parameter wait is true, in which case the response is sent when the job is finished.
+func (s *Server) submit(w http.ResponseWriter, r *http.Request, req interface{}, newJob func() (*Job, error)) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	// an empty body means the default request
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("failed to decode the request: %v", err))
+			return
+		}
+	}
+
+	job, err := newJob()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.lock.Lock()
+	select {
+	case s.queue <- job:
+		s.jobs[job.ID] = job
+		s.lock.Unlock()
+	default:
+		s.lock.Unlock()
+		writeError(w, http.StatusTooManyRequests, errors.New("too many simulations are waiting, try again later"))
+		return
+	}
+
+	w.Header().Set("Location", jobsPrefix+job.ID)
+	if r.URL.Query().Get("wait") != "true" {
+		s.writeJob(w, http.StatusAccepted, job)
+		return
+	}
+
+	select {
+	case <-job.done:
+		s.writeJob(w, http.StatusOK, job)
+	case <-r.Context().Done():
+	}
+}
+
+func (s *Server) getJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, jobsPrefix)
+	s.lock.RLock()
+	job, ok := s.jobs[id]
+	s.lock.RUnlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("job %q not found", id))
+		return
+	}
+
+	s.writeJob(w, http.StatusOK, job)
+}
+
+// work runs the queued jobs one by one
+func (s *Server) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-s.queue:
+			s.runJob(job)
+		}
+	}
+}
+
+func (s *Server) runJob(job *Job) {
+	now := time.Now()
+	s.lock.Lock()
+	job.Status = JobRunning
+	job.StartedAt = &now
+	s.lock.Unlock()
+
+	review, err := s.runSafely(job)
+
+	now = time.Now()
+	s.lock.Lock()
+	job.FinishedAt = &now
+	if err != nil {
+		job.Status = JobFailed
+		job.Error = err.Error()
+		klog.ErrorS(err, "Simulation failed", "job", job.ID, "kind", job.Kind)
+	} else {
+		job.Status = JobSucceeded
+		job.Review = review
+	}
+	s.lock.Unlock()
+	close(job.done)
+}
+
+// runSafely keeps the server running when a simulation panics
+func (s *Server) runSafely(job *Job) (review pkg.Printer, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("simulation panicked: %v", r)
+		}
+	}()
+
+	objs, err := s.source.Objects()
+	if err != nil {
+		return nil, err
+	}
+
+	return job.run(objs)
+}
+
+// removeExpiredJobs removes the jobs finished before the ttl
+func (s *Server) removeExpiredJobs() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for id, job := range s.jobs {
+		if job.FinishedAt != nil && time.Since(*job.FinishedAt) > s.conf.Options.JobTTL {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+func (s *Server) writeJob(w http.ResponseWriter, code int, job *Job) {
+	s.lock.RLock()
+	data, err := json.Marshal(job)
+	s.lock.RUnlock()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(data)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	data, _ := json.Marshal(map[string]string{"error": err.Error()})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(data)
+}
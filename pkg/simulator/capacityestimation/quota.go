@@ -0,0 +1,107 @@
+package capacityestimation
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	quota "k8s.io/apiserver/pkg/quota/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/kubernetes/pkg/quota/v1/evaluator/core"
+	"k8s.io/utils/clock"
+)
+
+const QuotaExceeded = "QuotaExceeded"
+
+// quotaLimit is the max number of replicas the resource quotas of the namespace allow
+type quotaLimit struct {
+	replicas     int
+	quotaName    string
+	resourceName corev1.ResourceName
+	message      string
+}
+
+// getQuotaLimit returns the quota limit of the pod, nil means no resource quota matches the pod.
+func getQuotaLimit(client clientset.Interface, pod *corev1.Pod) (*quotaLimit, error) {
+	quotaList, err := client.CoreV1().ResourceQuotas(pod.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if len(quotaList.Items) == 0 {
+		return nil, nil
+	}
+
+	podList, err := client.CoreV1().Pods(pod.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	evaluator := core.NewPodEvaluator(nil, clock.RealClock{})
+	podUsage, err := evaluator.Usage(pod)
+	if err != nil {
+		return nil, err
+	}
+
+	var limit *quotaLimit
+	for i := range quotaList.Items {
+		resourceQuota := &quotaList.Items[i]
+		matched, err := evaluator.Matches(resourceQuota, pod)
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			continue
+		}
+
+		hardResources := quota.ResourceNames(resourceQuota.Spec.Hard)
+		if err := evaluator.Constraints(hardResources, pod); err != nil {
+			return &quotaLimit{
+				replicas:  0,
+				quotaName: resourceQuota.Name,
+				message:   fmt.Sprintf("quota %s: %v", resourceQuota.Name, err),
+			}, nil
+		}
+
+		used := corev1.ResourceList{}
+		for j := range podList.Items {
+			if matched, err := evaluator.Matches(resourceQuota, &podList.Items[j]); err != nil || !matched {
+				continue
+			}
+			usage, err := evaluator.Usage(&podList.Items[j])
+			if err != nil {
+				return nil, err
+			}
+			used = quota.Add(used, usage)
+		}
+
+		for _, resourceName := range evaluator.MatchingResources(hardResources) {
+			request, ok := podUsage[resourceName]
+			if !ok || request.IsZero() {
+				continue
+			}
+
+			hard := resourceQuota.Spec.Hard[resourceName]
+			usedQuantity := used[resourceName]
+			remaining := hard.DeepCopy()
+			remaining.Sub(usedQuantity)
+			replicas := 0
+			if remaining.Sign() > 0 {
+				replicas = int(math.Floor(float64(remaining.MilliValue()) / float64(request.MilliValue())))
+			}
+
+			if limit == nil || replicas < limit.replicas {
+				limit = &quotaLimit{
+					replicas:     replicas,
+					quotaName:    resourceQuota.Name,
+					resourceName: resourceName,
+					message: fmt.Sprintf("exceeded quota: %s, requested: %s=%s, used: %s=%s, limited: %s=%s", resourceQuota.Name,
+						resourceName, request.String(), resourceName, usedQuantity.String(), resourceName, hard.String()),
+				}
+			}
+		}
+	}
+
+	return limit, nil
+}
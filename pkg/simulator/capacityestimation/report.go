@@ -3,11 +3,13 @@ package capacityestimation
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/jedib0t/go-pretty/v6/table"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
@@ -37,6 +39,42 @@ type CapacityEstimationReviewStatus struct {
 	StopReason *CapacityEstimationReviewScheduleStopReason `json:"stopReason"`
 	// per node information about the scheduling simulation
 	Pods []*CapacityEstimationReviewResult `json:"pods"`
+	// only available when the namespace of the pod has resource quotas
+	Quota *CapacityEstimationReviewQuota `json:"quota,omitempty"`
+	// only available when preemption is enabled
+	Preemption *CapacityEstimationReviewPreemption `json:"preemption,omitempty"`
+	// requested resources of the nodes with the replicas scheduled
+	Nodes []utils.NodeUtilization `json:"nodes,omitempty"`
+}
+
+type CapacityEstimationReviewQuota struct {
+	// number of replicas the nodes could hold, the nodes could hold at least as many as the quota-bound replicas
+	// when the estimation stops with QuotaExceeded
+	NodeBoundReplicas int32 `json:"nodeBoundReplicas"`
+	// number of replicas the resource quotas of the namespace allow
+	QuotaBoundReplicas int32 `json:"quotaBoundReplicas"`
+	// the quota and resource which limit the replicas most
+	QuotaName    string              `json:"quotaName"`
+	ResourceName corev1.ResourceName `json:"resourceName"`
+}
+
+type CapacityEstimationReviewPreemption struct {
+	// number of replicas that could schedule without evicting any pod
+	ReplicasWithoutPreemption int32 `json:"replicasWithoutPreemption"`
+	// number of replicas that could schedule by evicting pods with lower priority
+	ReplicasWithPreemption int32 `json:"replicasWithPreemption"`
+	// evicted pods grouped by owner and priority class
+	Victims []*PreemptionVictims `json:"victims"`
+}
+
+type PreemptionVictims struct {
+	Namespace         string   `json:"namespace"`
+	OwnerKind         string   `json:"ownerKind"`
+	OwnerName         string   `json:"ownerName"`
+	PriorityClassName string   `json:"priorityClassName"`
+	Priority          int32    `json:"priority"`
+	Count             int      `json:"count"`
+	Pods              []string `json:"pods"`
 }
 
 type CapacityEstimationReviewResult struct {
@@ -79,6 +117,8 @@ func (r *CapacityEstimationReview) Print(verbose bool, format string) error {
 		return utils.PrintJson(r)
 	case "yaml":
 		return utils.PrintYaml(r)
+	case "html":
+		return utils.PrintHTML(htmlPage(CapacityEstimationReviews{r}))
 	case "":
 		capacityEstimationReviewPrettyPrint(r, verbose)
 		return nil
@@ -88,8 +128,18 @@ func (r *CapacityEstimationReview) Print(verbose bool, format string) error {
 }
 
 func (r CapacityEstimationReviews) Print(verbose bool, format string) error {
+	// all the templates are shown in one page
+	if format == "html" {
+		return utils.PrintHTML(htmlPage(r))
+	}
+
+	withPreemption := len(r) > 0 && r[0].Status.Preemption != nil
 	t := table.NewWriter()
-	t.AppendHeader(table.Row{"spec", "replicas"})
+	if withPreemption {
+		t.AppendHeader(table.Row{"spec", "replicas", "replicas without preemption"})
+	} else {
+		t.AppendHeader(table.Row{"spec", "replicas"})
+	}
 	for i, review := range r {
 		if i > 0 && (format != "" || verbose) {
 			fmt.Println("---------------------------------------------------------------")
@@ -113,7 +163,11 @@ func (r CapacityEstimationReviews) Print(verbose bool, format string) error {
 				if err != nil {
 					return err
 				}
-				t.AppendRow(table.Row{string(output), review.Status.Replicas})
+				if withPreemption {
+					t.AppendRow(table.Row{string(output), review.Status.Replicas, review.Status.Preemption.ReplicasWithoutPreemption})
+				} else {
+					t.AppendRow(table.Row{string(output), review.Status.Replicas})
+				}
 			}
 		default:
 			return fmt.Errorf("output format %q not recognized", format)
@@ -196,7 +250,52 @@ func getReviewStatus(pods []*corev1.Pod, status pkg.Status) CapacityEstimationRe
 		Replicas:          int32(len(status.PodsForEstimation)),
 		StopReason:        getMainStopReason(status.StopReason),
 		Pods:              parsePodsReview(pods, status),
+		Nodes:             utils.GetNodeUtilizations(status.Nodes, status.Pods),
+	}
+}
+
+func getPreemptionReview(replicas int32, replicasWithoutPreemption int, preemptedPods []*corev1.Pod) *CapacityEstimationReviewPreemption {
+	result := &CapacityEstimationReviewPreemption{
+		ReplicasWithoutPreemption: replicas,
+		ReplicasWithPreemption:    replicas,
+		Victims:                   make([]*PreemptionVictims, 0),
+	}
+	if replicasWithoutPreemption >= 0 {
+		result.ReplicasWithoutPreemption = int32(replicasWithoutPreemption)
 	}
+
+	victimsMap := make(map[string]*PreemptionVictims)
+	for _, pod := range preemptedPods {
+		victims := &PreemptionVictims{
+			Namespace:         pod.Namespace,
+			OwnerKind:         "Pod",
+			OwnerName:         pod.Name,
+			PriorityClassName: pod.Spec.PriorityClassName,
+		}
+		if pod.Spec.Priority != nil {
+			victims.Priority = *pod.Spec.Priority
+		}
+		if owner := metav1.GetControllerOf(pod); owner != nil {
+			victims.OwnerKind = owner.Kind
+			victims.OwnerName = owner.Name
+		}
+
+		key := strings.Join([]string{victims.Namespace, victims.OwnerKind, victims.OwnerName, victims.PriorityClassName}, "/")
+		if existing, ok := victimsMap[key]; ok {
+			victims = existing
+		} else {
+			victimsMap[key] = victims
+			result.Victims = append(result.Victims, victims)
+		}
+		victims.Count++
+		victims.Pods = append(victims.Pods, pod.Name)
+	}
+
+	sort.SliceStable(result.Victims, func(i, j int) bool {
+		return result.Victims[i].Count > result.Victims[j].Count
+	})
+
+	return result
 }
 
 func deepCopyPods(in []*corev1.Pod, out []corev1.Pod) {
@@ -255,6 +354,14 @@ func capacityEstimationReviewPrettyPrint(r *CapacityEstimationReview, verbose bo
 		fmt.Printf("\nTermination reason: %v: %v\n", r.Status.StopReason.StopType, r.Status.StopReason.StopMessage)
 	}
 
+	if verbose && r.Status.Quota != nil {
+		fmt.Printf("\n%s\n", quotaMessage(r))
+	}
+
+	if verbose && r.Status.Preemption != nil {
+		preemptionPrettyPrint(r.Status.Preemption)
+	}
+
 	if verbose && r.Status.Replicas > 0 {
 		for _, pod := range r.Status.Pods {
 			if pod.Summary != nil {
@@ -274,3 +381,83 @@ func capacityEstimationReviewPrettyPrint(r *CapacityEstimationReview, verbose bo
 		}
 	}
 }
+
+func preemptionPrettyPrint(p *CapacityEstimationReviewPreemption) {
+	fmt.Printf("\nThe cluster can schedule %v instance(s) without preemption and %v instance(s) with preemption.\n",
+		p.ReplicasWithoutPreemption, p.ReplicasWithPreemption)
+	if len(p.Victims) == 0 {
+		return
+	}
+
+	fmt.Printf("\nPreempted pods:\n")
+	for _, victims := range p.Victims {
+		priorityClassName := victims.PriorityClassName
+		if len(priorityClassName) == 0 {
+			priorityClassName = "<none>"
+		}
+		fmt.Printf("\t- %v %v/%v, priority class: %v(%v): %v pod(s)\n", victims.OwnerKind, victims.Namespace, victims.OwnerName,
+			priorityClassName, victims.Priority, victims.Count)
+	}
+}
+
+func htmlPage(r CapacityEstimationReviews) *utils.HTMLPage {
+	page := &utils.HTMLPage{Title: "Capacity Estimation"}
+
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"template", "cpu(m)", "memory", "replicas", "termination reason"})
+	for _, review := range r {
+		req := review.Spec.PodRequirements[0]
+		t.AppendRow(table.Row{req.PodName, req.Resources.MilliCPU, resource.NewQuantity(req.Resources.Memory, resource.BinarySI).String(),
+			review.Status.Replicas, fmt.Sprintf("%v: %v", review.Status.StopReason.StopType, review.Status.StopReason.StopMessage)})
+	}
+	page.Sections = append(page.Sections, utils.HTMLSection{Title: "Summary", Table: t})
+
+	for _, review := range r {
+		name := review.Spec.PodRequirements[0].PodName
+		section := utils.HTMLSection{Title: fmt.Sprintf("Template %s", name)}
+		section.Paragraphs = append(section.Paragraphs, fmt.Sprintf("The cluster can schedule %d instance(s) of the pod %s.", review.Status.Replicas, name))
+		if review.Status.Quota != nil {
+			section.Paragraphs = append(section.Paragraphs, quotaMessage(review))
+		}
+		if review.Status.Preemption != nil {
+			section.Paragraphs = append(section.Paragraphs, fmt.Sprintf("The cluster can schedule %d instance(s) without preemption and %d instance(s) with preemption.",
+				review.Status.Preemption.ReplicasWithoutPreemption, review.Status.Preemption.ReplicasWithPreemption))
+		}
+		page.Sections = append(page.Sections, section)
+
+		zones := make(map[string]string, len(review.Status.Nodes))
+		for _, node := range review.Status.Nodes {
+			zones[node.Name] = node.Zone
+		}
+		t := table.NewWriter()
+		t.AppendHeader(table.Row{"node", "zone", "replicas"})
+		for _, pod := range review.Status.Pods {
+			for _, ron := range pod.ReplicasOnNodes {
+				t.AppendRow(table.Row{ron.NodeName, zones[ron.NodeName], ron.Replicas})
+			}
+		}
+		page.Sections = append(page.Sections, utils.HTMLSection{Title: fmt.Sprintf("Pod distribution of %s", name), Table: t})
+
+		if len(review.Status.Nodes) > 0 {
+			page.Sections = append(page.Sections, utils.HTMLSection{
+				Title: fmt.Sprintf("Node utilization with %s", name),
+				Table: utils.ZoneTable(review.Status.Nodes),
+				Bars:  review.Status.Nodes,
+			})
+		}
+	}
+
+	return page
+}
+
+// quotaMessage compares the replicas the nodes and the resource quotas allow, the nodes are not filled up when the
+// estimation stops with QuotaExceeded
+func quotaMessage(r *CapacityEstimationReview) string {
+	if r.Status.StopReason != nil && r.Status.StopReason.StopType == QuotaExceeded {
+		return fmt.Sprintf("The nodes can hold at least %d instance(s), the resource quota %s allows %d instance(s).",
+			r.Status.Quota.NodeBoundReplicas, r.Status.Quota.QuotaName, r.Status.Quota.QuotaBoundReplicas)
+	}
+
+	return fmt.Sprintf("The nodes can hold %d instance(s), the resource quota %s allows %d instance(s).",
+		r.Status.Quota.NodeBoundReplicas, r.Status.Quota.QuotaName, r.Status.Quota.QuotaBoundReplicas)
+}
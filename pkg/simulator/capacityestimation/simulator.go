@@ -1,18 +1,24 @@
 package capacityestimation
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"sync"
 
 	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/informers"
+	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	"github.com/k-cloud-labs/kluster-capacity/app/cmds/capacityestimation/options"
 	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/admission"
 	pkgframework "github.com/k-cloud-labs/kluster-capacity/pkg/framework"
 	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
 )
@@ -25,36 +31,78 @@ type PodGenerator interface {
 type simulator struct {
 	pkg.Framework
 
+	fakeClient   clientset.Interface
 	podGenerator PodGenerator
 	simulatedPod *corev1.Pod
 	maxSimulated int
 	simulated    int
+
+	withPreemption bool
+	// number of replicas scheduled before the first preemption happened, -1 means no preemption happened
+	replicasWithoutPreemption int
+	// nil means no resource quota limits the simulated pod
+	quotaLimit *quotaLimit
+
+	// number of simulated pods kept pending in the scheduling queue at a time
+	batchSize int
+	lock      sync.Mutex
+	// simulated pods not bound yet, the value is the index of the pod
+	pendingPods map[string]int
+	// simulated pods bound, the key is the index of the pod
+	boundPods map[int]*corev1.Pod
+	// index of the first unschedulable pod, -1 means all the simulated pods are schedulable so far
+	firstUnschedulable int
+	stopReason         string
 }
 
+// multiSimulator runs a simulator for each pod template, all the simulators share one world
 type multiSimulator struct {
-	simulators []*simulator
-	reports    pkg.Printer
+	pods         []*corev1.Pod
+	newSimulator func(pod *corev1.Pod, world *pkgframework.World) (*simulator, error)
+	newWorld     func(objs ...runtime.Object) (*pkgframework.World, error)
+	world        *pkgframework.World
+	// max number of simulators running at the same time
+	parallelism int
+	reports     pkg.Printer
+	// shared by all the simulators
+	tracer *pkgframework.Tracer
 }
 
 // NewCESimulatorExecutor create a ce simulator which is completely independent of apiserver so no need
 // for kubeconfig nor for apiserver url
 func NewCESimulatorExecutor(conf *options.CapacityEstimationConfig) (pkg.Simulator, error) {
-	newSimulator := func(pod *corev1.Pod) (*simulator, error) {
-		kubeSchedulerConfig, err := utils.BuildKubeSchedulerCompletedConfig(conf.Options.SchedulerConfig, conf.Options.KubeConfig)
-		if err != nil {
-			return nil, err
-		}
+	tracer, err := pkgframework.NewTracer(conf.Options.Trace)
+	if err != nil {
+		return nil, err
+	}
 
-		kubeConfig, err := utils.BuildRestConfig(conf.Options.KubeConfig)
+	chain, err := admission.New(&conf.Options.Options)
+	if err != nil {
+		_ = tracer.Close()
+		return nil, err
+	}
+
+	newSimulator := func(pod *corev1.Pod, world *pkgframework.World) (*simulator, error) {
+		kubeSchedulerConfig, err := utils.BuildKubeSchedulerCompletedConfig(conf.Options.SchedulerConfig, conf.Options.KubeConfig)
 		if err != nil {
 			return nil, err
 		}
 
 		s := &simulator{
-			podGenerator: NewSinglePodGenerator(pod),
-			simulatedPod: pod,
-			simulated:    0,
-			maxSimulated: conf.Options.MaxLimit,
+			fakeClient:                kubeSchedulerConfig.Client,
+			podGenerator:              NewSinglePodGenerator(pod),
+			simulatedPod:              pod,
+			simulated:                 0,
+			maxSimulated:              conf.Options.MaxLimit,
+			withPreemption:            conf.Options.EnablePreemption,
+			replicasWithoutPreemption: -1,
+			batchSize:                 conf.Options.BatchSize,
+			pendingPods:               make(map[string]int),
+			boundPods:                 make(map[int]*corev1.Pod),
+			firstUnschedulable:        -1,
+		}
+		if s.batchSize < 1 {
+			s.batchSize = 1
 		}
 
 		err = s.addEventHandlers(kubeSchedulerConfig.InformerFactory)
@@ -62,9 +110,15 @@ func NewCESimulatorExecutor(conf *options.CapacityEstimationConfig) (pkg.Simulat
 			return nil, err
 		}
 
-		framework, err := pkgframework.NewKubeSchedulerFramework(kubeSchedulerConfig, kubeConfig,
+		// the world is already loaded, so no rest config is needed
+		framework, err := pkgframework.NewKubeSchedulerFramework(kubeSchedulerConfig, nil,
+			pkgframework.WithWorld(world),
+			pkgframework.WithAdmission(chain),
 			pkgframework.WithExcludeNodes(conf.Options.ExcludeNodes),
-			pkgframework.WithPostBindHook(s.postBindHook))
+			pkgframework.WithPreemption(conf.Options.EnablePreemption),
+			pkgframework.WithPostBindHook(s.postBindHook),
+			pkgframework.WithSchedulingFailureHook(s.schedulingFailureHook),
+			pkgframework.WithTracer(tracer, ""))
 		if err != nil {
 			return nil, err
 		}
@@ -74,17 +128,28 @@ func NewCESimulatorExecutor(conf *options.CapacityEstimationConfig) (pkg.Simulat
 		return s, nil
 	}
 
-	ms := &multiSimulator{
-		simulators: make([]*simulator, 0),
-	}
+	newWorld := func(objs ...runtime.Object) (*pkgframework.World, error) {
+		if len(objs) > 0 {
+			return pkgframework.NewWorld(objs, pkgframework.WithExcludeNodes(conf.Options.ExcludeNodes))
+		}
 
-	for _, pod := range conf.Pods {
-		s, err := newSimulator(pod)
+		kubeConfig, err := utils.BuildRestConfig(conf.Options.KubeConfig)
 		if err != nil {
 			return nil, err
 		}
 
-		ms.simulators = append(ms.simulators, s)
+		return pkgframework.NewWorldFromCluster(kubeConfig, pkgframework.WithExcludeNodes(conf.Options.ExcludeNodes))
+	}
+
+	ms := &multiSimulator{
+		pods:         conf.Pods,
+		newSimulator: newSimulator,
+		newWorld:     newWorld,
+		parallelism:  conf.Options.Parallelism,
+		tracer:       tracer,
+	}
+	if ms.parallelism < 1 {
+		ms.parallelism = 1
 	}
 
 	return ms, nil
@@ -96,32 +161,109 @@ func (s *simulator) Initialize(objs ...runtime.Object) error {
 		return err
 	}
 
-	// create first pod
-	return s.createNextPod()
+	err = s.applyNamespaceConstraints()
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// the resource quotas don't allow any replica
+	if s.quotaLimit != nil && s.quotaLimit.replicas <= 0 {
+		return s.Stop(fmt.Sprintf("%s: %s", QuotaExceeded, s.quotaLimit.message))
+	}
+
+	// create the first batch of pods
+	for i := 0; i < s.batchSize && s.canCreateNextPod(); i++ {
+		if err := s.createNextPod(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyNamespaceConstraints admits the simulated pod, e.g. the defaults of LimitRanges are applied, and
+// calculates how many replicas the ResourceQuotas of the namespace allow.
+func (s *simulator) applyNamespaceConstraints() error {
+	pod := s.simulatedPod.DeepCopy()
+	if len(pod.Namespace) == 0 {
+		pod.Namespace = metav1.NamespaceDefault
+	}
+
+	pod, err := s.Admit(pod)
+	if err != nil {
+		return err
+	}
+
+	quotaLimit, err := getQuotaLimit(s.fakeClient, pod)
+	if err != nil {
+		return fmt.Errorf("unable to calculate quota limit of pod %s: %v", pod.Name, err)
+	}
+
+	s.simulatedPod = pod
+	s.podGenerator = NewSinglePodGenerator(pod)
+	s.quotaLimit = quotaLimit
+
+	return nil
 }
 
 func (s *simulator) Report() pkg.Printer {
-	return generateReport([]*corev1.Pod{s.simulatedPod}, s.Status())
+	status := s.Status()
+	status.PodsForEstimation = s.estimationPods()
+	review := generateReport([]*corev1.Pod{s.simulatedPod}, status)
+	if s.quotaLimit != nil {
+		review.Status.Quota = &CapacityEstimationReviewQuota{
+			// the nodes may hold more if the estimation is stopped by the quotas
+			NodeBoundReplicas:  int32(len(status.PodsForEstimation)),
+			QuotaBoundReplicas: int32(s.quotaLimit.replicas),
+			QuotaName:          s.quotaLimit.quotaName,
+			ResourceName:       s.quotaLimit.resourceName,
+		}
+	}
+	if s.withPreemption {
+		review.Status.Preemption = getPreemptionReview(review.Status.Replicas, s.replicasWithoutPreemption, s.Status().PreemptedPods)
+	}
+
+	return review
 }
 
+// Initialize loads the world only once, the simulators are created lazily in Run
 func (ms *multiSimulator) Initialize(objs ...runtime.Object) error {
-	for _, s := range ms.simulators {
-		if err := s.Initialize(objs...); err != nil {
-			return err
-		}
+	world, err := ms.newWorld(objs...)
+	if err != nil {
+		return err
 	}
 
+	ms.world = world
+
 	return nil
 }
 
 func (ms *multiSimulator) Run() error {
+	defer func() {
+		_ = ms.tracer.Close()
+	}()
+
 	g := errgroup.Group{}
-	reports := make(CapacityEstimationReviews, len(ms.simulators))
-	for i, s := range ms.simulators {
+	g.SetLimit(ms.parallelism)
+	reports := make(CapacityEstimationReviews, len(ms.pods))
+	for i, pod := range ms.pods {
 		i := i
-		s := s
+		pod := pod
 		g.Go(func() error {
-			err := s.Run()
+			s, err := ms.newSimulator(pod, ms.world)
+			if err != nil {
+				return err
+			}
+
+			err = s.Initialize()
+			if err != nil {
+				return err
+			}
+
+			err = s.Run()
 			if err != nil {
 				return err
 			}
@@ -148,46 +290,178 @@ func (s *simulator) postBindHook(bindPod *corev1.Pod) error {
 	if !metav1.HasAnnotation(bindPod.ObjectMeta, pkg.PodProvisioner) {
 		return nil
 	}
-	s.UpdateEstimationPods(bindPod)
 
-	if s.maxSimulated > 0 && s.simulated >= s.maxSimulated {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// the pod has been cancelled
+	if _, ok := s.pendingPods[bindPod.Name]; !ok {
+		return nil
+	}
+	s.boundPods[s.pendingPods[bindPod.Name]] = bindPod
+	delete(s.pendingPods, bindPod.Name)
+
+	if s.maxSimulated > 0 && len(s.boundPods) >= s.maxSimulated {
 		return s.Stop(fmt.Sprintf("LimitReached: Maximum number of pods simulated: %v", s.maxSimulated))
 	}
 
+	// the next replica would exceed the resource quotas
+	if s.quotaLimit != nil && len(s.boundPods) >= s.quotaLimit.replicas {
+		return s.Stop(fmt.Sprintf("%s: %s", QuotaExceeded, s.quotaLimit.message))
+	}
+
+	// an unschedulable pod has been found, wait for the pods created before it
+	if s.firstUnschedulable >= 0 {
+		return s.stopIfSettled()
+	}
+
+	// all the pods have been created, wait for them to be bound
+	if !s.canCreateNextPod() {
+		return nil
+	}
+
 	if err := s.createNextPod(); err != nil {
 		return fmt.Errorf("unable to create next pod for simulated scheduling: %v", err)
 	}
 	return nil
 }
 
+// onUnschedulable cancels all the pods created after the unschedulable pod, including the ones already bound,
+// and stops the simulation once all the pods created before it are bound.
+func (s *simulator) onUnschedulable(pod *corev1.Pod, reason string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	index, ok := s.pendingPods[pod.Name]
+	if !ok || (s.firstUnschedulable >= 0 && index >= s.firstUnschedulable) {
+		return nil
+	}
+	s.firstUnschedulable = index
+	s.stopReason = reason
+
+	for name, i := range s.pendingPods {
+		if i <= index {
+			continue
+		}
+		klog.V(2).InfoS("cancel simulate pod", "key", pod.Namespace+"/"+name)
+		if err := s.fakeClient.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil {
+			return s.Stop("FailedDeletePod: " + err.Error())
+		}
+		delete(s.pendingPods, name)
+	}
+	for i, boundPod := range s.boundPods {
+		if i <= index {
+			continue
+		}
+		klog.V(2).InfoS("cancel bound simulate pod", "key", boundPod.Namespace+"/"+boundPod.Name)
+		if err := s.fakeClient.CoreV1().Pods(boundPod.Namespace).Delete(context.TODO(), boundPod.Name, metav1.DeleteOptions{}); err != nil {
+			return s.Stop("FailedDeletePod: " + err.Error())
+		}
+		delete(s.boundPods, i)
+	}
+
+	return s.stopIfSettled()
+}
+
+// stopIfSettled stops the simulation once all the pods created before the unschedulable one are bound,
+// it must be called with lock held
+func (s *simulator) stopIfSettled() error {
+	bound := 0
+	for i := range s.boundPods {
+		if i < s.firstUnschedulable {
+			bound++
+		}
+	}
+	if bound != s.firstUnschedulable {
+		return nil
+	}
+
+	return s.Stop(s.stopReason)
+}
+
+// estimationPods returns the simulated pods bound in the order they are created
+func (s *simulator) estimationPods() []*corev1.Pod {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	indexes := make([]int, 0, len(s.boundPods))
+	for i := range s.boundPods {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	pods := make([]*corev1.Pod, 0, len(indexes))
+	for _, i := range indexes {
+		pods = append(pods, s.boundPods[i])
+	}
+	return pods
+}
+
+// canCreateNextPod returns false if the max limit or the resource quotas don't allow more pods, it must be called
+// with lock held
+func (s *simulator) canCreateNextPod() bool {
+	return (s.maxSimulated <= 0 || s.simulated < s.maxSimulated) && (s.quotaLimit == nil || s.simulated < s.quotaLimit.replicas)
+}
+
+// createNextPod must be called with lock held
 func (s *simulator) createNextPod() error {
 	pod := s.podGenerator.Generate()
+	s.pendingPods[pod.Name] = s.simulated
 	s.simulated++
 	klog.V(2).InfoS("create simulate pod", "count", s.simulated, "key", pod.Namespace+"/"+pod.Name)
 
 	return s.CreatePod(pod)
 }
 
+// schedulingFailureHook handles the pods provisioned by ce which are unschedulable
+func (s *simulator) schedulingFailureHook(pod *corev1.Pod, reason string, err error, nominatingInfo *framework.NominatingInfo) {
+	if !metav1.HasAnnotation(pod.ObjectMeta, pkg.PodProvisioner) || reason != corev1.PodReasonUnschedulable {
+		return
+	}
+
+	// the pod has just nominated a node by evicting lower priority pods in this attempt, it will be scheduled again
+	if s.withPreemption && isNominated(nominatingInfo) {
+		klog.V(2).InfoS("simulate pod preempts pods", "key", pod.Namespace+"/"+pod.Name, "node", nominatingInfo.NominatedNodeName)
+		return
+	}
+
+	if err := s.onUnschedulable(pod, fmt.Sprintf("%v: %v", reason, err)); err != nil {
+		klog.ErrorS(err, "Failed to handle unschedulable simulate pod", "key", pod.Namespace+"/"+pod.Name)
+	}
+}
+
 func (s *simulator) addEventHandlers(informerFactory informers.SharedInformerFactory) (err error) {
+	if !s.withPreemption {
+		return
+	}
+
+	// pods deleted by the scheduler are the victims of preemption
 	_, _ = informerFactory.Core().V1().Pods().Informer().AddEventHandler(
 		cache.FilteringResourceEventHandler{
 			FilterFunc: func(obj interface{}) bool {
-				if pod, ok := obj.(*corev1.Pod); ok && pod.Spec.SchedulerName == pkg.SchedulerName &&
-					metav1.HasAnnotation(pod.ObjectMeta, pkg.PodProvisioner) {
+				if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					obj = tombstone.Obj
+				}
+				if pod, ok := obj.(*corev1.Pod); ok && !metav1.HasAnnotation(pod.ObjectMeta, pkg.PodProvisioner) {
 					return true
 				}
 				return false
 			},
 			Handler: cache.ResourceEventHandlerFuncs{
-				UpdateFunc: func(oldObj, newObj interface{}) {
-					if pod, ok := newObj.(*corev1.Pod); ok {
-						for _, podCondition := range pod.Status.Conditions {
-							// Only for pending pods provisioned by ce
-							if podCondition.Type == corev1.PodScheduled && podCondition.Status == corev1.ConditionFalse &&
-								podCondition.Reason == corev1.PodReasonUnschedulable {
-								err = s.Stop(fmt.Sprintf("%v: %v", podCondition.Reason, podCondition.Message))
-							}
+				DeleteFunc: func(obj interface{}) {
+					if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+						obj = tombstone.Obj
+					}
+					if pod, ok := obj.(*corev1.Pod); ok {
+						s.lock.Lock()
+						defer s.lock.Unlock()
+
+						if s.replicasWithoutPreemption < 0 {
+							// the pod which triggers the preemption is not bound yet
+							s.replicasWithoutPreemption = s.simulated - 1
 						}
+						klog.V(2).InfoS("pod is preempted", "key", pod.Namespace+"/"+pod.Name, "node", pod.Spec.NodeName)
+						s.UpdatePreemptedPods(pod)
 					}
 				},
 			},
@@ -196,3 +470,8 @@ func (s *simulator) addEventHandlers(informerFactory informers.SharedInformerFac
 
 	return
 }
+
+// isNominated returns true if a node is nominated by the preemption, even if it's the same node nominated before
+func isNominated(nominatingInfo *framework.NominatingInfo) bool {
+	return nominatingInfo != nil && nominatingInfo.Mode() == framework.ModeOverride && len(nominatingInfo.NominatedNodeName) > 0
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/k-cloud-labs/kluster-capacity/pkg"
 	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
@@ -20,6 +21,8 @@ type ClusterCompressionReviewReviewStatus struct {
 	CreationTimestamp  time.Time                                   `json:"creationTimestamp"`
 	StopReason         *ClusterCompressionReviewScheduleStopReason `json:"stopReason"`
 	ScaleDownNodeNames []string                                    `json:"scaleDownNodeNames"`
+	// requested resources of the nodes after the nodes are scaled down
+	Nodes []utils.NodeUtilization `json:"nodes,omitempty"`
 }
 
 type ClusterCompressionReviewScheduleStopReason struct {
@@ -38,6 +41,7 @@ func getReviewStatus(status pkg.Status) ClusterCompressionReviewReviewStatus {
 		CreationTimestamp:  time.Now(),
 		StopReason:         getMainStopReason(status.StopReason),
 		ScaleDownNodeNames: status.NodesToScaleDown,
+		Nodes:              utils.GetNodeUtilizations(status.Nodes, status.Pods),
 	}
 }
 
@@ -56,6 +60,10 @@ func (r *ClusterCompressionReview) Print(verbose bool, format string) error {
 	switch format {
 	case "json":
 		return utils.PrintJson(r)
+	case "yaml":
+		return utils.PrintYaml(r)
+	case "html":
+		return utils.PrintHTML(htmlPage(r))
 	default:
 		return clusterCapacityReviewDefaultPrint(r, verbose)
 	}
@@ -83,3 +91,49 @@ func clusterCapacityReviewDefaultPrint(r *ClusterCompressionReview, verbose bool
 
 	return nil
 }
+
+func htmlPage(r *ClusterCompressionReview) *utils.HTMLPage {
+	page := &utils.HTMLPage{Title: "Cluster Compression"}
+
+	summary := utils.HTMLSection{Title: "Summary"}
+	if len(r.Status.ScaleDownNodeNames) > 0 {
+		summary.Paragraphs = append(summary.Paragraphs, fmt.Sprintf("%d node(s) in the cluster can be scaled down.", len(r.Status.ScaleDownNodeNames)))
+	} else {
+		summary.Paragraphs = append(summary.Paragraphs, "No nodes in the cluster can be scaled down.")
+	}
+	if r.Status.StopReason != nil {
+		summary.Paragraphs = append(summary.Paragraphs, fmt.Sprintf("Termination reason: %v: %v", r.Status.StopReason.StopType, r.Status.StopReason.StopMessage))
+	}
+	page.Sections = append(page.Sections, summary)
+
+	removed := sets.New[string](r.Status.ScaleDownNodeNames...)
+	zones := make(map[string]string, len(r.Status.Nodes))
+	var remaining []utils.NodeUtilization
+	for _, node := range r.Status.Nodes {
+		zones[node.Name] = node.Zone
+		if !removed.Has(node.Name) {
+			remaining = append(remaining, node)
+		}
+	}
+
+	if len(r.Status.ScaleDownNodeNames) > 0 {
+		section := utils.HTMLSection{Title: "Removal order"}
+		for _, name := range r.Status.ScaleDownNodeNames {
+			if zone := zones[name]; len(zone) > 0 {
+				name = fmt.Sprintf("%s (%s)", name, zone)
+			}
+			section.List = append(section.List, name)
+		}
+		page.Sections = append(page.Sections, section)
+	}
+
+	if len(remaining) > 0 {
+		page.Sections = append(page.Sections, utils.HTMLSection{
+			Title: "Utilization of the remaining nodes",
+			Table: utils.ZoneTable(remaining),
+			Bars:  remaining,
+		})
+	}
+
+	return page
+}
@@ -10,11 +10,13 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/informers"
 	clientset "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
 	"github.com/k-cloud-labs/kluster-capacity/app/cmds/clustercompression/options"
 	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/admission"
 	pkgframework "github.com/k-cloud-labs/kluster-capacity/pkg/framework"
 	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
 )
@@ -34,6 +36,8 @@ type simulator struct {
 	currentNodeUnschedulable bool
 	bindSuccessPodCount      int
 	nodeFilter               NodeFilter
+	// nil means the scheduling attempts are not traced
+	tracer *pkgframework.Tracer
 }
 
 // NewCCSimulatorExecutor create a ce simulator which is completely independent of apiserver so no need
@@ -44,12 +48,27 @@ func NewCCSimulatorExecutor(conf *options.ClusterCompressionConfig) (pkg.Simulat
 		return nil, err
 	}
 
-	kubeConfig, err := utils.BuildRestConfig(conf.Options.KubeConfig)
+	chain, err := admission.New(&conf.Options.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	// rest config is only needed when the world is initialized from a running cluster
+	var kubeConfig *restclient.Config
+	if len(conf.InitObjs) == 0 {
+		kubeConfig, err = utils.BuildRestConfig(conf.Options.KubeConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tracer, err := pkgframework.NewTracer(conf.Options.Trace)
 	if err != nil {
 		return nil, err
 	}
 
 	s := &simulator{
+		tracer:              tracer,
 		simulated:           0,
 		bindSuccessPodCount: 0,
 		createPodIndex:      0,
@@ -63,8 +82,10 @@ func NewCCSimulatorExecutor(conf *options.ClusterCompressionConfig) (pkg.Simulat
 	}
 
 	framework, err := pkgframework.NewKubeSchedulerFramework(cc, kubeConfig,
+		pkgframework.WithAdmission(chain),
 		pkgframework.WithExcludeNodes(conf.Options.ExcludeNodes),
 		pkgframework.WithPostBindHook(s.postBindHook),
+		pkgframework.WithTracer(tracer, ""),
 	)
 	if err != nil {
 		return nil, err
@@ -96,6 +117,14 @@ func (s *simulator) Initialize(objs ...runtime.Object) error {
 	return nil
 }
 
+func (s *simulator) Run() error {
+	defer func() {
+		_ = s.tracer.Close()
+	}()
+
+	return s.Framework.Run()
+}
+
 func (s *simulator) Report() pkg.Printer {
 	klog.V(2).Infof("the following nodes can be offline to save resources: %v", s.Status().NodesToScaleDown)
 	klog.V(2).Infof("the clusterCompression StopReason: %s", s.Status().StopReason)
@@ -178,28 +207,7 @@ func (s *simulator) selectNextNode() error {
 }
 
 func (s *simulator) cordon(node *corev1.Node) error {
-	node, err := s.fakeClient.CoreV1().Nodes().Get(context.TODO(), node.Name, metav1.GetOptions{})
-	if err != nil {
-		return err
-	}
-
-	copy := node.DeepCopy()
-
-	taints := []corev1.Taint{}
-	unScheduleTaint := corev1.Taint{
-		Key:    corev1.TaintNodeUnschedulable,
-		Effect: corev1.TaintEffectNoSchedule,
-	}
-	taints = append(taints, unScheduleTaint)
-
-	for i := range copy.Spec.Taints {
-		if copy.Spec.Taints[i].Key != corev1.TaintNodeUnschedulable {
-			taints = append(taints, copy.Spec.Taints[i])
-		}
-	}
-	copy.Spec.Taints = taints
-
-	_, err = s.fakeClient.CoreV1().Nodes().Update(context.TODO(), copy, metav1.UpdateOptions{})
+	err := utils.CordonNode(s.fakeClient, node.Name)
 	if err != nil {
 		return err
 	}
@@ -208,22 +216,7 @@ func (s *simulator) cordon(node *corev1.Node) error {
 }
 
 func (s *simulator) unCordon(nodeName string) error {
-	node, err := s.fakeClient.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
-	if err != nil {
-		return err
-	}
-
-	copy := node.DeepCopy()
-
-	taints := []corev1.Taint{}
-	for i := range copy.Spec.Taints {
-		if copy.Spec.Taints[i].Key != corev1.TaintNodeUnschedulable {
-			taints = append(taints, copy.Spec.Taints[i])
-		}
-	}
-	copy.Spec.Taints = taints
-
-	_, err = s.fakeClient.CoreV1().Nodes().Update(context.TODO(), copy, metav1.UpdateOptions{})
+	err := utils.UncordonNode(s.fakeClient, nodeName)
 	if err != nil {
 		return err
 	}
@@ -276,15 +269,9 @@ func (s *simulator) deletePodsByNode(node *corev1.Node) error {
 		return err
 	}
 
-	var createdPods []*corev1.Pod
-	for i := range podList {
-		if !utils.IsDaemonsetPod(podList[i].OwnerReferences) && podList[i].DeletionTimestamp == nil {
-			createdPods = append(createdPods, podList[i])
-			err := s.fakeClient.CoreV1().Pods(podList[i].Namespace).Delete(context.TODO(), podList[i].Name, metav1.DeleteOptions{})
-			if err != nil {
-				return err
-			}
-		}
+	createdPods, err := utils.DeletePodsToReschedule(s.fakeClient, podList)
+	if err != nil {
+		return err
 	}
 
 	s.createdPods = createdPods
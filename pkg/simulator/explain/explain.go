@@ -0,0 +1,130 @@
+package explain
+
+import (
+	"context"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+const (
+	PreFilter = "PreFilter"
+	Filter    = "Filter"
+	Score     = "Score"
+
+	Passed   = "Passed"
+	Rejected = "Rejected"
+	// the plugin is not run because a previous plugin returned an error
+	Skipped = "Skipped"
+	Scored  = "Scored"
+)
+
+// explainPod runs the PreFilter, Filter and Score plugins of the framework once on every node. Unlike the
+// scheduler, all the nodes are evaluated and all the filter plugins run on each node, and the pods nominated
+// to the nodes are not taken into account.
+func explainPod(ctx context.Context, fwk framework.Framework, pod *corev1.Pod, nodeName string) (*ExplainReview, error) {
+	nodeInfos, err := fwk.SnapshotSharedLister().NodeInfos().List()
+	if err != nil {
+		return nil, err
+	}
+
+	review := &ExplainReview{
+		Pod:   pod.Namespace + "/" + pod.Name,
+		Nodes: make([]NodeExplanation, 0),
+	}
+	plugins := fwk.ListPlugins()
+	state := framework.NewCycleState()
+	preFilterResult, preFilterStatus := fwk.RunPreFilterPlugins(ctx, state, pod)
+
+	explanations := make(map[string]*NodeExplanation, len(nodeInfos))
+	var feasibleNodes []*corev1.Node
+	for _, nodeInfo := range nodeInfos {
+		node := nodeInfo.Node()
+		if node == nil {
+			continue
+		}
+
+		explanation := &NodeExplanation{
+			NodeName: node.Name,
+			Plugins:  make([]PluginVerdict, 0),
+		}
+		explanations[node.Name] = explanation
+
+		if !preFilterStatus.IsSuccess() {
+			explanation.Plugins = append(explanation.Plugins, PluginVerdict{
+				Plugin:         preFilterStatus.FailedPlugin(),
+				ExtensionPoint: PreFilter,
+				Verdict:        Rejected,
+				Reasons:        preFilterStatus.Reasons(),
+			})
+			continue
+		}
+		if !preFilterResult.AllNodes() && !preFilterResult.NodeNames.Has(node.Name) {
+			explanation.Plugins = append(explanation.Plugins, PluginVerdict{
+				ExtensionPoint: PreFilter,
+				Verdict:        Rejected,
+				Reasons:        []string{"node is filtered out by the prefilter result"},
+			})
+			continue
+		}
+
+		statuses := fwk.RunFilterPlugins(ctx, state, pod, nodeInfo)
+		failed := false
+		for _, plugin := range plugins.Filter.Enabled {
+			verdict := PluginVerdict{
+				Plugin:         plugin.Name,
+				ExtensionPoint: Filter,
+				Verdict:        Passed,
+			}
+			if failed {
+				verdict.Verdict = Skipped
+			} else if status, ok := statuses[plugin.Name]; ok {
+				verdict.Verdict = Rejected
+				verdict.Reasons = status.Reasons()
+				// filter plugins stop running on the node after an error
+				failed = status.Code() == framework.Error
+			}
+			explanation.Plugins = append(explanation.Plugins, verdict)
+		}
+
+		if len(statuses) == 0 {
+			explanation.Feasible = true
+			feasibleNodes = append(feasibleNodes, node)
+		}
+	}
+
+	// scores are normalized among all the feasible nodes, so all of them are scored even if only one node is explained
+	if len(feasibleNodes) > 0 {
+		if status := fwk.RunPreScorePlugins(ctx, state, pod, feasibleNodes); !status.IsSuccess() {
+			review.ScoreError = status.Message()
+		} else if scores, status := fwk.RunScorePlugins(ctx, state, pod, feasibleNodes); !status.IsSuccess() {
+			review.ScoreError = status.Message()
+		} else {
+			for _, nodeScores := range scores {
+				explanation := explanations[nodeScores.Name]
+				explanation.TotalScore = nodeScores.TotalScore
+				for _, pluginScore := range nodeScores.Scores {
+					explanation.Plugins = append(explanation.Plugins, PluginVerdict{
+						Plugin:         pluginScore.Name,
+						ExtensionPoint: Score,
+						Verdict:        Scored,
+						Score:          pluginScore.Score,
+					})
+				}
+			}
+		}
+	}
+
+	for name, explanation := range explanations {
+		if len(nodeName) > 0 && name != nodeName {
+			continue
+		}
+		review.Nodes = append(review.Nodes, *explanation)
+	}
+	sort.Slice(review.Nodes, func(i, j int) bool {
+		return review.Nodes[i].NodeName < review.Nodes[j].NodeName
+	})
+
+	return review, nil
+}
@@ -0,0 +1,117 @@
+package explain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+type ExplainReview struct {
+	Pod string `json:"pod"`
+	// node chosen by the scheduler, empty when the pod is unschedulable
+	ChosenNode string `json:"chosenNode,omitempty"`
+	// why the pod is unschedulable
+	Error string `json:"error,omitempty"`
+	// why the feasible nodes are not scored
+	ScoreError string            `json:"scoreError,omitempty"`
+	Nodes      []NodeExplanation `json:"nodes"`
+}
+
+type NodeExplanation struct {
+	NodeName string `json:"nodeName"`
+	Feasible bool   `json:"feasible"`
+	// sum of the scores of all the score plugins, only for feasible nodes
+	TotalScore int64           `json:"totalScore,omitempty"`
+	Plugins    []PluginVerdict `json:"plugins"`
+}
+
+type PluginVerdict struct {
+	// empty when the node is filtered out by the result of all the pre filter plugins
+	Plugin         string `json:"plugin,omitempty"`
+	ExtensionPoint string `json:"extensionPoint"`
+	// Passed, Rejected, Skipped or Scored
+	Verdict string   `json:"verdict"`
+	Reasons []string `json:"reasons,omitempty"`
+	// normalized and weighted score, only for score plugins
+	Score int64 `json:"score,omitempty"`
+}
+
+func (r *ExplainReview) Print(verbose bool, format string) error {
+	switch format {
+	case "json":
+		return utils.PrintJson(r)
+	case "yaml":
+		return utils.PrintYaml(r)
+	case "":
+		prettyPrint(r, verbose)
+		return nil
+	default:
+		return fmt.Errorf("output format %q not recognized", format)
+	}
+}
+
+func prettyPrint(r *ExplainReview, verbose bool) {
+	fmt.Printf("Pod: %s\n", r.Pod)
+	if len(r.ChosenNode) > 0 {
+		fmt.Printf("Result: scheduled to %s\n", r.ChosenNode)
+	} else {
+		fmt.Printf("Result: unschedulable, reason: %s\n", r.Error)
+	}
+	if len(r.ScoreError) > 0 {
+		fmt.Printf("Failed to score the feasible nodes: %s\n", r.ScoreError)
+	}
+
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"node", "verdict", "rejected by", "score"})
+	for _, node := range r.Nodes {
+		verdict, score := "rejected", ""
+		if node.NodeName == r.ChosenNode {
+			verdict, score = "chosen", fmt.Sprint(node.TotalScore)
+		} else if node.Feasible {
+			verdict, score = "feasible", fmt.Sprint(node.TotalScore)
+		}
+
+		var rejectedBy []string
+		for _, plugin := range node.Plugins {
+			if plugin.Verdict == Rejected {
+				rejectedBy = append(rejectedBy, fmt.Sprintf("%s: %s", pluginName(plugin), strings.Join(plugin.Reasons, ", ")))
+			}
+		}
+		t.AppendRow(table.Row{node.NodeName, verdict, strings.Join(rejectedBy, "\n"), score})
+	}
+	fmt.Printf("\nNode verdicts:\n")
+	fmt.Println(t.Render())
+
+	// the verdict of every plugin is shown in verbose mode or when only one node is explained
+	if !verbose && len(r.Nodes) != 1 {
+		return
+	}
+
+	t = table.NewWriter()
+	t.AppendHeader(table.Row{"node", "extension point", "plugin", "verdict", "detail"})
+	t.SetColumnConfigs([]table.ColumnConfig{{Number: 1, AutoMerge: true}})
+	for _, node := range r.Nodes {
+		for _, plugin := range node.Plugins {
+			var detail string
+			if plugin.Verdict == Scored {
+				detail = fmt.Sprint(plugin.Score)
+			} else {
+				detail = strings.Join(plugin.Reasons, ", ")
+			}
+			t.AppendRow(table.Row{node.NodeName, plugin.ExtensionPoint, pluginName(plugin), plugin.Verdict, detail})
+		}
+	}
+	fmt.Printf("\nPlugin verdicts:\n")
+	fmt.Println(t.Render())
+}
+
+func pluginName(plugin PluginVerdict) string {
+	if len(plugin.Plugin) == 0 {
+		return "<prefilter result>"
+	}
+
+	return plugin.Plugin
+}
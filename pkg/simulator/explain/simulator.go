@@ -0,0 +1,144 @@
+package explain
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientset "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/explain/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/admission"
+	pkgframework "github.com/k-cloud-labs/kluster-capacity/pkg/framework"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+const Explained = "Explained: the pod has been scheduled once"
+
+// simulator schedules the pod once and explains the verdicts of the plugins on each node
+type simulator struct {
+	pkg.Framework
+
+	fakeClient clientset.Interface
+	pod        *corev1.Pod
+	// the pod from cluster replaces itself in the world, so that its resources are not counted twice
+	replace  bool
+	nodeName string
+	review   *ExplainReview
+}
+
+// NewExplainSimulatorExecutor create an explain simulator which is completely independent of apiserver so no need
+// for kubeconfig nor for apiserver url
+func NewExplainSimulatorExecutor(conf *options.ExplainConfig) (pkg.Simulator, error) {
+	kubeSchedulerConfig, err := utils.BuildKubeSchedulerCompletedConfig(conf.Options.SchedulerConfig, conf.Options.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := admission.New(&conf.Options.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	// rest config is only needed when the world is initialized from a running cluster
+	var kubeConfig *restclient.Config
+	if len(conf.InitObjs) == 0 {
+		kubeConfig, err = utils.BuildRestConfig(conf.Options.KubeConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s := &simulator{
+		fakeClient: kubeSchedulerConfig.Client,
+		pod:        utils.InitPod(conf.Pod),
+		replace:    len(conf.Options.PodFromCluster) > 0,
+		nodeName:   conf.Options.NodeName,
+	}
+
+	framework, err := pkgframework.NewKubeSchedulerFramework(kubeSchedulerConfig, kubeConfig,
+		pkgframework.WithAdmission(chain),
+		pkgframework.WithExcludeNodes(conf.Options.ExcludeNodes),
+		pkgframework.WithSchedulePodHook(s.explain))
+	if err != nil {
+		return nil, err
+	}
+
+	s.Framework = framework
+
+	return s, nil
+}
+
+func (s *simulator) Initialize(objs ...runtime.Object) error {
+	err := s.InitTheWorld(objs...)
+	if err != nil {
+		return err
+	}
+
+	if len(s.nodeName) > 0 {
+		_, err := s.fakeClient.CoreV1().Nodes().Get(context.TODO(), s.nodeName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("node %s doesn't exist or is excluded", s.nodeName)
+		} else if err != nil {
+			return err
+		}
+	}
+
+	if s.replace {
+		err := s.fakeClient.CoreV1().Pods(s.pod.Namespace).Delete(context.TODO(), s.pod.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	klog.V(2).InfoS("create pod to explain", "key", s.pod.Namespace+"/"+s.pod.Name)
+	return s.CreatePod(s.pod)
+}
+
+func (s *simulator) Report() pkg.Printer {
+	return s.review
+}
+
+// explain runs the plugins again on the snapshot used to schedule the pod, and stops the simulation
+func (s *simulator) explain(ctx context.Context, fwk framework.Framework, pod *corev1.Pod, result scheduler.ScheduleResult, scheduleErr error) {
+	if pod.UID != s.pod.UID {
+		return
+	}
+
+	review, err := explainPod(ctx, fwk, pod, s.nodeName)
+	if err != nil {
+		_ = s.Stop("FailedExplain: " + err.Error())
+		return
+	}
+
+	if scheduleErr != nil {
+		review.Error = scheduleErr.Error()
+	} else {
+		review.ChosenNode = result.SuggestedHost
+	}
+	s.review = review
+
+	_ = s.Stop(Explained)
+}
+
+// Run returns an error if the pod couldn't be explained
+func (s *simulator) Run() error {
+	err := s.Framework.Run()
+	if err != nil {
+		return err
+	}
+
+	if s.review == nil {
+		return fmt.Errorf("unable to explain pod %s/%s: %s", s.pod.Namespace, s.pod.Name, s.Status().StopReason)
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package hpaburst
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/capacityestimation"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+type HPABurstReview struct {
+	metav1.TypeMeta
+	Spec   HPABurstReviewSpec   `json:"spec"`
+	Status HPABurstReviewStatus `json:"status"`
+}
+
+type HPABurstReviewSpec struct {
+	// Priority or Interleaved
+	ScaleOrder string `json:"scaleOrder"`
+}
+
+type HPABurstReviewStatus struct {
+	CreationTimestamp time.Time                         `json:"creationTimestamp"`
+	StopReason        *HPABurstReviewScheduleStopReason `json:"stopReason"`
+	HPAs              []*HPABurstResult                 `json:"hpas"`
+}
+
+type HPABurstResult struct {
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	TargetKind string `json:"targetKind"`
+	TargetName string `json:"targetName"`
+	Priority   int32  `json:"priority"`
+	// replicas before scaling
+	CurrentReplicas int32 `json:"currentReplicas"`
+	MaxReplicas     int32 `json:"maxReplicas"`
+	// replicas scheduled in addition to the current replicas
+	ScheduledReplicas int32 `json:"scheduledReplicas"`
+	// replicas missing to reach maxReplicas
+	MissingReplicas int32 `json:"missingReplicas"`
+	// reason why no more replicas could schedule, empty if it reaches maxReplicas
+	Reason          string                               `json:"reason"`
+	ReplicasOnNodes []*capacityestimation.ReplicasOnNode `json:"replicasOnNodes"`
+}
+
+type HPABurstReviewScheduleStopReason struct {
+	StopType    string `json:"stopType"`
+	StopMessage string `json:"stopMessage"`
+}
+
+func generateReport(targets []*target, boundPods map[*target][]*corev1.Pod, scaleOrder string, status pkg.Status) *HPABurstReview {
+	results := make([]*HPABurstResult, 0, len(targets))
+	for _, t := range targets {
+		result := &HPABurstResult{
+			Namespace:         t.hpa.Namespace,
+			Name:              t.hpa.Name,
+			TargetKind:        t.hpa.Spec.ScaleTargetRef.Kind,
+			TargetName:        t.hpa.Spec.ScaleTargetRef.Name,
+			Priority:          t.priority,
+			CurrentReplicas:   t.currentReplicas,
+			MaxReplicas:       t.hpa.Spec.MaxReplicas,
+			ScheduledReplicas: t.scheduled,
+			MissingReplicas:   t.desired - t.scheduled,
+			Reason:            t.failedReason,
+			ReplicasOnNodes:   make([]*capacityestimation.ReplicasOnNode, 0),
+		}
+
+		replicasOnNodes := make(map[string]*capacityestimation.ReplicasOnNode)
+		for _, pod := range boundPods[t] {
+			if ron, ok := replicasOnNodes[pod.Spec.NodeName]; ok {
+				ron.Replicas++
+				continue
+			}
+			ron := &capacityestimation.ReplicasOnNode{NodeName: pod.Spec.NodeName, Replicas: 1}
+			replicasOnNodes[pod.Spec.NodeName] = ron
+			result.ReplicasOnNodes = append(result.ReplicasOnNodes, ron)
+		}
+
+		results = append(results, result)
+	}
+
+	return &HPABurstReview{
+		Spec: HPABurstReviewSpec{
+			ScaleOrder: scaleOrder,
+		},
+		Status: HPABurstReviewStatus{
+			CreationTimestamp: time.Now(),
+			StopReason:        getMainStopReason(status.StopReason),
+			HPAs:              results,
+		},
+	}
+}
+
+func getMainStopReason(message string) *HPABurstReviewScheduleStopReason {
+	slicedMessage := strings.Split(message, "\n")
+	colon := strings.Index(slicedMessage[0], ":")
+	// the message without a type, e.g. an empty one, is kept as it is
+	if colon < 0 {
+		return &HPABurstReviewScheduleStopReason{
+			StopMessage: strings.TrimSpace(message),
+		}
+	}
+
+	reason := &HPABurstReviewScheduleStopReason{
+		StopType:    slicedMessage[0][:colon],
+		StopMessage: strings.Trim(slicedMessage[0][colon+1:], " "),
+	}
+	return reason
+}
+
+func (r *HPABurstReview) Print(verbose bool, format string) error {
+	switch format {
+	case "json":
+		return utils.PrintJson(r)
+	case "yaml":
+		return utils.PrintYaml(r)
+	case "":
+		prettyPrint(r, verbose)
+		return nil
+	default:
+		return fmt.Errorf("output format %q not recognized", format)
+	}
+}
+
+func prettyPrint(r *HPABurstReview, verbose bool) {
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"hpa", "target", "priority", "current", "max", "scheduled", "missing"})
+	for _, hpa := range r.Status.HPAs {
+		t.AppendRow(table.Row{hpa.Namespace + "/" + hpa.Name, hpa.TargetKind + "/" + hpa.TargetName, hpa.Priority,
+			hpa.CurrentReplicas, hpa.MaxReplicas, hpa.ScheduledReplicas, hpa.MissingReplicas})
+	}
+	fmt.Println(t.Render())
+
+	if !verbose {
+		return
+	}
+
+	fmt.Printf("\nTermination reason: %v: %v\n", r.Status.StopReason.StopType, r.Status.StopReason.StopMessage)
+
+	for _, hpa := range r.Status.HPAs {
+		if hpa.MissingReplicas > 0 {
+			fmt.Printf("\n%v/%v can't reach maxReplicas, %v replica(s) missing: %v\n", hpa.Namespace, hpa.Name, hpa.MissingReplicas, hpa.Reason)
+		}
+	}
+
+	fmt.Printf("\nPod distribution among nodes:\n")
+	for _, hpa := range r.Status.HPAs {
+		if len(hpa.ReplicasOnNodes) == 0 {
+			continue
+		}
+		fmt.Printf("%v/%v\n", hpa.Namespace, hpa.Name)
+		for _, ron := range hpa.ReplicasOnNodes {
+			fmt.Printf("\t- %v: %v instance(s)\n", ron.NodeName, ron.Replicas)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package hpaburst
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/hpaburst/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/admission"
+	pkgframework "github.com/k-cloud-labs/kluster-capacity/pkg/framework"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+const (
+	NoHPA        = "NoHPA: no HorizontalPodAutoscaler needs to scale up"
+	AllSimulated = "AllSimulated: %d HorizontalPodAutoscaler(s) have been scaled to maxReplicas, %d of them can't reach maxReplicas"
+)
+
+type simulator struct {
+	pkg.Framework
+
+	fakeClient clientset.Interface
+	scaleOrder string
+	namespaces sets.Set[string]
+	targets    []*target
+	// index of the target which creates the latest pod
+	current int
+
+	// simulated pod waiting for scheduling and its target, only one pod is pending at a time
+	lock       sync.Mutex
+	pendingPod string
+	pending    *target
+	// pods bound for each target
+	boundPods map[*target][]*corev1.Pod
+	// nil means the scheduling attempts are not traced
+	tracer *pkgframework.Tracer
+}
+
+// NewHBSimulatorExecutor create a hb simulator which is completely independent of apiserver so no need
+// for kubeconfig nor for apiserver url
+func NewHBSimulatorExecutor(conf *options.HPABurstConfig) (pkg.Simulator, error) {
+	kubeSchedulerConfig, err := utils.BuildKubeSchedulerCompletedConfig(conf.Options.SchedulerConfig, conf.Options.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := admission.New(&conf.Options.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeConfig, err := utils.BuildRestConfig(conf.Options.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	tracer, err := pkgframework.NewTracer(conf.Options.Trace)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &simulator{
+		fakeClient: kubeSchedulerConfig.Client,
+		tracer:     tracer,
+		scaleOrder: conf.Options.ScaleOrder,
+		namespaces: sets.New[string](conf.Options.Namespaces...),
+		current:    -1,
+		boundPods:  make(map[*target][]*corev1.Pod),
+	}
+
+	err = s.addEventHandlers(kubeSchedulerConfig.InformerFactory)
+	if err != nil {
+		return nil, err
+	}
+
+	framework, err := pkgframework.NewKubeSchedulerFramework(kubeSchedulerConfig, kubeConfig,
+		pkgframework.WithAdmission(chain),
+		pkgframework.WithExcludeNodes(conf.Options.ExcludeNodes),
+		pkgframework.WithPostBindHook(s.postBindHook),
+		pkgframework.WithTracer(tracer, ""))
+	if err != nil {
+		return nil, err
+	}
+
+	s.Framework = framework
+
+	return s, nil
+}
+
+func (s *simulator) Initialize(objs ...runtime.Object) error {
+	err := s.InitTheWorld(objs...)
+	if err != nil {
+		return err
+	}
+
+	hpaList, err := s.fakeClient.AutoscalingV2().HorizontalPodAutoscalers(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for i := range hpaList.Items {
+		hpa := &hpaList.Items[i]
+		if s.namespaces.Len() > 0 && !s.namespaces.Has(hpa.Namespace) {
+			continue
+		}
+
+		t, err := newTarget(s.fakeClient, hpa)
+		if err != nil {
+			klog.ErrorS(err, "Ignore HorizontalPodAutoscaler with invalid target", "hpa", hpa.Namespace+"/"+hpa.Name)
+			continue
+		}
+		s.targets = append(s.targets, t)
+	}
+
+	// pods with higher priority scale first, the others are in the order of namespace/name
+	sort.SliceStable(s.targets, func(i, j int) bool {
+		if s.targets[i].priority != s.targets[j].priority {
+			return s.targets[i].priority > s.targets[j].priority
+		}
+		return s.targets[i].hpa.Namespace+"/"+s.targets[i].hpa.Name < s.targets[j].hpa.Namespace+"/"+s.targets[j].hpa.Name
+	})
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.createNextPod()
+}
+
+func (s *simulator) Run() error {
+	defer func() {
+		_ = s.tracer.Close()
+	}()
+
+	return s.Framework.Run()
+}
+
+func (s *simulator) Report() pkg.Printer {
+	return generateReport(s.targets, s.boundPods, s.scaleOrder, s.Status())
+}
+
+func (s *simulator) postBindHook(bindPod *corev1.Pod) error {
+	if !metav1.HasAnnotation(bindPod.ObjectMeta, pkg.PodProvisioner) {
+		return nil
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.pending == nil || s.pendingPod != bindPod.Name {
+		return nil
+	}
+
+	s.pending.scheduled++
+	s.boundPods[s.pending] = append(s.boundPods[s.pending], bindPod)
+
+	return s.createNextPod()
+}
+
+// nextTarget returns the next target to scale, nil means all the targets are done
+func (s *simulator) nextTarget() *target {
+	if s.scaleOrder == options.ScaleInPriorityOrder {
+		for _, t := range s.targets {
+			if !t.done() {
+				return t
+			}
+		}
+		return nil
+	}
+
+	for i := 1; i <= len(s.targets); i++ {
+		index := (s.current + i) % len(s.targets)
+		if !s.targets[index].done() {
+			s.current = index
+			return s.targets[index]
+		}
+	}
+	return nil
+}
+
+// createNextPod must be called with lock held
+func (s *simulator) createNextPod() error {
+	t := s.nextTarget()
+	if t == nil {
+		s.pending = nil
+		s.pendingPod = ""
+		if len(s.targets) == 0 {
+			return s.Stop(NoHPA)
+		}
+
+		failed := 0
+		for _, t := range s.targets {
+			if len(t.failedReason) > 0 {
+				failed++
+			}
+		}
+		return s.Stop(fmt.Sprintf(AllSimulated, len(s.targets), failed))
+	}
+
+	pod := t.generator.Generate()
+	s.pending = t
+	s.pendingPod = pod.Name
+	klog.V(2).InfoS("create simulate pod", "hpa", t.hpa.Namespace+"/"+t.hpa.Name, "key", pod.Namespace+"/"+pod.Name)
+
+	return s.CreatePod(pod)
+}
+
+func (s *simulator) addEventHandlers(informerFactory informers.SharedInformerFactory) (err error) {
+	_, _ = informerFactory.Core().V1().Pods().Informer().AddEventHandler(
+		cache.FilteringResourceEventHandler{
+			FilterFunc: func(obj interface{}) bool {
+				if pod, ok := obj.(*corev1.Pod); ok && pod.Spec.SchedulerName == pkg.SchedulerName &&
+					metav1.HasAnnotation(pod.ObjectMeta, pkg.PodProvisioner) {
+					return true
+				}
+				return false
+			},
+			Handler: cache.ResourceEventHandlerFuncs{
+				UpdateFunc: func(oldObj, newObj interface{}) {
+					if pod, ok := newObj.(*corev1.Pod); ok {
+						for _, podCondition := range pod.Status.Conditions {
+							// Only for pending pods provisioned by hb
+							if podCondition.Type == corev1.PodScheduled && podCondition.Status == corev1.ConditionFalse &&
+								podCondition.Reason == corev1.PodReasonUnschedulable {
+								err = s.onUnschedulable(pod, podCondition.Message)
+							}
+						}
+					}
+				},
+			},
+		},
+	)
+
+	return
+}
+
+// onUnschedulable marks the target as failed, then removes the pod and continues with the other targets
+func (s *simulator) onUnschedulable(pod *corev1.Pod, message string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.pending == nil || s.pendingPod != pod.Name {
+		return nil
+	}
+
+	klog.V(2).InfoS("simulate pod is unschedulable", "hpa", s.pending.hpa.Namespace+"/"+s.pending.hpa.Name, "key", pod.Namespace+"/"+pod.Name)
+	s.pending.failedReason = fmt.Sprintf("%v: %v", corev1.PodReasonUnschedulable, message)
+	if err := s.fakeClient.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.Name, metav1.DeleteOptions{}); err != nil {
+		return s.Stop("FailedDeletePod: " + err.Error())
+	}
+
+	return s.createNextPod()
+}
@@ -0,0 +1,120 @@
+package hpaburst
+
+import (
+	"context"
+	"fmt"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/capacityestimation"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+// target is the workload scaled by a hpa
+type target struct {
+	hpa             *autoscalingv2.HorizontalPodAutoscaler
+	template        *corev1.Pod
+	generator       capacityestimation.PodGenerator
+	priority        int32
+	currentReplicas int32
+	// replicas need to be created to reach maxReplicas
+	desired   int32
+	scheduled int32
+	// reason why no more replicas of the target could schedule
+	failedReason string
+}
+
+func (t *target) done() bool {
+	return t.scheduled >= t.desired || len(t.failedReason) > 0
+}
+
+func newTarget(client clientset.Interface, hpa *autoscalingv2.HorizontalPodAutoscaler) (*target, error) {
+	template, replicas, err := getPodTemplate(client, hpa.Namespace, hpa.Spec.ScaleTargetRef)
+	if err != nil {
+		return nil, err
+	}
+
+	currentReplicas := hpa.Status.CurrentReplicas
+	if currentReplicas == 0 {
+		currentReplicas = replicas
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: *template.ObjectMeta.DeepCopy(),
+		Spec:       *template.Spec.DeepCopy(),
+	}
+	pod.Namespace = hpa.Namespace
+	// use a name which never conflicts with the existing pods of the target
+	pod.Name = fmt.Sprintf("%s-hpa-burst", hpa.Spec.ScaleTargetRef.Name)
+	pod.OwnerReferences = []metav1.OwnerReference{
+		{
+			APIVersion: hpa.Spec.ScaleTargetRef.APIVersion,
+			Kind:       hpa.Spec.ScaleTargetRef.Kind,
+			Name:       hpa.Spec.ScaleTargetRef.Name,
+		},
+	}
+
+	priority, err := utils.GetPodPriority(client, pod)
+	if err != nil {
+		return nil, err
+	}
+	pod.Spec.Priority = &priority
+
+	desired := hpa.Spec.MaxReplicas - currentReplicas
+	if desired < 0 {
+		desired = 0
+	}
+
+	return &target{
+		hpa:             hpa,
+		template:        pod,
+		generator:       capacityestimation.NewSinglePodGenerator(pod),
+		priority:        priority,
+		currentReplicas: currentReplicas,
+		desired:         desired,
+	}, nil
+}
+
+func getPodTemplate(client clientset.Interface, namespace string, ref autoscalingv2.CrossVersionObjectReference) (*corev1.PodTemplateSpec, int32, error) {
+	replicasOf := func(replicas *int32) int32 {
+		if replicas == nil {
+			return 1
+		}
+		return *replicas
+	}
+
+	switch ref.Kind {
+	case "Deployment":
+		deploy, err := client.AppsV1().Deployments(namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, 0, err
+		}
+		return &deploy.Spec.Template, replicasOf(deploy.Spec.Replicas), nil
+	case "StatefulSet":
+		sts, err := client.AppsV1().StatefulSets(namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, 0, err
+		}
+		return &sts.Spec.Template, replicasOf(sts.Spec.Replicas), nil
+	case "ReplicaSet":
+		rs, err := client.AppsV1().ReplicaSets(namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, 0, err
+		}
+		return &rs.Spec.Template, replicasOf(rs.Spec.Replicas), nil
+	case "ReplicationController":
+		rc, err := client.CoreV1().ReplicationControllers(namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, 0, err
+		}
+		if rc.Spec.Template == nil {
+			return nil, 0, fmt.Errorf("replication controller %s/%s has no pod template", namespace, ref.Name)
+		}
+		return rc.Spec.Template, replicasOf(rc.Spec.Replicas), nil
+	default:
+		return nil, 0, fmt.Errorf("unsupported scale target kind %q", ref.Kind)
+	}
+}
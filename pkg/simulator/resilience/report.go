@@ -0,0 +1,130 @@
+package resilience
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+type ResilienceReview struct {
+	// empty if each node is a failure domain
+	TopologyKey string         `json:"topologyKey,omitempty"`
+	Domains     []DomainReview `json:"domains"`
+}
+
+// DomainReview is the result of removing a failure domain from the world
+type DomainReview struct {
+	Domain string   `json:"domain"`
+	Nodes  []string `json:"nodes"`
+	// pods created again to be rescheduled
+	DisplacedPods int `json:"displacedPods"`
+	// pods displaced without a controller to create them again
+	LostPods    []string     `json:"lostPods,omitempty"`
+	PendingPods []PendingPod `json:"pendingPods,omitempty"`
+	StopReason  string       `json:"stopReason"`
+}
+
+// PendingPod is a displaced pod which can't be rescheduled
+type PendingPod struct {
+	Pod string `json:"pod"`
+	// kind/namespace/name of the workload of the pod
+	Workload string `json:"workload"`
+	Message  string `json:"message,omitempty"`
+}
+
+// Survived returns true if all the pods displaced from the failure domain are rescheduled, the lost pods can't
+// be rescheduled whatever the capacity is
+func (d *DomainReview) Survived() bool {
+	return len(d.PendingPods) == 0
+}
+
+// Workloads returns the workloads left with pending pods
+func (d *DomainReview) Workloads() []string {
+	counts := make(map[string]int)
+	for _, pod := range d.PendingPods {
+		counts[pod.Workload]++
+	}
+
+	workloads := make([]string, 0, len(counts))
+	for workload, count := range counts {
+		workloads = append(workloads, fmt.Sprintf("%s (%d pending)", workload, count))
+	}
+	sort.Strings(workloads)
+
+	return workloads
+}
+
+// Failed returns the failure domains the cluster can't survive
+func (r *ResilienceReview) Failed() []string {
+	var failed []string
+	for i := range r.Domains {
+		if !r.Domains[i].Survived() {
+			failed = append(failed, r.Domains[i].Domain)
+		}
+	}
+
+	return failed
+}
+
+func (r *ResilienceReview) Print(verbose bool, format string) error {
+	switch format {
+	case "json":
+		return utils.PrintJson(r)
+	case "yaml":
+		return utils.PrintYaml(r)
+	case "":
+		resiliencePrettyPrint(r, verbose)
+		return nil
+	default:
+		return fmt.Errorf("output format %q not recognized", format)
+	}
+}
+
+func resiliencePrettyPrint(r *ResilienceReview, verbose bool) {
+	domainHeader := "node"
+	if len(r.TopologyKey) > 0 {
+		domainHeader = r.TopologyKey
+	}
+
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{domainHeader, "nodes", "displaced", "lost", "pending", "result"})
+	for i := range r.Domains {
+		d := &r.Domains[i]
+		result := "survived"
+		if !d.Survived() {
+			result = "failed"
+		}
+		t.AppendRow(table.Row{d.Domain, len(d.Nodes), d.DisplacedPods, len(d.LostPods), len(d.PendingPods), result})
+	}
+	fmt.Println(t.Render())
+
+	if verbose {
+		for i := range r.Domains {
+			d := &r.Domains[i]
+			if d.Survived() && len(d.LostPods) == 0 {
+				continue
+			}
+			fmt.Printf("\nWithout %s (%s):\n", d.Domain, strings.Join(d.Nodes, ", "))
+			for _, pod := range d.LostPods {
+				fmt.Printf("- lost: %s\n", pod)
+			}
+			for _, workload := range d.Workloads() {
+				fmt.Printf("- %s\n", workload)
+			}
+			for _, pod := range d.PendingPods {
+				fmt.Printf("  - %s: %s\n", pod.Pod, pod.Message)
+			}
+		}
+	}
+
+	failed := r.Failed()
+	if len(failed) == 0 {
+		fmt.Printf("\nThe cluster survives the loss of any single %s out of %d.\n", domainHeader, len(r.Domains))
+	} else {
+		fmt.Printf("\nThe cluster can't survive the loss of %d %s(s) out of %d: %s\n", len(failed), domainHeader, len(r.Domains), strings.Join(failed, ", "))
+	}
+}
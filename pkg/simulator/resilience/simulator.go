@@ -0,0 +1,339 @@
+package resilience
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/resilience/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/admission"
+	pkgframework "github.com/k-cloud-labs/kluster-capacity/pkg/framework"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+const (
+	NothingDisplaced = "NothingDisplaced: no pods need to be rescheduled"
+	AllRescheduled   = "AllRescheduled: %d pod(s) have been rescheduled"
+	PodsPending      = "PodsPending: %d of %d pod(s) can't be rescheduled"
+)
+
+// domain is a set of nodes which fail together
+type domain struct {
+	name  string
+	nodes []string
+}
+
+// simulator removes a failure domain from the world and reschedules the pods displaced
+type simulator struct {
+	pkg.Framework
+
+	fakeClient clientset.Interface
+	domain     domain
+	// UIDs of the pods created again for the displaced ones
+	displaced sets.Set[types.UID]
+	// pods displaced without a controller to create them again
+	lost    []string
+	pending []PendingPod
+}
+
+// multiSimulator runs an independent simulator for each failure domain, all the simulators share one world
+type multiSimulator struct {
+	conf        *options.ResilienceConfig
+	world       *pkgframework.World
+	domains     []domain
+	parallelism int
+	review      *ResilienceReview
+}
+
+// NewResilienceSimulatorExecutor create a resilience simulator which is completely independent of apiserver so no need
+// for kubeconfig nor for apiserver url
+func NewResilienceSimulatorExecutor(conf *options.ResilienceConfig) (pkg.Simulator, error) {
+	ms := &multiSimulator{
+		conf:        conf,
+		parallelism: conf.Options.Parallelism,
+	}
+	if ms.parallelism < 1 {
+		ms.parallelism = 1
+	}
+
+	return ms, nil
+}
+
+// Initialize loads the world only once and groups the nodes into failure domains, the simulators are created
+// lazily in Run
+func (ms *multiSimulator) Initialize(objs ...runtime.Object) error {
+	if len(objs) == 0 {
+		kubeConfig, err := utils.BuildRestConfig(ms.conf.Options.KubeConfig)
+		if err != nil {
+			return err
+		}
+		objs, err = pkgframework.GetInitObjectsFromCluster(kubeConfig)
+		if err != nil {
+			return err
+		}
+	}
+
+	world, err := pkgframework.NewWorld(objs, pkgframework.WithExcludeNodes(ms.conf.Options.ExcludeNodes))
+	if err != nil {
+		return err
+	}
+	ms.world = world
+	ms.domains = groupNodes(objs, ms.conf.Options.TopologyKey, sets.New[string](ms.conf.Options.ExcludeNodes...))
+
+	return nil
+}
+
+// groupNodes returns the failure domains in the order of their names, the nodes without the topology label
+// don't belong to any failure domain
+func groupNodes(objs []runtime.Object, topologyKey string, excludeNodes sets.Set[string]) []domain {
+	nodes := make(map[string][]string)
+	for _, obj := range objs {
+		node, ok := obj.(*corev1.Node)
+		if !ok || excludeNodes.Has(node.Name) {
+			continue
+		}
+
+		name := node.Name
+		if len(topologyKey) > 0 {
+			value, ok := node.Labels[topologyKey]
+			if !ok {
+				klog.V(2).InfoS("Ignore node without the topology label", "node", node.Name, "topologyKey", topologyKey)
+				continue
+			}
+			name = value
+		}
+		nodes[name] = append(nodes[name], node.Name)
+	}
+
+	var domains []domain
+	for name, names := range nodes {
+		sort.Strings(names)
+		domains = append(domains, domain{name: name, nodes: names})
+	}
+	sort.Slice(domains, func(i, j int) bool {
+		return domains[i].name < domains[j].name
+	})
+
+	return domains
+}
+
+func (ms *multiSimulator) Run() error {
+	g := errgroup.Group{}
+	g.SetLimit(ms.parallelism)
+	reviews := make([]DomainReview, len(ms.domains))
+	for i, d := range ms.domains {
+		i := i
+		d := d
+		g.Go(func() error {
+			s, err := ms.newSimulator(d)
+			if err != nil {
+				return err
+			}
+
+			err = s.Initialize()
+			if err != nil {
+				return err
+			}
+
+			err = s.Run()
+			if err != nil {
+				return err
+			}
+			reviews[i] = s.review()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	ms.review = &ResilienceReview{
+		TopologyKey: ms.conf.Options.TopologyKey,
+		Domains:     reviews,
+	}
+
+	return nil
+}
+
+func (ms *multiSimulator) Report() pkg.Printer {
+	return ms.review
+}
+
+func (ms *multiSimulator) newSimulator(d domain) (*simulator, error) {
+	kubeSchedulerConfig, err := utils.BuildKubeSchedulerCompletedConfig(ms.conf.Options.SchedulerConfig, ms.conf.Options.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := admission.New(&ms.conf.Options.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &simulator{
+		fakeClient: kubeSchedulerConfig.Client,
+		domain:     d,
+		displaced:  sets.New[types.UID](),
+	}
+
+	// the world is already loaded, so no rest config is needed
+	framework, err := pkgframework.NewKubeSchedulerFramework(kubeSchedulerConfig, nil,
+		pkgframework.WithAdmission(chain),
+		pkgframework.WithWorld(ms.world),
+		pkgframework.WithExcludeNodes(ms.conf.Options.ExcludeNodes),
+		pkgframework.WithQuiescedHook(s.onQuiesced))
+	if err != nil {
+		return nil, err
+	}
+
+	s.Framework = framework
+
+	return s, nil
+}
+
+// Initialize removes the nodes of the failure domain and creates the pods displaced from them again. Only the
+// pods managed by a controller are created again, the others are lost, and the DaemonSet and mirror pods are gone
+// with the nodes.
+func (s *simulator) Initialize(objs ...runtime.Object) error {
+	err := s.InitTheWorld(objs...)
+	if err != nil {
+		return err
+	}
+
+	podList, err := s.fakeClient.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{ResourceVersion: "0"})
+	if err != nil {
+		return err
+	}
+	podsByNode := make(map[string][]*corev1.Pod)
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		podsByNode[pod.Spec.NodeName] = append(podsByNode[pod.Spec.NodeName], pod)
+	}
+
+	var displaced []*corev1.Pod
+	for _, nodeName := range s.domain.nodes {
+		pods, err := utils.DeletePodsToReschedule(s.fakeClient, podsByNode[nodeName])
+		if err != nil {
+			return err
+		}
+		displaced = append(displaced, pods...)
+		if err := s.removeNode(nodeName, podsByNode[nodeName]); err != nil {
+			return err
+		}
+	}
+	klog.V(2).InfoS("Remove failure domain", "domain", s.domain.name, "nodes", s.domain.nodes, "displaced", len(displaced))
+
+	for _, pod := range displaced {
+		if utils.IsMirrorPod(pod) {
+			continue
+		}
+		if metav1.GetControllerOf(pod) == nil {
+			s.lost = append(s.lost, pod.Namespace+"/"+pod.Name)
+			continue
+		}
+
+		pod = utils.InitPod(pod)
+		if err := s.CreatePod(pod); err != nil {
+			return err
+		}
+		s.displaced.Insert(pod.UID)
+	}
+	sort.Strings(s.lost)
+
+	if s.displaced.Len() == 0 {
+		return s.Stop(NothingDisplaced)
+	}
+
+	return nil
+}
+
+// removeNode deletes the node of the failure domain with the pods left on it, the same as upgrade does. A
+// cordoned node would still take the pods tolerating all the taints.
+func (s *simulator) removeNode(nodeName string, pods []*corev1.Pod) error {
+	for _, pod := range pods {
+		err := s.fakeClient.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return s.fakeClient.CoreV1().Nodes().Delete(context.TODO(), nodeName, metav1.DeleteOptions{})
+}
+
+// onQuiesced stops the simulation when the scheduler has nothing to do, the displaced pods not bound by then
+// can't be rescheduled
+func (s *simulator) onQuiesced() error {
+	podList, err := s.fakeClient.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{ResourceVersion: "0"})
+	if err != nil {
+		return err
+	}
+
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if !s.displaced.Has(pod.UID) || len(pod.Spec.NodeName) > 0 {
+			continue
+		}
+
+		pending := PendingPod{
+			Pod:      pod.Namespace + "/" + pod.Name,
+			Workload: s.workloadOf(pod),
+		}
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse {
+				pending.Message = cond.Message
+			}
+		}
+		s.pending = append(s.pending, pending)
+	}
+
+	if len(s.pending) == 0 {
+		return s.Stop(fmt.Sprintf(AllRescheduled, s.displaced.Len()))
+	}
+	return s.Stop(fmt.Sprintf(PodsPending, len(s.pending), s.displaced.Len()))
+}
+
+// workloadOf returns the kind/namespace/name of the workload controlling the pod, the pods of a Deployment
+// are controlled by its ReplicaSets
+func (s *simulator) workloadOf(pod *corev1.Pod) string {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return fmt.Sprintf("Pod/%s/%s", pod.Namespace, pod.Name)
+	}
+
+	if ref.Kind == "ReplicaSet" {
+		rs, err := s.fakeClient.AppsV1().ReplicaSets(pod.Namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+		if err == nil {
+			if owner := metav1.GetControllerOf(rs); owner != nil && owner.Kind == "Deployment" {
+				return fmt.Sprintf("Deployment/%s/%s", pod.Namespace, owner.Name)
+			}
+		}
+	}
+
+	return fmt.Sprintf("%s/%s/%s", ref.Kind, pod.Namespace, ref.Name)
+}
+
+func (s *simulator) review() DomainReview {
+	sort.Slice(s.pending, func(i, j int) bool {
+		return s.pending[i].Pod < s.pending[j].Pod
+	})
+
+	return DomainReview{
+		Domain:        s.domain.name,
+		Nodes:         s.domain.nodes,
+		DisplacedPods: s.displaced.Len(),
+		LostPods:      s.lost,
+		PendingPods:   s.pending,
+		StopReason:    s.Status().StopReason,
+	}
+}
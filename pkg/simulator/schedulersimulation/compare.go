@@ -0,0 +1,91 @@
+package schedulersimulation
+
+import (
+	"errors"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/schedulersimulation/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/framework"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/plugins/replayorder"
+)
+
+// compareSimulator replays the same world under two scheduler configurations
+type compareSimulator struct {
+	schedulerConfigs []string
+	simulators       []*simulator
+	// shared by both simulations
+	tracer *framework.Tracer
+}
+
+func NewSSCompareExecutor(conf *options.SchedulerSimulationCompareConfig) (pkg.Simulator, error) {
+	if len(conf.Options.SchedulerConfigs) != 2 {
+		return nil, errors.New("exactly two scheduler configurations are needed for comparison")
+	}
+
+	// both simulations must replay the pods in the same order
+	replaySeed := conf.Options.ReplaySeed
+	if conf.Options.ReplayOrder == replayorder.Random && replaySeed == 0 {
+		replaySeed = time.Now().UnixNano()
+	}
+
+	tracer, err := framework.NewTracer(conf.Options.Trace)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := &compareSimulator{
+		schedulerConfigs: conf.Options.SchedulerConfigs,
+		tracer:           tracer,
+	}
+	for _, schedulerConfig := range conf.Options.SchedulerConfigs {
+		opt := conf.Options.SchedulerSimulationOptions
+		opt.SchedulerConfig = schedulerConfig
+		opt.ReplaySeed = replaySeed
+
+		ssConf := options.NewSchedulerSimulationConfig(&opt)
+		ssConf.InitObjs = conf.InitObjs
+		// traces of the two simulations are told apart by the scheduler configuration
+		s, err := newSimulator(ssConf, nil, tracer, schedulerConfig)
+		if err != nil {
+			_ = tracer.Close()
+			return nil, err
+		}
+		cs.simulators = append(cs.simulators, s)
+	}
+
+	return cs, nil
+}
+
+func (cs *compareSimulator) Initialize(objs ...runtime.Object) error {
+	for _, s := range cs.simulators {
+		if err := s.Initialize(objs...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cs *compareSimulator) Run() error {
+	defer func() {
+		_ = cs.tracer.Close()
+	}()
+
+	g := errgroup.Group{}
+	for _, s := range cs.simulators {
+		s := s
+		g.Go(func() error {
+			return s.Run()
+		})
+	}
+
+	return g.Wait()
+}
+
+func (cs *compareSimulator) Report() pkg.Printer {
+	return generateComparison(cs.schedulerConfigs[0], cs.simulators[0], cs.schedulerConfigs[1], cs.simulators[1])
+}
@@ -0,0 +1,247 @@
+package schedulersimulation
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/k-cloud-labs/kluster-capacity/pkg/plugins/replayorder"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+type SchedulerSimulationComparison struct {
+	Base   ComparedSimulation `json:"base"`
+	Target ComparedSimulation `json:"target"`
+	// pods scheduled to different nodes, including the ones only unschedulable in one of the simulations
+	PodsPlacedDifferently []PodPlacementDiff `json:"podsPlacedDifferently"`
+	// utilization is the ratio of the requested resources to the allocatable resources of the node
+	NodeUtilizationDeltas []NodeUtilizationDelta `json:"nodeUtilizationDeltas"`
+}
+
+type ComparedSimulation struct {
+	SchedulerConfig string `json:"schedulerConfig"`
+	StopReason      string `json:"stopReason"`
+	ReplayOrder     string `json:"replayOrder"`
+	ReplaySeed      int64  `json:"replaySeed,omitempty"`
+	// number of nodes with at least one pod which doesn't belong to a DaemonSet
+	NodesUsed         int      `json:"nodesUsed"`
+	UnschedulablePods []string `json:"unschedulablePods"`
+}
+
+type PodPlacementDiff struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// empty means the pod is unschedulable
+	BaseNode   string `json:"baseNode"`
+	TargetNode string `json:"targetNode"`
+}
+
+type NodeUtilizationDelta struct {
+	NodeName     string  `json:"nodeName"`
+	BaseCPU      float64 `json:"baseCPU"`
+	TargetCPU    float64 `json:"targetCPU"`
+	CPUDelta     float64 `json:"cpuDelta"`
+	BaseMemory   float64 `json:"baseMemory"`
+	TargetMemory float64 `json:"targetMemory"`
+	MemoryDelta  float64 `json:"memoryDelta"`
+}
+
+type utilization struct {
+	cpu    float64
+	memory float64
+}
+
+func generateComparison(baseConfig string, base *simulator, targetConfig string, target *simulator) *SchedulerSimulationComparison {
+	basePlacements, baseSimulation := getPlacements(baseConfig, base)
+	targetPlacements, targetSimulation := getPlacements(targetConfig, target)
+
+	r := &SchedulerSimulationComparison{
+		Base:                  baseSimulation,
+		Target:                targetSimulation,
+		PodsPlacedDifferently: make([]PodPlacementDiff, 0),
+		NodeUtilizationDeltas: make([]NodeUtilizationDelta, 0),
+	}
+
+	for key, pod := range basePlacements {
+		targetPod, ok := targetPlacements[key]
+		if !ok || pod.Spec.NodeName == targetPod.Spec.NodeName {
+			continue
+		}
+		r.PodsPlacedDifferently = append(r.PodsPlacedDifferently, PodPlacementDiff{
+			Namespace:  pod.Namespace,
+			Name:       pod.Name,
+			BaseNode:   pod.Spec.NodeName,
+			TargetNode: targetPod.Spec.NodeName,
+		})
+	}
+	sort.Slice(r.PodsPlacedDifferently, func(i, j int) bool {
+		if r.PodsPlacedDifferently[i].Namespace != r.PodsPlacedDifferently[j].Namespace {
+			return r.PodsPlacedDifferently[i].Namespace < r.PodsPlacedDifferently[j].Namespace
+		}
+		return r.PodsPlacedDifferently[i].Name < r.PodsPlacedDifferently[j].Name
+	})
+
+	baseUtilization := getUtilization(base)
+	targetUtilization := getUtilization(target)
+	for node, bu := range baseUtilization {
+		tu := targetUtilization[node]
+		r.NodeUtilizationDeltas = append(r.NodeUtilizationDeltas, NodeUtilizationDelta{
+			NodeName:     node,
+			BaseCPU:      bu.cpu,
+			TargetCPU:    tu.cpu,
+			CPUDelta:     tu.cpu - bu.cpu,
+			BaseMemory:   bu.memory,
+			TargetMemory: tu.memory,
+			MemoryDelta:  tu.memory - bu.memory,
+		})
+	}
+	sort.Slice(r.NodeUtilizationDeltas, func(i, j int) bool {
+		return r.NodeUtilizationDeltas[i].NodeName < r.NodeUtilizationDeltas[j].NodeName
+	})
+
+	return r
+}
+
+func getPlacements(schedulerConfig string, s *simulator) (map[string]*corev1.Pod, ComparedSimulation) {
+	status := s.Status()
+	simulation := ComparedSimulation{
+		SchedulerConfig:   schedulerConfig,
+		StopReason:        status.StopReason,
+		ReplayOrder:       s.replayOrder,
+		UnschedulablePods: make([]string, 0),
+	}
+	if s.replayOrder == replayorder.Random {
+		simulation.ReplaySeed = s.replaySeed
+	}
+
+	placements := make(map[string]*corev1.Pod, len(status.Pods))
+	usedNodes := make(map[string]bool)
+	for i := range status.Pods {
+		pod := &status.Pods[i]
+		key := pod.Namespace + "/" + pod.Name
+		placements[key] = pod
+		if len(pod.Spec.NodeName) == 0 {
+			simulation.UnschedulablePods = append(simulation.UnschedulablePods, key)
+		} else if !utils.IsDaemonsetPod(pod.OwnerReferences) {
+			usedNodes[pod.Spec.NodeName] = true
+		}
+	}
+	sort.Strings(simulation.UnschedulablePods)
+	simulation.NodesUsed = len(usedNodes)
+
+	return placements, simulation
+}
+
+func getUtilization(s *simulator) map[string]utilization {
+	status := s.Status()
+	result := make(map[string]utilization, len(status.Nodes))
+	requests := make(map[string]*framework.Resource, len(status.Nodes))
+	for i := range status.Pods {
+		pod := &status.Pods[i]
+		if len(pod.Spec.NodeName) == 0 {
+			continue
+		}
+		if _, ok := requests[pod.Spec.NodeName]; !ok {
+			requests[pod.Spec.NodeName] = &framework.Resource{}
+		}
+		addResource(requests[pod.Spec.NodeName], utils.ComputePodResourceRequest(pod))
+	}
+
+	for name, node := range status.Nodes {
+		var u utilization
+		if request, ok := requests[name]; ok {
+			u.cpu, u.memory = requestedRatio(request, node.Status.Allocatable)
+		}
+		result[name] = u
+	}
+
+	return result
+}
+
+func (r *SchedulerSimulationComparison) Print(verbose bool, format string) error {
+	switch format {
+	case "json":
+		return utils.PrintJson(r)
+	case "yaml":
+		return utils.PrintYaml(r)
+	case "":
+		comparisonPrettyPrint(r, verbose)
+		return nil
+	default:
+		return fmt.Errorf("output format %q not recognized", format)
+	}
+}
+
+func comparisonPrettyPrint(r *SchedulerSimulationComparison, verbose bool) {
+	fmt.Printf("Base scheduler config: %s\n", r.Base.SchedulerConfig)
+	fmt.Printf("Target scheduler config: %s\n", r.Target.SchedulerConfig)
+	if r.Base.ReplaySeed != 0 {
+		fmt.Printf("Replay order: %s (seed: %d)\n\n", r.Base.ReplayOrder, r.Base.ReplaySeed)
+	} else {
+		fmt.Printf("Replay order: %s\n\n", r.Base.ReplayOrder)
+	}
+
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"", "base", "target"})
+	t.AppendRow(table.Row{"termination reason", r.Base.StopReason, r.Target.StopReason})
+	t.AppendRow(table.Row{"nodes used", r.Base.NodesUsed, r.Target.NodesUsed})
+	t.AppendRow(table.Row{"unschedulable pods", len(r.Base.UnschedulablePods), len(r.Target.UnschedulablePods)})
+	fmt.Println(t.Render())
+	fmt.Printf("\nPods placed differently: %d\n", len(r.PodsPlacedDifferently))
+
+	t = table.NewWriter()
+	t.AppendHeader(table.Row{"node", "base cpu", "target cpu", "cpu delta", "base memory", "target memory", "memory delta"})
+	for _, delta := range r.NodeUtilizationDeltas {
+		// unchanged nodes are only shown in verbose mode
+		if !verbose && delta.CPUDelta == 0 && delta.MemoryDelta == 0 {
+			continue
+		}
+		t.AppendRow(table.Row{delta.NodeName, percentage(delta.BaseCPU), percentage(delta.TargetCPU), signedPercentage(delta.CPUDelta),
+			percentage(delta.BaseMemory), percentage(delta.TargetMemory), signedPercentage(delta.MemoryDelta)})
+	}
+	fmt.Printf("\nNode utilization:\n")
+	fmt.Println(t.Render())
+
+	if !verbose {
+		return
+	}
+
+	if len(r.PodsPlacedDifferently) > 0 {
+		t = table.NewWriter()
+		t.AppendHeader(table.Row{"pod", "base node", "target node"})
+		for _, diff := range r.PodsPlacedDifferently {
+			t.AppendRow(table.Row{diff.Namespace + "/" + diff.Name, nodeOrUnschedulable(diff.BaseNode), nodeOrUnschedulable(diff.TargetNode)})
+		}
+		fmt.Printf("\nPods placed differently:\n")
+		fmt.Println(t.Render())
+	}
+
+	for _, simulation := range []ComparedSimulation{r.Base, r.Target} {
+		if len(simulation.UnschedulablePods) == 0 {
+			continue
+		}
+		fmt.Printf("\nUnschedulable pods with %s(%d):\n", simulation.SchedulerConfig, len(simulation.UnschedulablePods))
+		for _, pod := range simulation.UnschedulablePods {
+			fmt.Printf("- %s\n", pod)
+		}
+	}
+}
+
+func percentage(ratio float64) string {
+	return fmt.Sprintf("%.1f%%", ratio*100)
+}
+
+func signedPercentage(ratio float64) string {
+	return fmt.Sprintf("%+.1f%%", ratio*100)
+}
+
+func nodeOrUnschedulable(node string) string {
+	if len(node) == 0 {
+		return "<unschedulable>"
+	}
+
+	return node
+}
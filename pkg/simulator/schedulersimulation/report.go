@@ -2,7 +2,10 @@ package schedulersimulation
 
 import (
 	"fmt"
+	"math"
+	"sort"
 
+	"github.com/jedib0t/go-pretty/v6/table"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
@@ -14,14 +17,39 @@ type SchedulerSimulationReview struct {
 	UnschedulablePods []corev1.Pod     `json:"unschedulablePods"`
 	Details           []ScheduleDetail `json:"details"`
 	StopReason        string           `json:"stopReason"`
+	// order of the pods entering the scheduling queue
+	ReplayOrder string `json:"replayOrder"`
+	// only available when the replay order is Random
+	ReplaySeed int64            `json:"replaySeed,omitempty"`
+	Metrics    PlacementMetrics `json:"metrics"`
+}
+
+// PlacementMetrics measures the quality of the placement, the used nodes are the nodes running at least
+// one pod which doesn't belong to a DaemonSet
+type PlacementMetrics struct {
+	NodesUsed      int `json:"nodesUsed"`
+	OnlyDSPodNodes int `json:"onlyDSPodNodes"`
+	// minimum number of nodes needed by the placed pods which don't belong to a DaemonSet, computed by
+	// filling the largest nodes first regardless of the shapes of the pods
+	NodesLowerBound int `json:"nodesLowerBound"`
+	// ratio of requested to allocatable resources of all the used nodes
+	CPUPackingEfficiency    float64 `json:"cpuPackingEfficiency"`
+	MemoryPackingEfficiency float64 `json:"memoryPackingEfficiency"`
+	// standard deviation of the requested ratios of the used nodes
+	CPUUtilizationStdDev    float64 `json:"cpuUtilizationStdDev"`
+	MemoryUtilizationStdDev float64 `json:"memoryUtilizationStdDev"`
 }
 
 type ScheduleDetail struct {
 	NodeName        string              `json:"nodeName"`
+	Zone            string              `json:"zone,omitempty"`
 	Replicas        int                 `json:"replicas"`
 	NodeAllocatable corev1.ResourceList `json:"nodeAllocatable"`
 	PodRequest      framework.Resource  `json:"podRequest"`
 	OnlyDSPod       bool                `json:"onlyDSPod"`
+	// ratio of requested to allocatable resources
+	CPURequestedRatio    float64 `json:"cpuRequestedRatio"`
+	MemoryRequestedRatio float64 `json:"memoryRequestedRatio"`
 }
 
 func (r *SchedulerSimulationReview) Print(verbose bool, format string) error {
@@ -30,6 +58,8 @@ func (r *SchedulerSimulationReview) Print(verbose bool, format string) error {
 		return utils.PrintJson(r)
 	case "yaml":
 		return utils.PrintYaml(r)
+	case "html":
+		return utils.PrintHTML(htmlPage(r))
 	case "":
 		prettyPrint(r, verbose)
 		return nil
@@ -39,7 +69,12 @@ func (r *SchedulerSimulationReview) Print(verbose bool, format string) error {
 }
 
 func prettyPrint(r *SchedulerSimulationReview, verbose bool) {
-	fmt.Printf("Termination reason: %s\n\n", r.StopReason)
+	fmt.Printf("Termination reason: %s\n", r.StopReason)
+	if r.ReplaySeed != 0 {
+		fmt.Printf("Replay order: %s (seed: %d)\n\n", r.ReplayOrder, r.ReplaySeed)
+	} else {
+		fmt.Printf("Replay order: %s\n\n", r.ReplayOrder)
+	}
 	if len(r.UnschedulablePods) > 0 {
 		fmt.Printf("Unschedulabel pods(%d):\n", len(r.UnschedulablePods))
 	}
@@ -61,13 +96,87 @@ func prettyPrint(r *SchedulerSimulationReview, verbose bool) {
 		if verbose {
 			var msg string
 			if detail.OnlyDSPod {
-				msg = "Only DaemonSet Pod"
+				msg = ", Only DaemonSet Pod"
 			}
-			fmt.Printf("\t- %v: %v instance(s)%s\n", detail.NodeName, detail.Replicas, msg)
+			fmt.Printf("\t- %v: %v instance(s), cpu %.1f%%, memory %.1f%%%s\n", detail.NodeName, detail.Replicas,
+				detail.CPURequestedRatio*100, detail.MemoryRequestedRatio*100, msg)
 		} else {
 			fmt.Printf("\t- %v\n", detail.NodeName)
 		}
 	}
+
+	fmt.Printf("\nPlacement metrics:\n")
+	fmt.Printf("\t- nodes used: %d (lower bound: %d)\n", r.Metrics.NodesUsed, r.Metrics.NodesLowerBound)
+	fmt.Printf("\t- nodes with only DaemonSet pods: %d\n", r.Metrics.OnlyDSPodNodes)
+	fmt.Printf("\t- packing efficiency: cpu %.1f%%, memory %.1f%%\n", r.Metrics.CPUPackingEfficiency*100, r.Metrics.MemoryPackingEfficiency*100)
+	fmt.Printf("\t- utilization standard deviation: cpu %.1f%%, memory %.1f%%\n", r.Metrics.CPUUtilizationStdDev*100, r.Metrics.MemoryUtilizationStdDev*100)
+}
+
+func htmlPage(r *SchedulerSimulationReview) *utils.HTMLPage {
+	page := &utils.HTMLPage{Title: "Scheduler Simulation"}
+
+	summary := utils.HTMLSection{Title: "Summary"}
+	summary.Paragraphs = append(summary.Paragraphs, fmt.Sprintf("Termination reason: %s", r.StopReason))
+	if r.ReplaySeed != 0 {
+		summary.Paragraphs = append(summary.Paragraphs, fmt.Sprintf("Replay order: %s (seed: %d)", r.ReplayOrder, r.ReplaySeed))
+	} else {
+		summary.Paragraphs = append(summary.Paragraphs, fmt.Sprintf("Replay order: %s", r.ReplayOrder))
+	}
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"metric", "value"})
+	t.AppendRows([]table.Row{
+		{"nodes used", r.Metrics.NodesUsed},
+		{"nodes lower bound", r.Metrics.NodesLowerBound},
+		{"nodes with only DaemonSet pods", r.Metrics.OnlyDSPodNodes},
+		{"unschedulable pods", len(r.UnschedulablePods)},
+		{"cpu packing efficiency", fmt.Sprintf("%.1f%%", r.Metrics.CPUPackingEfficiency*100)},
+		{"memory packing efficiency", fmt.Sprintf("%.1f%%", r.Metrics.MemoryPackingEfficiency*100)},
+		{"cpu utilization standard deviation", fmt.Sprintf("%.1f%%", r.Metrics.CPUUtilizationStdDev*100)},
+		{"memory utilization standard deviation", fmt.Sprintf("%.1f%%", r.Metrics.MemoryUtilizationStdDev*100)},
+	})
+	summary.Table = t
+	page.Sections = append(page.Sections, summary)
+
+	if len(r.UnschedulablePods) > 0 {
+		t := table.NewWriter()
+		t.AppendHeader(table.Row{"pod", "reason"})
+		for i := range r.UnschedulablePods {
+			pod := &r.UnschedulablePods[i]
+			t.AppendRow(table.Row{pod.Namespace + "/" + pod.Name, getUnschedulableReason(pod)})
+		}
+		page.Sections = append(page.Sections, utils.HTMLSection{Title: "Unschedulable pods", Table: t})
+	}
+
+	details := make([]ScheduleDetail, len(r.Details))
+	copy(details, r.Details)
+	sort.Slice(details, func(i, j int) bool {
+		return details[i].NodeName < details[j].NodeName
+	})
+	t = table.NewWriter()
+	t.AppendHeader(table.Row{"node", "zone", "pods", "only daemonset pods"})
+	nodes := make([]utils.NodeUtilization, 0, len(details))
+	for _, detail := range details {
+		t.AppendRow(table.Row{detail.NodeName, detail.Zone, detail.Replicas, detail.OnlyDSPod})
+		nodes = append(nodes, utils.NodeUtilization{
+			Name:                detail.NodeName,
+			Zone:                detail.Zone,
+			Pods:                detail.Replicas,
+			MilliCPURequested:   detail.PodRequest.MilliCPU,
+			MilliCPUAllocatable: detail.NodeAllocatable.Cpu().MilliValue(),
+			MemoryRequested:     detail.PodRequest.Memory,
+			MemoryAllocatable:   detail.NodeAllocatable.Memory().Value(),
+		})
+	}
+	page.Sections = append(page.Sections, utils.HTMLSection{Title: fmt.Sprintf("Pod distribution among %d nodes", len(details)), Table: t})
+	if len(nodes) > 0 {
+		page.Sections = append(page.Sections, utils.HTMLSection{
+			Title: "Node utilization",
+			Table: utils.ZoneTable(nodes),
+			Bars:  nodes,
+		})
+	}
+
+	return page
 }
 
 func getUnschedulableReason(pod *corev1.Pod) string {
@@ -118,7 +227,9 @@ func generateReport(status pkg.Status) *SchedulerSimulationReview {
 			}(nodePodMap[node]),
 		}
 		if node, ok := status.Nodes[node]; ok {
+			detail.Zone = utils.GetNodeZone(&node)
 			detail.NodeAllocatable = node.Status.Allocatable
+			detail.CPURequestedRatio, detail.MemoryRequestedRatio = requestedRatio(&request, node.Status.Allocatable)
 		}
 		details = append(details, detail)
 	}
@@ -127,7 +238,130 @@ func generateReport(status pkg.Status) *SchedulerSimulationReview {
 		UnschedulablePods: unschedulablePods,
 		Details:           details,
 		StopReason:        status.StopReason,
+		Metrics:           getPlacementMetrics(details, status),
+	}
+}
+
+func getPlacementMetrics(details []ScheduleDetail, status pkg.Status) PlacementMetrics {
+	var (
+		metrics                 PlacementMetrics
+		cpuRatios, memoryRatios []float64
+		used, allocatable       framework.Resource
+	)
+
+	for _, detail := range details {
+		if detail.OnlyDSPod {
+			metrics.OnlyDSPodNodes++
+			continue
+		}
+		metrics.NodesUsed++
+		cpuRatios = append(cpuRatios, detail.CPURequestedRatio)
+		memoryRatios = append(memoryRatios, detail.MemoryRequestedRatio)
+		addResource(&used, &detail.PodRequest)
+		allocatable.MilliCPU += detail.NodeAllocatable.Cpu().MilliValue()
+		allocatable.Memory += detail.NodeAllocatable.Memory().Value()
 	}
+
+	if allocatable.MilliCPU > 0 {
+		metrics.CPUPackingEfficiency = float64(used.MilliCPU) / float64(allocatable.MilliCPU)
+	}
+	if allocatable.Memory > 0 {
+		metrics.MemoryPackingEfficiency = float64(used.Memory) / float64(allocatable.Memory)
+	}
+	metrics.CPUUtilizationStdDev = stdDev(cpuRatios)
+	metrics.MemoryUtilizationStdDev = stdDev(memoryRatios)
+	metrics.NodesLowerBound = getNodesLowerBound(status)
+
+	return metrics
+}
+
+// getNodesLowerBound fills the nodes with the largest free resources first until the requests of all the
+// placed pods which don't belong to a DaemonSet fit, the free resources are the allocatable resources minus
+// the requests of DaemonSet pods, cpu and memory are counted separately and the larger count is the bound.
+// The unschedulable pods are left out, so the bound is comparable with the nodes used.
+func getNodesLowerBound(status pkg.Status) int {
+	var request framework.Resource
+	dsRequests := make(map[string]*framework.Resource)
+	for i := range status.Pods {
+		pod := &status.Pods[i]
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		if utils.IsDaemonsetPod(pod.OwnerReferences) {
+			if _, ok := dsRequests[pod.Spec.NodeName]; !ok {
+				dsRequests[pod.Spec.NodeName] = &framework.Resource{}
+			}
+			addResource(dsRequests[pod.Spec.NodeName], utils.ComputePodResourceRequest(pod))
+			continue
+		}
+		addResource(&request, utils.ComputePodResourceRequest(pod))
+	}
+
+	freeCPU := make([]int64, 0, len(status.Nodes))
+	freeMemory := make([]int64, 0, len(status.Nodes))
+	for name, node := range status.Nodes {
+		cpu, memory := node.Status.Allocatable.Cpu().MilliValue(), node.Status.Allocatable.Memory().Value()
+		if dsRequest, ok := dsRequests[name]; ok {
+			cpu -= dsRequest.MilliCPU
+			memory -= dsRequest.Memory
+		}
+		freeCPU = append(freeCPU, cpu)
+		freeMemory = append(freeMemory, memory)
+	}
+
+	cpuBound, memoryBound := nodesToFit(request.MilliCPU, freeCPU), nodesToFit(request.Memory, freeMemory)
+	if cpuBound > memoryBound {
+		return cpuBound
+	}
+
+	return memoryBound
+}
+
+func nodesToFit(request int64, free []int64) int {
+	sort.Slice(free, func(i, j int) bool {
+		return free[i] > free[j]
+	})
+
+	count := 0
+	for _, f := range free {
+		if request <= 0 {
+			break
+		}
+		request -= f
+		count++
+	}
+
+	return count
+}
+
+func requestedRatio(request *framework.Resource, allocatable corev1.ResourceList) (cpu float64, memory float64) {
+	if milliCPU := allocatable.Cpu().MilliValue(); milliCPU > 0 {
+		cpu = float64(request.MilliCPU) / float64(milliCPU)
+	}
+	if bytes := allocatable.Memory().Value(); bytes > 0 {
+		memory = float64(request.Memory) / float64(bytes)
+	}
+
+	return
+}
+
+func stdDev(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+
+	return math.Sqrt(variance / float64(len(values)))
 }
 
 func addResource(source *framework.Resource, res *framework.Resource) {
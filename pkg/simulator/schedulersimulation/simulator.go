@@ -1,52 +1,142 @@
 package schedulersimulation
 
 import (
+	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
+	clientset "k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
+	schedconfig "k8s.io/kubernetes/cmd/kube-scheduler/app/config"
+	kubeschedulerconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
 
 	"github.com/k-cloud-labs/kluster-capacity/app/cmds/schedulersimulation/options"
 	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/admission"
 	"github.com/k-cloud-labs/kluster-capacity/pkg/framework"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/plugins/replayorder"
 	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
 )
 
+// DefaultReplayOrder means the pods are replayed in the order decided by the queue sort plugin of the scheduler configuration
+const DefaultReplayOrder = "Default"
+
+const (
+	AllScheduled = "AllScheduled: %d pod(s) have been scheduled once."
+	AllSucceed   = "AllSucceed: %d pod(s) have been scheduled successfully."
+	Stalled      = "Stalled: no pods are waiting to be scheduled, %d of %d pod(s) can't be scheduled."
+)
+
 type simulator struct {
 	pkg.Framework
 
+	fakeClient    clientset.Interface
+	podLister     corelisters.PodLister
 	exitCondition string
+	replayOrder   string
+	replaySeed    int64
+	// tracer owned by the simulator, nil if it's not traced or the tracer is shared with other simulators
+	tracer *framework.Tracer
 }
 
 func NewSSSimulatorExecutor(conf *options.SchedulerSimulationConfig) (pkg.Simulator, error) {
-	kubeSchedulerConfig, err := utils.BuildKubeSchedulerCompletedConfig(conf.Options.SchedulerConfig, conf.Options.KubeConfig)
+	tracer, err := framework.NewTracer(conf.Options.Trace)
 	if err != nil {
 		return nil, err
 	}
 
-	kubeConfig, err := utils.BuildRestConfig(conf.Options.KubeConfig)
+	s, err := newSimulator(conf, nil, tracer, "")
 	if err != nil {
+		_ = tracer.Close()
 		return nil, err
 	}
+	s.tracer = tracer
 
-	framework, err := framework.NewKubeSchedulerFramework(kubeSchedulerConfig, kubeConfig,
-		framework.WithNodeImages(false),
-		framework.WithScheduledPods(false),
-		framework.WithTerminatingPods(false),
-		framework.WithExcludeNodes(conf.Options.ExcludeNodes),
-		framework.WithSaveTo(conf.Options.SaveTo))
+	return s, nil
+}
+
+// newSimulator creates a simulator, the world and the tracer can be shared by several simulators and are optional
+func newSimulator(conf *options.SchedulerSimulationConfig, world *framework.World, tracer *framework.Tracer, simulation string) (*simulator, error) {
+	var (
+		kubeSchedulerConfig *schedconfig.CompletedConfig
+		err                 error
+	)
+	if conf.KubeSchedulerConfig != nil {
+		kubeSchedulerConfig, err = utils.BuildKubeSchedulerCompletedConfigFromConfiguration(conf.KubeSchedulerConfig.DeepCopy(), conf.Options.KubeConfig)
+	} else {
+		kubeSchedulerConfig, err = utils.BuildKubeSchedulerCompletedConfig(conf.Options.SchedulerConfig, conf.Options.KubeConfig)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// rest config is only needed when the world is initialized from a running cluster
+	var kubeConfig *restclient.Config
+	if len(conf.InitObjs) == 0 && world == nil {
+		kubeConfig, err = utils.BuildRestConfig(conf.Options.KubeConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	chain, err := admission.New(&conf.Options.Options)
 	if err != nil {
 		return nil, err
 	}
 
 	s := &simulator{
-		Framework:     framework,
+		fakeClient:    kubeSchedulerConfig.Client,
 		exitCondition: conf.Options.ExitCondition,
+		replayOrder:   DefaultReplayOrder,
 	}
 
+	opts := append(worldOptions(conf.Options, chain),
+		framework.WithWorld(world),
+		framework.WithSaveTo(conf.Options.SaveTo),
+		framework.WithTracer(tracer, simulation),
+		framework.WithTimeout(conf.Options.Timeout),
+	)
+	if len(conf.Options.ReplayOrder) > 0 {
+		s.replayOrder = conf.Options.ReplayOrder
+		s.replaySeed = conf.Options.ReplaySeed
+		if s.replayOrder == replayorder.Random && s.replaySeed == 0 {
+			s.replaySeed = time.Now().UnixNano()
+		}
+
+		factory, err := replayorder.NewFactory(s.replayOrder, s.replaySeed)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts,
+			framework.WithOutOfTreeRegistry(frameworkruntime.Registry{replayorder.Name: factory}),
+			framework.WithCustomQueueSort(kubeschedulerconfig.PluginSet{
+				Enabled:  []kubeschedulerconfig.Plugin{{Name: replayorder.Name}},
+				Disabled: []kubeschedulerconfig.Plugin{{Name: "*"}},
+			}))
+	}
+
+	// the events of the pods may be missed or never come for unschedulable pods, so the simulation also stops
+	// when the scheduler has nothing to do
+	opts = append(opts, framework.WithQuiescedHook(s.onQuiesced))
+
+	framework, err := framework.NewKubeSchedulerFramework(kubeSchedulerConfig, kubeConfig, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Framework = framework
+	s.podLister = kubeSchedulerConfig.InformerFactory.Core().V1().Pods().Lister()
+
 	err = s.addEventHandlers(kubeSchedulerConfig.InformerFactory)
 	if err != nil {
 		return nil, err
@@ -55,12 +145,79 @@ func NewSSSimulatorExecutor(conf *options.SchedulerSimulationConfig) (pkg.Simula
 	return s, nil
 }
 
+// worldOptions decide which objects are kept in the world and how they are changed
+func worldOptions(opt *options.SchedulerSimulationOptions, chain *admission.Chain) []framework.Option {
+	return []framework.Option{
+		framework.WithAdmission(chain),
+		framework.WithNodeImages(false),
+		framework.WithScheduledPods(false),
+		framework.WithTerminatingPods(false),
+		framework.WithExcludeNodes(opt.ExcludeNodes),
+	}
+}
+
 func (s *simulator) Initialize(objs ...runtime.Object) error {
-	return s.InitTheWorld(objs...)
+	err := s.InitTheWorld(objs...)
+	if err != nil {
+		return err
+	}
+
+	// the replay order only works when all the pods are in the scheduling queue before the scheduler starts
+	podList, err := s.fakeClient.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{ResourceVersion: "0"})
+	if err != nil {
+		return err
+	}
+
+	return wait.PollImmediate(100*time.Millisecond, time.Minute, func() (bool, error) {
+		pods, err := s.podLister.List(labels.Everything())
+		if err != nil {
+			return false, err
+		}
+		return len(pods) >= len(podList.Items), nil
+	})
+}
+
+func (s *simulator) Run() error {
+	defer func() {
+		_ = s.tracer.Close()
+	}()
+
+	return s.Framework.Run()
 }
 
 func (s *simulator) Report() pkg.Printer {
-	return generateReport(s.Status())
+	review := generateReport(s.Status())
+	review.ReplayOrder = s.replayOrder
+	if s.replayOrder == replayorder.Random {
+		review.ReplaySeed = s.replaySeed
+	}
+
+	return review
+}
+
+// onQuiesced stops the simulation when the scheduler has nothing to do, all the pods not bound have been
+// scheduled at least once and are waiting for cluster events which will never come.
+func (s *simulator) onQuiesced() error {
+	pods, err := s.podLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	unscheduled := 0
+	for _, pod := range pods {
+		if len(pod.Spec.NodeName) == 0 {
+			unscheduled++
+		}
+	}
+
+	switch {
+	case unscheduled == 0:
+		return s.Stop(fmt.Sprintf(AllSucceed, len(pods)))
+	case s.exitCondition == options.ExitWhenAllScheduled:
+		return s.Stop(fmt.Sprintf(AllScheduled, len(pods)))
+	default:
+		return s.Stop(fmt.Sprintf(Stalled, unscheduled, len(pods)))
+	}
 }
 
 func (s *simulator) addEventHandlers(informerFactory informers.SharedInformerFactory) (err error) {
@@ -111,10 +268,10 @@ func (s *simulator) addEventHandlers(informerFactory informers.SharedInformerFac
 
 			if s.exitCondition == options.ExitWhenAllScheduled && succeedCount+failedCount == count {
 				stop = true
-				reason = "AllScheduled: %d pod(s) have been scheduled once."
+				reason = AllScheduled
 			} else if s.exitCondition == options.ExitWhenAllSucceed && succeedCount == count {
 				stop = true
-				reason = "AllSucceed: %d pod(s) have been scheduled successfully."
+				reason = AllSucceed
 			}
 
 			if stop {
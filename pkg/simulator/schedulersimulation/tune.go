@@ -0,0 +1,325 @@
+package schedulersimulation
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeschedulerconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config/validation"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/names"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/schedulersimulation/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/admission"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/framework"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/plugins/replayorder"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+// TuneParameters are the parameters of the scheduler configuration changed by one trial
+type TuneParameters struct {
+	// weights of the tuned score plugins
+	Weights []PluginWeight `json:"weights"`
+	// scoring strategy of NodeResourcesFit
+	ScoringStrategy string `json:"scoringStrategy"`
+	// resources scored by NodeResourcesFit, empty means the ones of the base scheduler configuration
+	ScoringResources string `json:"scoringResources,omitempty"`
+	// 0 means the adaptive percentage of the scheduler
+	PercentageOfNodesToScore int32 `json:"percentageOfNodesToScore"`
+}
+
+type PluginWeight struct {
+	Plugin string `json:"plugin"`
+	Weight int32  `json:"weight"`
+}
+
+// tuneSimulator replays the same world under the scheduler configurations generated from the base one
+type tuneSimulator struct {
+	conf       *options.SchedulerSimulationTuneConfig
+	parameters []TuneParameters
+	configs    []*kubeschedulerconfig.KubeSchedulerConfiguration
+	// all the trials must replay the pods in the same order
+	replaySeed int64
+	world      *framework.World
+	reports    []*SchedulerSimulationReview
+	// shared by all the trials
+	tracer *framework.Tracer
+}
+
+func NewSSTuneExecutor(conf *options.SchedulerSimulationTuneConfig) (pkg.Simulator, error) {
+	baseConfig, err := utils.LoadKubeSchedulerConfiguration(conf.Options.SchedulerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	ts := &tuneSimulator{
+		conf:       conf,
+		parameters: generateTuneParameters(conf.Options),
+		replaySeed: conf.Options.ReplaySeed,
+	}
+	if conf.Options.ReplayOrder == replayorder.Random && ts.replaySeed == 0 {
+		ts.replaySeed = time.Now().UnixNano()
+	}
+
+	for i := range ts.parameters {
+		cfg, err := applyTuneParameters(baseConfig, &ts.parameters[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid scheduler configuration of trial %d: %v", i+1, err)
+		}
+		ts.configs = append(ts.configs, cfg)
+	}
+
+	ts.tracer, err = framework.NewTracer(conf.Options.Trace)
+	if err != nil {
+		return nil, err
+	}
+
+	return ts, nil
+}
+
+// generateTuneParameters returns the cartesian product of all the values to try
+func generateTuneParameters(opt *options.SchedulerSimulationTuneOptions) []TuneParameters {
+	weightCombinations := [][]PluginWeight{{}}
+	for _, plugin := range opt.TunedPlugins {
+		next := make([][]PluginWeight, 0, len(weightCombinations)*len(opt.Weights))
+		for _, combination := range weightCombinations {
+			for _, weight := range opt.Weights {
+				weights := append(append([]PluginWeight{}, combination...), PluginWeight{Plugin: plugin, Weight: int32(weight)})
+				next = append(next, weights)
+			}
+		}
+		weightCombinations = next
+	}
+
+	scoringResources := opt.ScoringResources
+	if len(scoringResources) == 0 {
+		scoringResources = []string{""}
+	}
+
+	var parameters []TuneParameters
+	for _, weights := range weightCombinations {
+		for _, strategy := range opt.ScoringStrategies {
+			for _, resources := range scoringResources {
+				for _, percentage := range opt.PercentagesOfNodesToScore {
+					parameters = append(parameters, TuneParameters{
+						Weights:                  weights,
+						ScoringStrategy:          strategy,
+						ScoringResources:         resources,
+						PercentageOfNodesToScore: int32(percentage),
+					})
+				}
+			}
+		}
+	}
+
+	return parameters
+}
+
+// applyTuneParameters returns a copy of the base scheduler configuration changed by the parameters, only the
+// first profile is changed because it's the one used by the simulator
+func applyTuneParameters(base *kubeschedulerconfig.KubeSchedulerConfiguration, parameters *TuneParameters) (*kubeschedulerconfig.KubeSchedulerConfiguration, error) {
+	cfg := base.DeepCopy()
+	if len(cfg.Profiles) == 0 {
+		cfg.Profiles = []kubeschedulerconfig.KubeSchedulerProfile{{}}
+	}
+	profile := &cfg.Profiles[0]
+	if profile.Plugins == nil {
+		profile.Plugins = &kubeschedulerconfig.Plugins{}
+	}
+
+	// weights of the score extension point take precedence over the ones of multi point
+	for _, weight := range parameters.Weights {
+		found := false
+		for i := range profile.Plugins.Score.Enabled {
+			if profile.Plugins.Score.Enabled[i].Name == weight.Plugin {
+				profile.Plugins.Score.Enabled[i].Weight = weight.Weight
+				found = true
+			}
+		}
+		if !found {
+			profile.Plugins.Score.Enabled = append(profile.Plugins.Score.Enabled, kubeschedulerconfig.Plugin{Name: weight.Plugin, Weight: weight.Weight})
+		}
+	}
+
+	var args *kubeschedulerconfig.NodeResourcesFitArgs
+	for i := range profile.PluginConfig {
+		if profile.PluginConfig[i].Name == names.NodeResourcesFit {
+			fitArgs, ok := profile.PluginConfig[i].Args.(*kubeschedulerconfig.NodeResourcesFitArgs)
+			if !ok {
+				return nil, fmt.Errorf("unexpected args type %T of %s", profile.PluginConfig[i].Args, names.NodeResourcesFit)
+			}
+			args = fitArgs
+		}
+	}
+	if args == nil {
+		args = &kubeschedulerconfig.NodeResourcesFitArgs{}
+		profile.PluginConfig = append(profile.PluginConfig, kubeschedulerconfig.PluginConfig{Name: names.NodeResourcesFit, Args: args})
+	}
+	if args.ScoringStrategy == nil {
+		args.ScoringStrategy = &kubeschedulerconfig.ScoringStrategy{}
+	}
+	args.ScoringStrategy.Type = kubeschedulerconfig.ScoringStrategyType(parameters.ScoringStrategy)
+	if len(parameters.ScoringResources) > 0 {
+		resources, err := parseScoringResources(parameters.ScoringResources)
+		if err != nil {
+			return nil, err
+		}
+		args.ScoringStrategy.Resources = resources
+	}
+	if len(args.ScoringStrategy.Resources) == 0 {
+		args.ScoringStrategy.Resources = []kubeschedulerconfig.ResourceSpec{{Name: "cpu", Weight: 1}, {Name: "memory", Weight: 1}}
+	}
+
+	percentage := parameters.PercentageOfNodesToScore
+	cfg.PercentageOfNodesToScore = &percentage
+	profile.PercentageOfNodesToScore = nil
+
+	if err := validation.ValidateKubeSchedulerConfiguration(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// parseScoringResources parses resources like cpu=1,memory=1
+func parseScoringResources(resources string) ([]kubeschedulerconfig.ResourceSpec, error) {
+	var specs []kubeschedulerconfig.ResourceSpec
+	for _, resource := range strings.Split(resources, ",") {
+		kv := strings.Split(strings.TrimSpace(resource), "=")
+		if len(kv) != 2 || len(kv[0]) == 0 {
+			return nil, fmt.Errorf("invalid scoring resource %q, it should be like cpu=1", resource)
+		}
+		weight, err := strconv.ParseInt(kv[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight of scoring resource %q: %v", resource, err)
+		}
+		specs = append(specs, kubeschedulerconfig.ResourceSpec{Name: kv[0], Weight: weight})
+	}
+
+	return specs, nil
+}
+
+// Initialize loads the world only once, the trials are created lazily in Run
+func (ts *tuneSimulator) Initialize(objs ...runtime.Object) error {
+	chain, err := admission.New(&ts.conf.Options.Options)
+	if err != nil {
+		return err
+	}
+	worldOpts := worldOptions(&ts.conf.Options.SchedulerSimulationOptions, chain)
+	if len(objs) > 0 {
+		world, err := framework.NewWorld(objs, worldOpts...)
+		if err != nil {
+			return err
+		}
+		ts.world = world
+		return nil
+	}
+
+	kubeConfig, err := utils.BuildRestConfig(ts.conf.Options.KubeConfig)
+	if err != nil {
+		return err
+	}
+	world, err := framework.NewWorldFromCluster(kubeConfig, worldOpts...)
+	if err != nil {
+		return err
+	}
+	ts.world = world
+
+	return nil
+}
+
+func (ts *tuneSimulator) Run() error {
+	defer func() {
+		_ = ts.tracer.Close()
+	}()
+
+	g := errgroup.Group{}
+	g.SetLimit(ts.conf.Options.Parallelism)
+	reports := make([]*SchedulerSimulationReview, len(ts.configs))
+	for i, cfg := range ts.configs {
+		i := i
+		opt := ts.conf.Options.SchedulerSimulationOptions
+		opt.ReplaySeed = ts.replaySeed
+		ssConf := options.NewSchedulerSimulationConfig(&opt)
+		ssConf.KubeSchedulerConfig = cfg
+		g.Go(func() error {
+			// traces of the trials are told apart by the number of the trial
+			s, err := newSimulator(ssConf, ts.world, ts.tracer, fmt.Sprintf("trial-%d", i+1))
+			if err != nil {
+				return err
+			}
+
+			err = s.Initialize()
+			if err != nil {
+				return err
+			}
+
+			err = s.Run()
+			if err != nil {
+				return err
+			}
+			reports[i] = s.Report().(*SchedulerSimulationReview)
+			return nil
+		})
+	}
+
+	err := g.Wait()
+	if err != nil {
+		return err
+	}
+
+	ts.reports = reports
+
+	return nil
+}
+
+func (ts *tuneSimulator) Report() pkg.Printer {
+	return generateTuneReport(ts.conf.Options, ts.parameters, ts.reports)
+}
+
+// sortTrials ranks the trials without unschedulable pods first, then by the objective and the nodes used
+func sortTrials(trials []TuneTrial, objective string) {
+	sort.SliceStable(trials, func(i, j int) bool {
+		a, b := trials[i], trials[j]
+		if (a.UnschedulablePods == 0) != (b.UnschedulablePods == 0) {
+			return a.UnschedulablePods == 0
+		}
+		if a.UnschedulablePods != b.UnschedulablePods {
+			return a.UnschedulablePods < b.UnschedulablePods
+		}
+		if a.ObjectiveValue != b.ObjectiveValue {
+			if maximizeObjective(objective) {
+				return a.ObjectiveValue > b.ObjectiveValue
+			}
+			return a.ObjectiveValue < b.ObjectiveValue
+		}
+		if a.Metrics.NodesUsed != b.Metrics.NodesUsed {
+			return a.Metrics.NodesUsed < b.Metrics.NodesUsed
+		}
+		return a.Trial < b.Trial
+	})
+}
+
+func maximizeObjective(objective string) bool {
+	return objective == options.ObjectiveCPUPackingEfficiency || objective == options.ObjectiveMemoryPackingEfficiency
+}
+
+func objectiveValue(objective string, metrics PlacementMetrics) float64 {
+	switch objective {
+	case options.ObjectiveCPUPackingEfficiency:
+		return metrics.CPUPackingEfficiency
+	case options.ObjectiveMemoryPackingEfficiency:
+		return metrics.MemoryPackingEfficiency
+	case options.ObjectiveCPUUtilizationStdDev:
+		return metrics.CPUUtilizationStdDev
+	case options.ObjectiveMemoryUtilizationStdDev:
+		return metrics.MemoryUtilizationStdDev
+	default:
+		return float64(metrics.NodesUsed)
+	}
+}
@@ -0,0 +1,155 @@
+package schedulersimulation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/schedulersimulation/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/plugins/replayorder"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+// maxTrialsPrinted is the number of the best trials printed when not in verbose mode
+const maxTrialsPrinted = 10
+
+type SchedulerSimulationTuneReview struct {
+	BaseSchedulerConfig string `json:"baseSchedulerConfig"`
+	Objective           string `json:"objective"`
+	ReplayOrder         string `json:"replayOrder"`
+	ReplaySeed          int64  `json:"replaySeed,omitempty"`
+	// ranked from the best to the worst
+	Trials []TuneTrial `json:"trials"`
+	// the best trial without unschedulable pods, nil if every trial leaves some pods unschedulable
+	Best *TuneTrial `json:"best"`
+}
+
+type TuneTrial struct {
+	// number of the trial, the same as the one in the trace
+	Trial             int              `json:"trial"`
+	Parameters        TuneParameters   `json:"parameters"`
+	StopReason        string           `json:"stopReason"`
+	UnschedulablePods int              `json:"unschedulablePods"`
+	ObjectiveValue    float64          `json:"objectiveValue"`
+	Metrics           PlacementMetrics `json:"metrics"`
+}
+
+func generateTuneReport(opt *options.SchedulerSimulationTuneOptions, parameters []TuneParameters, reports []*SchedulerSimulationReview) *SchedulerSimulationTuneReview {
+	r := &SchedulerSimulationTuneReview{
+		BaseSchedulerConfig: opt.SchedulerConfig,
+		Objective:           opt.Objective,
+		Trials:              make([]TuneTrial, 0, len(reports)),
+	}
+
+	for i, report := range reports {
+		if report == nil {
+			continue
+		}
+		if len(r.ReplayOrder) == 0 {
+			r.ReplayOrder = report.ReplayOrder
+			if r.ReplayOrder == replayorder.Random {
+				r.ReplaySeed = report.ReplaySeed
+			}
+		}
+		r.Trials = append(r.Trials, TuneTrial{
+			Trial:             i + 1,
+			Parameters:        parameters[i],
+			StopReason:        report.StopReason,
+			UnschedulablePods: len(report.UnschedulablePods),
+			ObjectiveValue:    objectiveValue(opt.Objective, report.Metrics),
+			Metrics:           report.Metrics,
+		})
+	}
+
+	sortTrials(r.Trials, opt.Objective)
+	if len(r.Trials) > 0 && r.Trials[0].UnschedulablePods == 0 {
+		best := r.Trials[0]
+		r.Best = &best
+	}
+
+	return r
+}
+
+func (r *SchedulerSimulationTuneReview) Print(verbose bool, format string) error {
+	switch format {
+	case "json":
+		return utils.PrintJson(r)
+	case "yaml":
+		return utils.PrintYaml(r)
+	case "":
+		tunePrettyPrint(r, verbose)
+		return nil
+	default:
+		return fmt.Errorf("output format %q not recognized", format)
+	}
+}
+
+func tunePrettyPrint(r *SchedulerSimulationTuneReview, verbose bool) {
+	fmt.Printf("Base scheduler config: %s\n", r.BaseSchedulerConfig)
+	fmt.Printf("Objective: %s\n", r.Objective)
+	if r.ReplaySeed != 0 {
+		fmt.Printf("Replay order: %s (seed: %d)\n\n", r.ReplayOrder, r.ReplaySeed)
+	} else {
+		fmt.Printf("Replay order: %s\n\n", r.ReplayOrder)
+	}
+
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"rank", "trial", "weights", "scoring strategy", "scoring resources", "nodes to score",
+		"unschedulable pods", "nodes used", "cpu packing", "memory packing", "cpu stddev", "memory stddev"})
+	for i, trial := range r.Trials {
+		// only the best trials are shown if not in verbose mode
+		if !verbose && i >= maxTrialsPrinted {
+			break
+		}
+		t.AppendRow(table.Row{i + 1, trial.Trial, formatWeights(trial.Parameters.Weights), trial.Parameters.ScoringStrategy,
+			formatScoringResources(trial.Parameters.ScoringResources), formatPercentage(trial.Parameters.PercentageOfNodesToScore),
+			trial.UnschedulablePods, trial.Metrics.NodesUsed, percentage(trial.Metrics.CPUPackingEfficiency),
+			percentage(trial.Metrics.MemoryPackingEfficiency), percentage(trial.Metrics.CPUUtilizationStdDev),
+			percentage(trial.Metrics.MemoryUtilizationStdDev)})
+	}
+	fmt.Printf("Trials(%d):\n", len(r.Trials))
+	fmt.Println(t.Render())
+	if !verbose && len(r.Trials) > maxTrialsPrinted {
+		fmt.Printf("%d more trial(s) are hidden, use --verbose to show all of them\n", len(r.Trials)-maxTrialsPrinted)
+	}
+
+	if r.Best == nil {
+		fmt.Printf("\nNo configuration can schedule all the pods.\n")
+		return
+	}
+
+	fmt.Printf("\nBest configuration (trial %d):\n", r.Best.Trial)
+	for _, weight := range r.Best.Parameters.Weights {
+		fmt.Printf("\t- score weight of %s: %d\n", weight.Plugin, weight.Weight)
+	}
+	fmt.Printf("\t- NodeResourcesFit scoring strategy: %s, resources: %s\n", r.Best.Parameters.ScoringStrategy,
+		formatScoringResources(r.Best.Parameters.ScoringResources))
+	fmt.Printf("\t- percentageOfNodesToScore: %s\n", formatPercentage(r.Best.Parameters.PercentageOfNodesToScore))
+	fmt.Printf("\t- nodes used: %d (lower bound: %d)\n", r.Best.Metrics.NodesUsed, r.Best.Metrics.NodesLowerBound)
+}
+
+func formatWeights(weights []PluginWeight) string {
+	items := make([]string, 0, len(weights))
+	for _, weight := range weights {
+		items = append(items, fmt.Sprintf("%s=%d", weight.Plugin, weight.Weight))
+	}
+
+	return strings.Join(items, "\n")
+}
+
+func formatScoringResources(resources string) string {
+	if len(resources) == 0 {
+		return "<base>"
+	}
+
+	return resources
+}
+
+func formatPercentage(percentage int32) string {
+	if percentage == 0 {
+		return "<adaptive>"
+	}
+
+	return fmt.Sprintf("%d%%", percentage)
+}
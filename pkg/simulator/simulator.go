@@ -0,0 +1,22 @@
+package simulator
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+)
+
+// Run runs the simulator in the same way as the commands do and returns its review
+func Run(s pkg.Simulator, objs []runtime.Object) (pkg.Printer, error) {
+	err := s.Initialize(objs...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Report(), nil
+}
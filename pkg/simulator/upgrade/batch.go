@@ -0,0 +1,355 @@
+package upgrade
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/uuid"
+
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+// replaceBatch replaces the next maxSurge + maxUnavailable old nodes the same way as the surge upgrade of the
+// managed node pools: the surge nodes are added first, then the old nodes are drained and removed, at last the
+// nodes unavailable during the batch are added
+func (s *simulator) replaceBatch(review *BatchReview) error {
+	size := s.maxSurge + s.maxUnavailable
+	if s.next+size > len(s.oldNodes) {
+		size = len(s.oldNodes) - s.next
+	}
+	names := s.oldNodes[s.next : s.next+size]
+	s.next += size
+
+	// the old nodes are read before they are changed, so that the new nodes are copied from them as they are
+	var oldNodes []*corev1.Node
+	for _, name := range names {
+		node, err := s.fakeClient.CoreV1().Nodes().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		oldNodes = append(oldNodes, node)
+	}
+
+	surge := s.maxSurge
+	if surge > len(oldNodes) {
+		surge = len(oldNodes)
+	}
+	for _, node := range oldNodes[:surge] {
+		if err := s.addNode(node, review); err != nil {
+			return err
+		}
+	}
+
+	for _, node := range oldNodes {
+		if err := s.drainNode(node.Name, review); err != nil {
+			return err
+		}
+	}
+	// the evicted pods are created again but not scheduled yet, it's when the PDBs are checked by the eviction
+	if err := s.checkPDBs(review); err != nil {
+		return err
+	}
+
+	for _, node := range oldNodes {
+		if err := s.removeNode(node.Name, review); err != nil {
+			return err
+		}
+	}
+	for _, node := range oldNodes[surge:] {
+		if err := s.addNode(node, review); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addNode adds a new node copied from the old one with the DaemonSet pods of it
+func (s *simulator) addNode(old *corev1.Node, review *BatchReview) error {
+	node := old.DeepCopy()
+	node.ObjectMeta = metav1.ObjectMeta{
+		Name:        old.Name + "-upgraded",
+		UID:         uuid.NewUUID(),
+		Labels:      node.Labels,
+		Annotations: node.Annotations,
+	}
+	if node.Labels == nil {
+		node.Labels = make(map[string]string)
+	}
+	node.Labels[corev1.LabelHostname] = node.Name
+	for k, v := range s.nodeLabels {
+		node.Labels[k] = v
+	}
+	node.Spec.Unschedulable = false
+	node.Spec.ProviderID = ""
+	if _, err := s.fakeClient.CoreV1().Nodes().Create(context.TODO(), node, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	review.NodesAdded = append(review.NodesAdded, node.Name)
+
+	pods, err := s.podsOnNode(old.Name)
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods {
+		if !utils.IsDaemonsetPod(pod.OwnerReferences) {
+			continue
+		}
+		pod.Name = pod.Name + "-upgraded"
+		pod.UID = uuid.NewUUID()
+		pod.ResourceVersion = ""
+		pod.Spec.NodeName = node.Name
+		if _, err := s.fakeClient.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// drainNode cordons the node and evicts the pods on it the same way as cc, the evicted pods are created again
+// to be scheduled if they are managed by a controller, otherwise they are lost
+func (s *simulator) drainNode(nodeName string, review *BatchReview) error {
+	if err := utils.CordonNode(s.fakeClient, nodeName); err != nil {
+		return err
+	}
+
+	pods, err := s.podsOnNode(nodeName)
+	if err != nil {
+		return err
+	}
+	deleted, err := utils.DeletePodsToReschedule(s.fakeClient, pods)
+	if err != nil {
+		return err
+	}
+
+	for _, pod := range deleted {
+		// the mirror pods are gone with the node
+		if utils.IsMirrorPod(pod) {
+			continue
+		}
+		review.PodsEvicted++
+
+		if metav1.GetControllerOf(pod) == nil {
+			review.LostPods = append(review.LostPods, pod.Namespace+"/"+pod.Name)
+			continue
+		}
+		if err := s.CreatePod(utils.InitPod(pod)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeNode deletes the drained node with the DaemonSet pods left on it
+func (s *simulator) removeNode(nodeName string, review *BatchReview) error {
+	pods, err := s.podsOnNode(nodeName)
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods {
+		err := s.fakeClient.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	if err := s.fakeClient.CoreV1().Nodes().Delete(context.TODO(), nodeName, metav1.DeleteOptions{}); err != nil {
+		return err
+	}
+	review.NodesRemoved = append(review.NodesRemoved, nodeName)
+
+	return nil
+}
+
+// checkPDBs compares the healthy pods of each PDB after the evictions with the ones it requires, every PDB with
+// fewer healthy pods than desired is violated by the batch
+func (s *simulator) checkPDBs(review *BatchReview) error {
+	pdbList, err := s.fakeClient.PolicyV1().PodDisruptionBudgets(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for i := range pdbList.Items {
+		pdb := &pdbList.Items[i]
+		pods, err := s.podsOfPDB(pdb)
+		if err != nil {
+			return err
+		}
+		expected, err := s.expectedPods(pods)
+		if err != nil {
+			return err
+		}
+		desired, err := desiredHealthy(pdb, expected)
+		if err != nil {
+			return err
+		}
+
+		healthy := 0
+		for _, pod := range pods {
+			if len(pod.Spec.NodeName) > 0 {
+				healthy++
+			}
+		}
+		if healthy < desired {
+			review.PDBViolations = append(review.PDBViolations, PDBViolation{
+				PDB:            pdb.Namespace + "/" + pdb.Name,
+				Expected:       expected,
+				DesiredHealthy: desired,
+				CurrentHealthy: healthy,
+			})
+		}
+	}
+
+	return nil
+}
+
+// podsOfPDB returns the pods selected by the PDB which are not being deleted, including the pending ones
+func (s *simulator) podsOfPDB(pdb *policyv1.PodDisruptionBudget) ([]*corev1.Pod, error) {
+	selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	podList, err := s.fakeClient.CoreV1().Pods(pdb.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var pods []*corev1.Pod
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.DeletionTimestamp == nil && selector.Matches(labels.Set(pod.Labels)) {
+			pods = append(pods, pod)
+		}
+	}
+
+	return pods, nil
+}
+
+// expectedPods returns the number of pods expected by the PDB the same way as the disruption controller: the sum
+// of the replicas of the controllers of the pods, the pods without a scalable controller count for themselves
+func (s *simulator) expectedPods(pods []*corev1.Pod) (int, error) {
+	expected := 0
+	controllers := make(map[types.UID]struct{})
+	for _, pod := range pods {
+		ref := metav1.GetControllerOf(pod)
+		if ref == nil {
+			expected++
+			continue
+		}
+
+		uid, replicas, found, err := s.scaleOf(pod.Namespace, ref)
+		if err != nil {
+			return 0, err
+		}
+		if !found {
+			expected++
+			continue
+		}
+		if _, ok := controllers[uid]; !ok {
+			controllers[uid] = struct{}{}
+			expected += int(replicas)
+		}
+	}
+
+	return expected, nil
+}
+
+// scaleOf returns the uid and the replicas of the scalable controller, the replicas of the ReplicaSets of a
+// Deployment are the ones of the Deployment
+func (s *simulator) scaleOf(namespace string, ref *metav1.OwnerReference) (types.UID, int32, bool, error) {
+	var (
+		meta     metav1.Object
+		replicas *int32
+		err      error
+	)
+	switch ref.Kind {
+	case "ReplicaSet":
+		var rs *appsv1.ReplicaSet
+		rs, err = s.fakeClient.AppsV1().ReplicaSets(namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+		if err == nil {
+			if owner := metav1.GetControllerOf(rs); owner != nil && owner.Kind == "Deployment" {
+				return s.scaleOf(namespace, owner)
+			}
+			meta, replicas = rs, rs.Spec.Replicas
+		}
+	case "Deployment":
+		var deploy *appsv1.Deployment
+		deploy, err = s.fakeClient.AppsV1().Deployments(namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+		if err == nil {
+			meta, replicas = deploy, deploy.Spec.Replicas
+		}
+	case "StatefulSet":
+		var sts *appsv1.StatefulSet
+		sts, err = s.fakeClient.AppsV1().StatefulSets(namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+		if err == nil {
+			meta, replicas = sts, sts.Spec.Replicas
+		}
+	case "ReplicationController":
+		var rc *corev1.ReplicationController
+		rc, err = s.fakeClient.CoreV1().ReplicationControllers(namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+		if err == nil {
+			meta, replicas = rc, rc.Spec.Replicas
+		}
+	default:
+		return "", 0, false, nil
+	}
+	if apierrors.IsNotFound(err) {
+		return "", 0, false, nil
+	}
+	if err != nil {
+		return "", 0, false, err
+	}
+
+	// the replicas default to 1
+	if replicas == nil {
+		return meta.GetUID(), 1, true, nil
+	}
+	return meta.GetUID(), *replicas, true, nil
+}
+
+// desiredHealthy returns the minimum number of healthy pods required by the PDB the same way as the disruption
+// controller
+func desiredHealthy(pdb *policyv1.PodDisruptionBudget, expected int) (int, error) {
+	switch {
+	case pdb.Spec.MinAvailable != nil:
+		return intstr.GetScaledValueFromIntOrPercent(pdb.Spec.MinAvailable, expected, true)
+	case pdb.Spec.MaxUnavailable != nil:
+		maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(pdb.Spec.MaxUnavailable, expected, true)
+		if err != nil {
+			return 0, err
+		}
+		if maxUnavailable > expected {
+			return 0, nil
+		}
+		return expected - maxUnavailable, nil
+	default:
+		return 0, fmt.Errorf("PDB %s/%s has neither minAvailable nor maxUnavailable", pdb.Namespace, pdb.Name)
+	}
+}
+
+func (s *simulator) podsOnNode(nodeName string) ([]*corev1.Pod, error) {
+	podList, err := s.fakeClient.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var pods []*corev1.Pod
+	for i := range podList.Items {
+		if podList.Items[i].Spec.NodeName == nodeName {
+			pods = append(pods, podList.Items[i].DeepCopy())
+		}
+	}
+
+	return pods, nil
+}
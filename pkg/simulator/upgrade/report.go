@@ -0,0 +1,106 @@
+package upgrade
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+type UpgradeReview struct {
+	Pool           string        `json:"pool"`
+	MaxSurge       int           `json:"maxSurge"`
+	MaxUnavailable int           `json:"maxUnavailable"`
+	Batches        []BatchReview `json:"batches"`
+	StopReason     string        `json:"stopReason"`
+}
+
+// BatchReview is what a batch of the upgrade changed in the world and the disruption it caused
+type BatchReview struct {
+	Batch        int      `json:"batch"`
+	Error        string   `json:"error,omitempty"`
+	NodesAdded   []string `json:"nodesAdded,omitempty"`
+	NodesRemoved []string `json:"nodesRemoved,omitempty"`
+	PodsEvicted  int      `json:"podsEvicted"`
+	// pods evicted without a controller to create them again
+	LostPods      []string       `json:"lostPods,omitempty"`
+	PDBViolations []PDBViolation `json:"pdbViolations,omitempty"`
+	// pods left pending by the batch after the scheduler settled
+	UnschedulablePods []string `json:"unschedulablePods,omitempty"`
+}
+
+// PDBViolation is a PDB whose healthy pods are less than it requires after the evictions of a batch
+type PDBViolation struct {
+	PDB string `json:"pdb"`
+	// pods expected by the PDB, the replicas of the controllers of its pods
+	Expected       int `json:"expected"`
+	DesiredHealthy int `json:"desiredHealthy"`
+	CurrentHealthy int `json:"currentHealthy"`
+}
+
+// Disrupted returns true if the batch violated any PDB or left any pod pending
+func (b *BatchReview) Disrupted() bool {
+	return len(b.Error) > 0 || len(b.PDBViolations) > 0 || len(b.UnschedulablePods) > 0
+}
+
+func (r *UpgradeReview) Print(verbose bool, format string) error {
+	switch format {
+	case "json":
+		return utils.PrintJson(r)
+	case "yaml":
+		return utils.PrintYaml(r)
+	case "":
+		upgradePrettyPrint(r, verbose)
+		return nil
+	default:
+		return fmt.Errorf("output format %q not recognized", format)
+	}
+}
+
+func upgradePrettyPrint(r *UpgradeReview, verbose bool) {
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"batch", "added", "removed", "evicted", "lost", "pdb violations", "unschedulable", "result"})
+	disrupted := 0
+	for i := range r.Batches {
+		b := &r.Batches[i]
+		result := "ok"
+		if len(b.Error) > 0 {
+			result = "failed: " + b.Error
+		} else if b.Disrupted() {
+			result = "disrupted"
+		}
+		if b.Disrupted() {
+			disrupted++
+		}
+		t.AppendRow(table.Row{b.Batch, len(b.NodesAdded), len(b.NodesRemoved), b.PodsEvicted, len(b.LostPods), len(b.PDBViolations), len(b.UnschedulablePods), result})
+	}
+	fmt.Println(t.Render())
+
+	if verbose {
+		for i := range r.Batches {
+			b := &r.Batches[i]
+			fmt.Printf("\nBatch %d:\n", b.Batch)
+			if len(b.NodesAdded) > 0 {
+				fmt.Printf("- added: %s\n", strings.Join(b.NodesAdded, ", "))
+			}
+			if len(b.NodesRemoved) > 0 {
+				fmt.Printf("- removed: %s\n", strings.Join(b.NodesRemoved, ", "))
+			}
+			for _, pod := range b.LostPods {
+				fmt.Printf("- lost: %s\n", pod)
+			}
+			for _, v := range b.PDBViolations {
+				fmt.Printf("- pdb violated: %s, %d healthy of %d desired\n", v.PDB, v.CurrentHealthy, v.DesiredHealthy)
+			}
+			for _, pod := range b.UnschedulablePods {
+				fmt.Printf("- unschedulable: %s\n", pod)
+			}
+		}
+	}
+
+	fmt.Printf("\nPool %s upgraded with max surge %d and max unavailable %d, %d of %d batch(es) disrupted.\n",
+		r.Pool, r.MaxSurge, r.MaxUnavailable, disrupted, len(r.Batches))
+	fmt.Printf("Termination reason: %s\n", r.StopReason)
+}
@@ -0,0 +1,207 @@
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/upgrade/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/admission"
+	pkgframework "github.com/k-cloud-labs/kluster-capacity/pkg/framework"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+const (
+	AllReplaced = "AllReplaced: %d node(s) have been replaced in %d batch(es)"
+	FailedBatch = "FailedBatch: batch %d failed: %v"
+)
+
+// simulator replaces the nodes of a pool batch by batch, each batch waits for the scheduler to settle, i.e. to
+// have nothing to do, before the next one
+type simulator struct {
+	pkg.Framework
+
+	fakeClient     clientset.Interface
+	pool           labels.Selector
+	maxSurge       int
+	maxUnavailable int
+	nodeLabels     map[string]string
+	excludeNodes   sets.Set[string]
+
+	// names of the nodes to replace in the order of their replacement
+	oldNodes []string
+
+	// only accessed by the quiesced hook, so no lock is needed
+	next int
+	// the pods pending before the upgrade, they aren't counted as unschedulable by the batches
+	pending sets.Set[string]
+	// the batch waiting for the scheduler to settle
+	current *BatchReview
+	batches []BatchReview
+	err     error
+}
+
+// NewUpgradeSimulatorExecutor create an upgrade simulator which is completely independent of apiserver so no need
+// for kubeconfig nor for apiserver url
+func NewUpgradeSimulatorExecutor(conf *options.UpgradeConfig) (pkg.Simulator, error) {
+	kubeSchedulerConfig, err := utils.BuildKubeSchedulerCompletedConfig(conf.Options.SchedulerConfig, conf.Options.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := admission.New(&conf.Options.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := labels.Parse(conf.Options.Pool)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pool selector %q: %v", conf.Options.Pool, err)
+	}
+
+	s := &simulator{
+		fakeClient:     kubeSchedulerConfig.Client,
+		pool:           pool,
+		maxSurge:       conf.Options.MaxSurge,
+		maxUnavailable: conf.Options.MaxUnavailable,
+		nodeLabels:     conf.Options.NodeLabels,
+		excludeNodes:   sets.New[string](conf.Options.ExcludeNodes...),
+	}
+
+	// the world is always initialized from the objects passed to Initialize, so no rest config is needed
+	framework, err := pkgframework.NewKubeSchedulerFramework(kubeSchedulerConfig, nil,
+		pkgframework.WithAdmission(chain),
+		pkgframework.WithExcludeNodes(conf.Options.ExcludeNodes),
+		pkgframework.WithQuiescedHook(s.onQuiesced))
+	if err != nil {
+		return nil, err
+	}
+
+	s.Framework = framework
+
+	return s, nil
+}
+
+func (s *simulator) Initialize(objs ...runtime.Object) error {
+	err := s.InitTheWorld(objs...)
+	if err != nil {
+		return err
+	}
+
+	nodeList, err := s.fakeClient.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{ResourceVersion: "0"})
+	if err != nil {
+		return err
+	}
+	for _, node := range nodeList.Items {
+		if !s.excludeNodes.Has(node.Name) && s.pool.Matches(labels.Set(node.Labels)) {
+			s.oldNodes = append(s.oldNodes, node.Name)
+		}
+	}
+	if len(s.oldNodes) == 0 {
+		return fmt.Errorf("no nodes match pool selector %s", s.pool.String())
+	}
+	sort.Strings(s.oldNodes)
+
+	// the pods pending in the world are scheduled by the simulation as well
+	podList, err := s.fakeClient.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{ResourceVersion: "0"})
+	if err != nil {
+		return err
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if len(pod.Spec.NodeName) > 0 {
+			continue
+		}
+		if err := s.fakeClient.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.Name, metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+		if err := s.CreatePod(utils.InitPod(pod)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Run returns an error if any batch failed
+func (s *simulator) Run() error {
+	err := s.Framework.Run()
+	if err != nil {
+		return err
+	}
+
+	return s.err
+}
+
+func (s *simulator) Report() pkg.Printer {
+	return &UpgradeReview{
+		Pool:           s.pool.String(),
+		MaxSurge:       s.maxSurge,
+		MaxUnavailable: s.maxUnavailable,
+		Batches:        s.batches,
+		StopReason:     s.Status().StopReason,
+	}
+}
+
+// onQuiesced checks the batch the scheduler has settled for and replaces the next one, the simulation stops
+// when all the nodes of the pool are replaced
+func (s *simulator) onQuiesced() error {
+	unschedulable, err := s.unschedulablePods()
+	if err != nil {
+		return err
+	}
+
+	if s.current != nil {
+		// the pods pending before the batch are counted by the previous ones
+		for _, pod := range unschedulable {
+			if !s.pending.Has(pod) {
+				s.current.UnschedulablePods = append(s.current.UnschedulablePods, pod)
+			}
+		}
+		s.batches = append(s.batches, *s.current)
+		s.current = nil
+	}
+	s.pending = sets.New[string](unschedulable...)
+
+	if s.next >= len(s.oldNodes) {
+		return s.Stop(fmt.Sprintf(AllReplaced, len(s.oldNodes), len(s.batches)))
+	}
+
+	review := &BatchReview{Batch: len(s.batches) + 1}
+	klog.V(2).InfoS("Replace batch", "batch", review.Batch)
+	if err := s.replaceBatch(review); err != nil {
+		review.Error = err.Error()
+		s.batches = append(s.batches, *review)
+		s.err = fmt.Errorf("batch %d failed: %v", review.Batch, err)
+		return s.Stop(fmt.Sprintf(FailedBatch, review.Batch, err))
+	}
+	s.current = review
+
+	return nil
+}
+
+// unschedulablePods returns the pods not bound, the scheduler has settled so they can't be scheduled
+func (s *simulator) unschedulablePods() ([]string, error) {
+	podList, err := s.fakeClient.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{ResourceVersion: "0"})
+	if err != nil {
+		return nil, err
+	}
+
+	var pods []string
+	for _, pod := range podList.Items {
+		if len(pod.Spec.NodeName) == 0 {
+			pods = append(pods, pod.Namespace+"/"+pod.Name)
+		}
+	}
+	sort.Strings(pods)
+
+	return pods, nil
+}
@@ -0,0 +1,354 @@
+package whatif
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/utils/pointer"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/whatif/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+func (s *simulator) nextName(prefix string) string {
+	s.created++
+	return fmt.Sprintf("%s-whatif-%d", prefix, s.created)
+}
+
+func (s *simulator) addNodes(add *options.AddNodes, review *StepReview) error {
+	like, err := s.fakeClient.CoreV1().Nodes().Get(context.TODO(), add.Like, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	// the DaemonSet pods of the node copied run on the new nodes as well
+	pods, err := s.podsOnNode(like.Name)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < add.Count; i++ {
+		node := like.DeepCopy()
+		node.ObjectMeta = metav1.ObjectMeta{
+			Name:        s.nextName(like.Name),
+			UID:         uuid.NewUUID(),
+			Labels:      node.Labels,
+			Annotations: node.Annotations,
+		}
+		if node.Labels == nil {
+			node.Labels = make(map[string]string)
+		}
+		node.Labels[corev1.LabelHostname] = node.Name
+		for k, v := range add.Labels {
+			node.Labels[k] = v
+		}
+		node.Spec.Unschedulable = false
+		node.Spec.ProviderID = ""
+		if _, err := s.fakeClient.CoreV1().Nodes().Create(context.TODO(), node, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		review.Nodes = append(review.Nodes, node.Name)
+
+		for _, pod := range pods {
+			if !utils.IsDaemonsetPod(pod.OwnerReferences) {
+				continue
+			}
+			pod = pod.DeepCopy()
+			pod.Name = s.nextName(pod.Name)
+			pod.UID = uuid.NewUUID()
+			pod.ResourceVersion = ""
+			pod.Spec.NodeName = node.Name
+			if _, err := s.fakeClient.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+				return err
+			}
+			review.PodsCreated++
+		}
+	}
+
+	return nil
+}
+
+func (s *simulator) drainNodes(drain *options.DrainNodes, review *StepReview) error {
+	nodes, err := s.selectNodes(drain.NodeSelection)
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes {
+		// cordon the node first so that the evicted pods don't come back
+		node.Spec.Unschedulable = true
+		if _, err := s.fakeClient.CoreV1().Nodes().Update(context.TODO(), node, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		review.Nodes = append(review.Nodes, node.Name)
+
+		pods, err := s.podsOnNode(node.Name)
+		if err != nil {
+			return err
+		}
+		for _, pod := range pods {
+			// the DaemonSet and mirror pods are ignored by drain, they are gone with the node though
+			if utils.IsDaemonsetPod(pod.OwnerReferences) || utils.IsMirrorPod(pod) {
+				if drain.Delete {
+					if err := s.deletePod(pod); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if err := s.evict(pod, review); err != nil {
+				return err
+			}
+		}
+
+		if drain.Delete {
+			if err := s.fakeClient.CoreV1().Nodes().Delete(context.TODO(), node.Name, metav1.DeleteOptions{}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *simulator) taintNodes(taint *options.TaintNodes, review *StepReview) error {
+	nodes, err := s.selectNodes(taint.NodeSelection)
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes {
+		taints := []corev1.Taint{taint.Taint}
+		for _, t := range node.Spec.Taints {
+			if !t.MatchTaint(&taint.Taint) {
+				taints = append(taints, t)
+			}
+		}
+		node.Spec.Taints = taints
+		if _, err := s.fakeClient.CoreV1().Nodes().Update(context.TODO(), node, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		review.Nodes = append(review.Nodes, node.Name)
+
+		if taint.Taint.Effect != corev1.TaintEffectNoExecute {
+			continue
+		}
+		pods, err := s.podsOnNode(node.Name)
+		if err != nil {
+			return err
+		}
+		for _, pod := range pods {
+			if utils.IsMirrorPod(pod) || tolerates(pod, &taint.Taint) {
+				continue
+			}
+			if err := s.evict(pod, review); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *simulator) scale(scale *options.Scale, review *StepReview) error {
+	namespace := scale.Namespace
+	if len(namespace) == 0 {
+		namespace = metav1.NamespaceDefault
+	}
+
+	w, err := s.getWorkload(scale.Kind, namespace, scale.Name)
+	if err != nil {
+		return err
+	}
+
+	pods, err := s.podsOfWorkload(scale.Kind, namespace, scale.Name)
+	if err != nil {
+		return err
+	}
+
+	current := int32(len(pods))
+	if scale.Replicas > current {
+		template := &corev1.Pod{
+			ObjectMeta: *w.template.ObjectMeta.DeepCopy(),
+			Spec:       *w.template.Spec.DeepCopy(),
+		}
+		template.Namespace = namespace
+		template.OwnerReferences = []metav1.OwnerReference{
+			{
+				APIVersion: appsv1.SchemeGroupVersion.String(),
+				Kind:       scale.Kind,
+				Name:       scale.Name,
+				Controller: pointer.Bool(true),
+			},
+		}
+		priority, err := utils.GetPodPriority(s.fakeClient, template)
+		if err != nil {
+			return err
+		}
+		template.Spec.Priority = &priority
+
+		for i := current; i < scale.Replicas; i++ {
+			template.Name = s.nextName(scale.Name)
+			if err := s.CreatePod(utils.InitPod(template)); err != nil {
+				return err
+			}
+			review.PodsCreated++
+		}
+	} else {
+		// the pending pods are deleted first, then the newest ones as the ReplicaSet controller does
+		sort.SliceStable(pods, func(i, j int) bool {
+			if (len(pods[i].Spec.NodeName) == 0) != (len(pods[j].Spec.NodeName) == 0) {
+				return len(pods[i].Spec.NodeName) == 0
+			}
+			return pods[j].CreationTimestamp.Before(&pods[i].CreationTimestamp)
+		})
+		for _, pod := range pods[:current-scale.Replicas] {
+			if err := s.deletePod(pod); err != nil {
+				return err
+			}
+			review.PodsDeleted++
+		}
+	}
+
+	return w.setReplicas(scale.Replicas)
+}
+
+func (s *simulator) deleteNamespace(namespace string, review *StepReview) error {
+	_, err := s.fakeClient.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	podList, err := s.fakeClient.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range podList.Items {
+		if err := s.deletePod(&podList.Items[i]); err != nil {
+			return err
+		}
+		review.PodsDeleted++
+	}
+
+	// the workloads are deleted as well, so that they can't be scaled by the following steps
+	deployList, err := s.fakeClient.AppsV1().Deployments(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, deploy := range deployList.Items {
+		if err := s.fakeClient.AppsV1().Deployments(namespace).Delete(context.TODO(), deploy.Name, metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+	}
+	rsList, err := s.fakeClient.AppsV1().ReplicaSets(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, rs := range rsList.Items {
+		if err := s.fakeClient.AppsV1().ReplicaSets(namespace).Delete(context.TODO(), rs.Name, metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+	}
+	stsList, err := s.fakeClient.AppsV1().StatefulSets(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, sts := range stsList.Items {
+		if err := s.fakeClient.AppsV1().StatefulSets(namespace).Delete(context.TODO(), sts.Name, metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+	}
+
+	return s.fakeClient.CoreV1().Namespaces().Delete(context.TODO(), namespace, metav1.DeleteOptions{})
+}
+
+func (s *simulator) selectNodes(selection options.NodeSelection) ([]*corev1.Node, error) {
+	var nodes []*corev1.Node
+	if len(selection.Nodes) > 0 {
+		for _, name := range selection.Nodes {
+			node, err := s.fakeClient.CoreV1().Nodes().Get(context.TODO(), name, metav1.GetOptions{})
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node)
+		}
+
+		return nodes, nil
+	}
+
+	selector, err := labels.Parse(selection.Selector)
+	if err != nil {
+		return nil, err
+	}
+	nodeList, err := s.fakeClient.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range nodeList.Items {
+		if selector.Matches(labels.Set(nodeList.Items[i].Labels)) {
+			nodes = append(nodes, nodeList.Items[i].DeepCopy())
+		}
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no nodes match selector %s", selection.Selector)
+	}
+
+	return nodes, nil
+}
+
+func (s *simulator) podsOnNode(nodeName string) ([]*corev1.Pod, error) {
+	podList, err := s.fakeClient.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var pods []*corev1.Pod
+	for i := range podList.Items {
+		if podList.Items[i].Spec.NodeName == nodeName {
+			pods = append(pods, podList.Items[i].DeepCopy())
+		}
+	}
+
+	return pods, nil
+}
+
+// evict deletes the pod, the pod is created again to be scheduled if it's managed by a controller, which
+// would recreate it, otherwise it's lost
+func (s *simulator) evict(pod *corev1.Pod, review *StepReview) error {
+	if err := s.deletePod(pod); err != nil {
+		return err
+	}
+	review.PodsEvicted++
+
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil || ref.Kind == "DaemonSet" || ref.Kind == "Node" {
+		review.PodsLost++
+		return nil
+	}
+
+	return s.CreatePod(utils.InitPod(pod))
+}
+
+func (s *simulator) deletePod(pod *corev1.Pod) error {
+	err := s.fakeClient.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.Name, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func tolerates(pod *corev1.Pod, taint *corev1.Taint) bool {
+	for i := range pod.Spec.Tolerations {
+		if pod.Spec.Tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
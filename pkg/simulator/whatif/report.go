@@ -0,0 +1,118 @@
+package whatif
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+type WhatIfReview struct {
+	Steps      []StepReview `json:"steps"`
+	StopReason string       `json:"stopReason"`
+}
+
+// StepReview is what a step changed in the world or what it measured
+type StepReview struct {
+	Name   string `json:"name,omitempty"`
+	Action string `json:"action"`
+	Error  string `json:"error,omitempty"`
+
+	// nodes added, drained or tainted
+	Nodes       []string `json:"nodes,omitempty"`
+	PodsCreated int      `json:"podsCreated,omitempty"`
+	PodsDeleted int      `json:"podsDeleted,omitempty"`
+	PodsEvicted int      `json:"podsEvicted,omitempty"`
+	// pods evicted without a controller to create them again
+	PodsLost int `json:"podsLost,omitempty"`
+
+	Estimates         []EstimateResult `json:"estimates,omitempty"`
+	Unschedulable     *int             `json:"unschedulable,omitempty"`
+	UnschedulablePods []string         `json:"unschedulablePods,omitempty"`
+}
+
+type EstimateResult struct {
+	Template   string `json:"template"`
+	Replicas   int32  `json:"replicas"`
+	StopReason string `json:"stopReason,omitempty"`
+}
+
+func (r *WhatIfReview) Print(verbose bool, format string) error {
+	switch format {
+	case "json":
+		return utils.PrintJson(r)
+	case "yaml":
+		return utils.PrintYaml(r)
+	case "":
+		whatIfPrettyPrint(r, verbose)
+		return nil
+	default:
+		return fmt.Errorf("output format %q not recognized", format)
+	}
+}
+
+func whatIfPrettyPrint(r *WhatIfReview, verbose bool) {
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"#", "step", "action", "result"})
+	for i, step := range r.Steps {
+		t.AppendRow(table.Row{i + 1, step.Name, step.Action, step.result()})
+	}
+	fmt.Println(t.Render())
+
+	if verbose {
+		for i, step := range r.Steps {
+			if len(step.Nodes) == 0 && len(step.UnschedulablePods) == 0 && len(step.Estimates) == 0 {
+				continue
+			}
+			fmt.Printf("\nStep %d: %s\n", i+1, step.Action)
+			if len(step.Nodes) > 0 {
+				fmt.Printf("- nodes: %s\n", strings.Join(step.Nodes, ", "))
+			}
+			for _, estimate := range step.Estimates {
+				fmt.Printf("- %s: %d replicas, %s\n", estimate.Template, estimate.Replicas, estimate.StopReason)
+			}
+			for _, pod := range step.UnschedulablePods {
+				fmt.Printf("- unschedulable: %s\n", pod)
+			}
+		}
+	}
+
+	fmt.Printf("\nTermination reason: %s\n", r.StopReason)
+}
+
+func (s *StepReview) result() string {
+	if len(s.Error) > 0 {
+		return "failed: " + s.Error
+	}
+
+	var results []string
+	if len(s.Nodes) > 0 {
+		results = append(results, fmt.Sprintf("%d nodes", len(s.Nodes)))
+	}
+	for _, counter := range []struct {
+		name  string
+		count int
+	}{
+		{"pods created", s.PodsCreated},
+		{"pods deleted", s.PodsDeleted},
+		{"pods evicted", s.PodsEvicted},
+		{"pods lost", s.PodsLost},
+	} {
+		if counter.count > 0 {
+			results = append(results, fmt.Sprintf("%d %s", counter.count, counter.name))
+		}
+	}
+	for _, estimate := range s.Estimates {
+		results = append(results, fmt.Sprintf("%s: %d replicas", estimate.Template, estimate.Replicas))
+	}
+	if s.Unschedulable != nil {
+		results = append(results, fmt.Sprintf("%d unschedulable pods", *s.Unschedulable))
+	}
+
+	if len(results) == 0 {
+		return "no changes"
+	}
+	return strings.Join(results, ", ")
+}
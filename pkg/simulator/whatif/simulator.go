@@ -0,0 +1,254 @@
+package whatif
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	ceoptions "github.com/k-cloud-labs/kluster-capacity/app/cmds/capacityestimation/options"
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/whatif/options"
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/admission"
+	pkgframework "github.com/k-cloud-labs/kluster-capacity/pkg/framework"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/capacityestimation"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+const (
+	AllApplied = "AllApplied: %d step(s) have been applied."
+	FailedStep = "FailedStep: step %d failed: %v"
+)
+
+// simulator applies the steps to the running simulation one by one, each step changing the world waits for
+// the scheduler to settle, i.e. to have nothing to do, before the next step
+type simulator struct {
+	pkg.Framework
+
+	fakeClient      clientset.Interface
+	schedulerConfig string
+	steps           []options.Step
+	// pod templates of the estimate steps by the index of the step
+	templates map[int][]*corev1.Pod
+
+	// only accessed by the quiesced hook, so no lock is needed
+	next    int
+	reviews []StepReview
+	// suffix of the names of the nodes and pods created, so that they never conflict
+	created int
+	err     error
+}
+
+// NewWhatIfSimulatorExecutor create a whatif simulator which is completely independent of apiserver so no need
+// for kubeconfig nor for apiserver url
+func NewWhatIfSimulatorExecutor(conf *options.WhatIfConfig) (pkg.Simulator, error) {
+	kubeSchedulerConfig, err := utils.BuildKubeSchedulerCompletedConfig(conf.Options.SchedulerConfig, conf.Options.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := admission.New(&conf.Options.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &simulator{
+		fakeClient:      kubeSchedulerConfig.Client,
+		schedulerConfig: conf.Options.SchedulerConfig,
+		steps:           conf.Steps,
+		templates:       make(map[int][]*corev1.Pod),
+	}
+
+	// the templates are read before the simulation starts, so that a missing one doesn't fail it halfway
+	for i, step := range conf.Steps {
+		if step.Estimate == nil {
+			continue
+		}
+		for _, template := range step.Estimate.PodsFromTemplate {
+			pod, err := utils.GetPodFromTemplate(template)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get pod from template %s: %v", template, err)
+			}
+			s.templates[i] = append(s.templates[i], pod)
+		}
+	}
+
+	// the world is always initialized from the objects passed to Initialize, so no rest config is needed
+	framework, err := pkgframework.NewKubeSchedulerFramework(kubeSchedulerConfig, nil,
+		pkgframework.WithAdmission(chain),
+		pkgframework.WithExcludeNodes(conf.Options.ExcludeNodes),
+		pkgframework.WithQuiescedHook(s.onQuiesced))
+	if err != nil {
+		return nil, err
+	}
+
+	s.Framework = framework
+
+	return s, nil
+}
+
+func (s *simulator) Initialize(objs ...runtime.Object) error {
+	err := s.InitTheWorld(objs...)
+	if err != nil {
+		return err
+	}
+
+	// the pods pending in the world are scheduled by the simulation as well
+	podList, err := s.fakeClient.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{ResourceVersion: "0"})
+	if err != nil {
+		return err
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if len(pod.Spec.NodeName) > 0 {
+			continue
+		}
+		if err := s.fakeClient.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.Name, metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+		if err := s.CreatePod(utils.InitPod(pod)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Run returns an error if any step failed
+func (s *simulator) Run() error {
+	err := s.Framework.Run()
+	if err != nil {
+		return err
+	}
+
+	return s.err
+}
+
+func (s *simulator) Report() pkg.Printer {
+	return &WhatIfReview{
+		Steps:      s.reviews,
+		StopReason: s.Status().StopReason,
+	}
+}
+
+// onQuiesced measures the world and applies the next step changing it, the simulation stops when all the
+// steps are applied
+func (s *simulator) onQuiesced() error {
+	for s.next < len(s.steps) {
+		index := s.next
+		step := &s.steps[index]
+		s.next++
+
+		klog.V(2).InfoS("Apply step", "step", index+1, "action", step.String())
+		review := StepReview{
+			Name:   step.Name,
+			Action: step.String(),
+		}
+		err := s.apply(index, step, &review)
+		if err != nil {
+			review.Error = err.Error()
+			s.reviews = append(s.reviews, review)
+			s.err = fmt.Errorf("step %d (%s) failed: %v", index+1, step.String(), err)
+			return s.Stop(fmt.Sprintf(FailedStep, index+1, err))
+		}
+		s.reviews = append(s.reviews, review)
+
+		// wait for the scheduler to settle
+		if step.Mutating() {
+			return nil
+		}
+	}
+
+	return s.Stop(fmt.Sprintf(AllApplied, len(s.steps)))
+}
+
+func (s *simulator) apply(index int, step *options.Step, review *StepReview) error {
+	switch {
+	case step.AddNodes != nil:
+		return s.addNodes(step.AddNodes, review)
+	case step.DrainNodes != nil:
+		return s.drainNodes(step.DrainNodes, review)
+	case step.TaintNodes != nil:
+		return s.taintNodes(step.TaintNodes, review)
+	case step.Scale != nil:
+		return s.scale(step.Scale, review)
+	case len(step.DeleteNamespace) > 0:
+		return s.deleteNamespace(step.DeleteNamespace, review)
+	case step.Estimate != nil:
+		return s.estimate(step.Estimate, s.templates[index], review)
+	default:
+		return s.countUnschedulable(review)
+	}
+}
+
+// estimate runs ce for the templates in a copy of the world as it is now, the world itself isn't changed
+func (s *simulator) estimate(estimate *options.Estimate, templates []*corev1.Pod, review *StepReview) error {
+	objs, err := s.Objects()
+	if err != nil {
+		return err
+	}
+
+	opt := ceoptions.NewCapacityEstimationOptions()
+	opt.SchedulerConfig = s.schedulerConfig
+	opt.MaxLimit = estimate.MaxLimit
+	conf := ceoptions.NewCapacityEstimationConfig(opt)
+	conf.InitObjs = objs
+	for _, template := range templates {
+		conf.Pods = append(conf.Pods, template.DeepCopy())
+	}
+
+	ce, err := capacityestimation.NewCESimulatorExecutor(conf)
+	if err != nil {
+		return err
+	}
+	if err := ce.Initialize(objs...); err != nil {
+		return err
+	}
+	if err := ce.Run(); err != nil {
+		return err
+	}
+
+	reviews, ok := ce.Report().(capacityestimation.CapacityEstimationReviews)
+	if !ok {
+		return fmt.Errorf("unexpected review %T of ce", ce.Report())
+	}
+	for _, r := range reviews {
+		result := EstimateResult{
+			Template: r.Spec.Templates[0].Name,
+			Replicas: r.Status.Replicas,
+		}
+		if r.Status.StopReason != nil {
+			result.StopReason = fmt.Sprintf("%s: %s", r.Status.StopReason.StopType, r.Status.StopReason.StopMessage)
+		}
+		review.Estimates = append(review.Estimates, result)
+	}
+
+	return nil
+}
+
+func (s *simulator) countUnschedulable(review *StepReview) error {
+	podList, err := s.fakeClient.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{ResourceVersion: "0"})
+	if err != nil {
+		return err
+	}
+
+	// the scheduler has settled, so the pods not bound can't be scheduled
+	unschedulable := make([]string, 0)
+	for _, pod := range podList.Items {
+		if len(pod.Spec.NodeName) == 0 {
+			unschedulable = append(unschedulable, pod.Namespace+"/"+pod.Name)
+		}
+	}
+	sort.Strings(unschedulable)
+
+	count := len(unschedulable)
+	review.Unschedulable = &count
+	review.UnschedulablePods = unschedulable
+
+	return nil
+}
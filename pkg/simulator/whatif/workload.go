@@ -0,0 +1,92 @@
+package whatif
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds/whatif/options"
+)
+
+// workload is a Deployment, StatefulSet or ReplicaSet scaled by the steps
+type workload struct {
+	template    *corev1.PodTemplateSpec
+	setReplicas func(replicas int32) error
+}
+
+func (s *simulator) getWorkload(kind, namespace, name string) (*workload, error) {
+	switch kind {
+	case options.KindDeployment:
+		deploy, err := s.fakeClient.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &workload{
+			template: &deploy.Spec.Template,
+			setReplicas: func(replicas int32) error {
+				deploy.Spec.Replicas = &replicas
+				_, err := s.fakeClient.AppsV1().Deployments(namespace).Update(context.TODO(), deploy, metav1.UpdateOptions{})
+				return err
+			},
+		}, nil
+	case options.KindStatefulSet:
+		sts, err := s.fakeClient.AppsV1().StatefulSets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &workload{
+			template: &sts.Spec.Template,
+			setReplicas: func(replicas int32) error {
+				sts.Spec.Replicas = &replicas
+				_, err := s.fakeClient.AppsV1().StatefulSets(namespace).Update(context.TODO(), sts, metav1.UpdateOptions{})
+				return err
+			},
+		}, nil
+	default:
+		rs, err := s.fakeClient.AppsV1().ReplicaSets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &workload{
+			template: &rs.Spec.Template,
+			setReplicas: func(replicas int32) error {
+				rs.Spec.Replicas = &replicas
+				_, err := s.fakeClient.AppsV1().ReplicaSets(namespace).Update(context.TODO(), rs, metav1.UpdateOptions{})
+				return err
+			},
+		}, nil
+	}
+}
+
+// podsOfWorkload returns the pods controlled by the workload, the pods of a Deployment are controlled by
+// its ReplicaSets or by itself if they are created by the steps
+func (s *simulator) podsOfWorkload(kind, namespace, name string) ([]*corev1.Pod, error) {
+	owners := sets.New[string](kind + "/" + name)
+	if kind == options.KindDeployment {
+		rsList, err := s.fakeClient.AppsV1().ReplicaSets(namespace).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for i := range rsList.Items {
+			if ref := metav1.GetControllerOf(&rsList.Items[i]); ref != nil && ref.Kind == kind && ref.Name == name {
+				owners.Insert(options.KindReplicaSet + "/" + rsList.Items[i].Name)
+			}
+		}
+	}
+
+	podList, err := s.fakeClient.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var pods []*corev1.Pod
+	for i := range podList.Items {
+		if ref := metav1.GetControllerOf(&podList.Items[i]); ref != nil && owners.Has(ref.Kind+"/"+ref.Name) {
+			pods = append(pods, podList.Items[i].DeepCopy())
+		}
+	}
+
+	return pods, nil
+}
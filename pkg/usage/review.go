@@ -0,0 +1,173 @@
+package usage
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/k-cloud-labs/kluster-capacity/app/cmds"
+	"github.com/k-cloud-labs/kluster-capacity/pkg"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/capacityestimation"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/clustercompression"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/simulator/schedulersimulation"
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+// UsageReview compares the result of a simulation of the world as it's requested with the one of the world
+// right-sized to the usage
+type UsageReview struct {
+	Summary    *Summary    `json:"summary"`
+	Requested  pkg.Printer `json:"requested"`
+	RightSized pkg.Printer `json:"rightSized"`
+	Gains      []Gain      `json:"gains,omitempty"`
+}
+
+// Gain is a measure of the capacity compared between the two simulations
+type Gain struct {
+	Measure    string `json:"measure"`
+	Requested  int    `json:"requested"`
+	RightSized int    `json:"rightSized"`
+	// positive if right-sizing gains capacity
+	Gained int `json:"gained"`
+}
+
+// Compare runs the simulation on the objects as they are and on the objects right-sized to the usage file of
+// the options
+func Compare(opt *cmds.Options, objs []runtime.Object, run func(objs []runtime.Object) (pkg.Printer, error)) (*UsageReview, error) {
+	usage, err := Load(opt.UsageFile)
+	if err != nil {
+		return nil, err
+	}
+	rightSizedObjs, summary := RightSize(objs, usage, opt.UsagePercentile, opt.UsageMargin)
+
+	requested, err := run(objs)
+	if err != nil {
+		return nil, err
+	}
+	rightSized, err := run(rightSizedObjs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UsageReview{
+		Summary:    summary,
+		Requested:  requested,
+		RightSized: rightSized,
+		Gains:      gains(requested, rightSized),
+	}, nil
+}
+
+// Validate checks the usage options, they are ignored if no usage file is specified
+func Validate(opt *cmds.Options) error {
+	if len(opt.UsageFile) == 0 {
+		return nil
+	}
+
+	if opt.UsagePercentile < 1 || opt.UsagePercentile > 100 {
+		return errors.New("usage percentile must be between 1 and 100")
+	}
+
+	if opt.UsageMargin < 0 {
+		return errors.New("usage margin must not be negative")
+	}
+
+	return nil
+}
+
+// Requested returns the result of the simulation of the world as it's requested if the review is a UsageReview,
+// otherwise the review itself
+func Requested(review pkg.Printer) pkg.Printer {
+	if r, ok := review.(*UsageReview); ok {
+		return r.Requested
+	}
+	return review
+}
+
+// gains returns the measures of the capacity of the reviews, more replicas or nodes to scale down are gained
+// while fewer nodes used or pods unschedulable are gained
+func gains(requested, rightSized pkg.Printer) []Gain {
+	more := func(measure string, r, rs int) Gain {
+		return Gain{Measure: measure, Requested: r, RightSized: rs, Gained: rs - r}
+	}
+	fewer := func(measure string, r, rs int) Gain {
+		return Gain{Measure: measure, Requested: r, RightSized: rs, Gained: r - rs}
+	}
+
+	switch r := requested.(type) {
+	case capacityestimation.CapacityEstimationReviews:
+		rs, ok := rightSized.(capacityestimation.CapacityEstimationReviews)
+		if !ok || len(rs) != len(r) {
+			return nil
+		}
+		var result []Gain
+		for i := range r {
+			result = append(result, more(fmt.Sprintf("replicas of %s", r[i].Spec.Templates[0].Name), int(r[i].Status.Replicas), int(rs[i].Status.Replicas)))
+		}
+		return result
+	case *clustercompression.ClusterCompressionReview:
+		rs, ok := rightSized.(*clustercompression.ClusterCompressionReview)
+		if !ok {
+			return nil
+		}
+		return []Gain{more("nodes to scale down", len(r.Status.ScaleDownNodeNames), len(rs.Status.ScaleDownNodeNames))}
+	case *schedulersimulation.SchedulerSimulationReview:
+		rs, ok := rightSized.(*schedulersimulation.SchedulerSimulationReview)
+		if !ok {
+			return nil
+		}
+		return []Gain{
+			fewer("nodes used", r.Metrics.NodesUsed, rs.Metrics.NodesUsed),
+			fewer("unschedulable pods", len(r.UnschedulablePods), len(rs.UnschedulablePods)),
+		}
+	default:
+		return nil
+	}
+}
+
+func (r *UsageReview) Print(verbose bool, format string) error {
+	switch format {
+	case "json":
+		return utils.PrintJson(r)
+	case "yaml":
+		return utils.PrintYaml(r)
+	case "":
+		return usagePrettyPrint(r, verbose)
+	default:
+		return fmt.Errorf("output format %q not recognized", format)
+	}
+}
+
+func usagePrettyPrint(r *UsageReview, verbose bool) error {
+	fmt.Println("=== As requested ===")
+	if err := r.Requested.Print(verbose, ""); err != nil {
+		return err
+	}
+	fmt.Printf("\n=== Right-sized to p%d usage + %.0f%% ===\n", r.Summary.Percentile, r.Summary.Margin*100)
+	if err := r.RightSized.Print(verbose, ""); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%d of %d pod(s) right-sized, %d pod(s) without usage left as requested.\n",
+		r.Summary.PodsRightSized, r.Summary.Pods, r.Summary.PodsWithoutUsage)
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"resource", "requested", "right-sized"})
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		requested, rightSized := r.Summary.Requested[name], r.Summary.RightSized[name]
+		t.AppendRow(table.Row{name, requested.String(), rightSized.String()})
+	}
+	fmt.Println(t.Render())
+
+	if len(r.Gains) > 0 {
+		t = table.NewWriter()
+		t.AppendHeader(table.Row{"measure", "requested", "right-sized", "gained"})
+		for _, gain := range r.Gains {
+			t.AppendRow(table.Row{gain.Measure, gain.Requested, gain.RightSized, fmt.Sprintf("%+d", gain.Gained)})
+		}
+		fmt.Println(t.Render())
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package usage
+
+import (
+	"math"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/k-cloud-labs/kluster-capacity/pkg/utils"
+)
+
+const mi = 1024 * 1024
+
+// Summary is how much the requests of the pods in the world are changed by right-sizing
+type Summary struct {
+	Percentile int     `json:"percentile"`
+	Margin     float64 `json:"margin"`
+	// pods bound to nodes
+	Pods             int `json:"pods"`
+	PodsRightSized   int `json:"podsRightSized"`
+	PodsWithoutUsage int `json:"podsWithoutUsage"`
+
+	Requested  corev1.ResourceList `json:"requested"`
+	RightSized corev1.ResourceList `json:"rightSized"`
+}
+
+// RightSize returns a copy of the objects with the requests of the pods bound to nodes rewritten to their usage
+// at the percentile plus the margin, e.g. 0.1 for 10%. The pods without usage are left as they are.
+func RightSize(objs []runtime.Object, usage Usage, percentile int, margin float64) ([]runtime.Object, *Summary) {
+	summary := &Summary{
+		Percentile: percentile,
+		Margin:     margin,
+	}
+	var requestedCPU, requestedMemory, rightSizedCPU, rightSizedMemory int64
+
+	result := make([]runtime.Object, 0, len(objs))
+	for _, obj := range objs {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok || len(pod.Spec.NodeName) == 0 {
+			result = append(result, obj)
+			continue
+		}
+
+		summary.Pods++
+		request := utils.ComputePodResourceRequest(pod)
+		requestedCPU += request.MilliCPU
+		requestedMemory += request.Memory
+
+		pod = pod.DeepCopy()
+		rightSized := false
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			value, ok := usage.Get(pod, name, percentile)
+			if !ok {
+				continue
+			}
+			setRequests(pod, name, value, margin)
+			rightSized = true
+		}
+		if rightSized {
+			summary.PodsRightSized++
+		} else {
+			summary.PodsWithoutUsage++
+		}
+
+		request = utils.ComputePodResourceRequest(pod)
+		rightSizedCPU += request.MilliCPU
+		rightSizedMemory += request.Memory
+		result = append(result, pod)
+	}
+
+	summary.Requested = corev1.ResourceList{
+		corev1.ResourceCPU:    *resource.NewMilliQuantity(requestedCPU, resource.DecimalSI),
+		corev1.ResourceMemory: *resource.NewQuantity(requestedMemory, resource.BinarySI),
+	}
+	summary.RightSized = corev1.ResourceList{
+		corev1.ResourceCPU:    *resource.NewMilliQuantity(rightSizedCPU, resource.DecimalSI),
+		corev1.ResourceMemory: *resource.NewQuantity(rightSizedMemory, resource.BinarySI),
+	}
+
+	return result, summary
+}
+
+// setRequests splits the usage of the pod plus the margin among its containers in proportion to their requests,
+// or evenly if none of them requests the resource. The init containers are left as they are. The requests are
+// rounded up to millicores for cpu and Mi for memory.
+func setRequests(pod *corev1.Pod, name corev1.ResourceName, value resource.Quantity, margin float64) {
+	// the value of the quantity in the unit of the rounding
+	scaled := func(q resource.Quantity) float64 {
+		if name == corev1.ResourceCPU {
+			return float64(q.MilliValue())
+		}
+		return float64(q.Value()) / mi
+	}
+	newQuantity := func(v float64) resource.Quantity {
+		// the error of the float multiplication isn't rounded up
+		rounded := int64(math.Ceil(v - 1e-6))
+		if name == corev1.ResourceCPU {
+			return *resource.NewMilliQuantity(rounded, resource.DecimalSI)
+		}
+		return *resource.NewQuantity(rounded*mi, resource.BinarySI)
+	}
+
+	target := scaled(value) * (1 + margin)
+	var total float64
+	for _, container := range pod.Spec.Containers {
+		if q, ok := container.Resources.Requests[name]; ok {
+			total += scaled(q)
+		}
+	}
+
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		share := 1 / float64(len(pod.Spec.Containers))
+		if total > 0 {
+			share = scaled(container.Resources.Requests[name]) / total
+		}
+		if container.Resources.Requests == nil {
+			container.Resources.Requests = corev1.ResourceList{}
+		}
+		container.Resources.Requests[name] = newQuantity(target * share)
+	}
+}
@@ -0,0 +1,145 @@
+package usage
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// PodUsage is the usage of a pod recorded by percentiles, e.g. exported from Prometheus or metrics-server
+type PodUsage struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	// key is the percentile, e.g. p95
+	CPU    map[string]resource.Quantity `json:"cpu,omitempty"`
+	Memory map[string]resource.Quantity `json:"memory,omitempty"`
+}
+
+// Usage is the usage of the pods by namespace/name
+type Usage map[string]*PodUsage
+
+// Percentile returns the key of the percentile in the usage file, e.g. p95
+func Percentile(percentile int) string {
+	return fmt.Sprintf("p%d", percentile)
+}
+
+// Get returns the usage of the resource of the pod at the percentile, false if it's not recorded
+func (u Usage) Get(pod *corev1.Pod, name corev1.ResourceName, percentile int) (resource.Quantity, bool) {
+	podUsage, ok := u[pod.Namespace+"/"+pod.Name]
+	if !ok {
+		return resource.Quantity{}, false
+	}
+
+	var values map[string]resource.Quantity
+	switch name {
+	case corev1.ResourceCPU:
+		values = podUsage.CPU
+	case corev1.ResourceMemory:
+		values = podUsage.Memory
+	}
+	value, ok := values[Percentile(percentile)]
+	return value, ok
+}
+
+// Load reads the usage file, a CSV file if its extension is .csv, otherwise a JSON file.
+//
+// The JSON file is a list of PodUsage. The first row of the CSV file is the header, the namespace and pod
+// columns followed by the <resource>_<percentile> ones, e.g.
+//
+//	namespace,pod,cpu_p95,memory_p95
+//	team,web-abc-0,250m,300Mi
+//
+// The values are quantities, plain numbers are cores for cpu and bytes for memory.
+func Load(path string) (Usage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var pods []*PodUsage
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		pods, err = readCSV(f)
+	} else {
+		err = json.NewDecoder(f).Decode(&pods)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read usage file %s: %v", path, err)
+	}
+
+	usage := make(Usage, len(pods))
+	for _, pod := range pods {
+		if len(pod.Namespace) == 0 || len(pod.Pod) == 0 {
+			return nil, fmt.Errorf("namespace or pod of the usage in %s is missing", path)
+		}
+		usage[pod.Namespace+"/"+pod.Pod] = pod
+	}
+
+	return usage, nil
+}
+
+func readCSV(r io.Reader) ([]*PodUsage, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	if len(header) < 2 || header[0] != "namespace" || header[1] != "pod" {
+		return nil, errors.New("the first columns of the header must be namespace and pod")
+	}
+	for _, column := range header[2:] {
+		name, _, ok := strings.Cut(column, "_")
+		if !ok || (name != string(corev1.ResourceCPU) && name != string(corev1.ResourceMemory)) {
+			return nil, fmt.Errorf("column %q is not cpu_<percentile> or memory_<percentile>", column)
+		}
+	}
+
+	var pods []*PodUsage
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		pod := &PodUsage{
+			Namespace: record[0],
+			Pod:       record[1],
+			CPU:       make(map[string]resource.Quantity),
+			Memory:    make(map[string]resource.Quantity),
+		}
+		for i, column := range header[2:] {
+			value := record[i+2]
+			// the percentiles not recorded for the pod
+			if len(value) == 0 {
+				continue
+			}
+			quantity, err := resource.ParseQuantity(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s of pod %s/%s: %v", column, pod.Namespace, pod.Pod, err)
+			}
+
+			name, percentile, _ := strings.Cut(column, "_")
+			if name == string(corev1.ResourceCPU) {
+				pod.CPU[percentile] = quantity
+			} else {
+				pod.Memory[percentile] = quantity
+			}
+		}
+		pods = append(pods, pod)
+	}
+
+	return pods, nil
+}
@@ -0,0 +1,282 @@
+package utils
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NodeUtilization is the resources requested by the pods bound to a node at the end of a simulation
+type NodeUtilization struct {
+	Name                string `json:"name"`
+	Zone                string `json:"zone,omitempty"`
+	Pods                int    `json:"pods"`
+	MilliCPURequested   int64  `json:"milliCPURequested"`
+	MilliCPUAllocatable int64  `json:"milliCPUAllocatable"`
+	MemoryRequested     int64  `json:"memoryRequested"`
+	MemoryAllocatable   int64  `json:"memoryAllocatable"`
+}
+
+// zoneUtilization is the sum of the utilizations of the nodes in a zone
+type zoneUtilization struct {
+	NodeUtilization
+	Nodes int
+}
+
+func (u *NodeUtilization) CPURatio() float64 {
+	return ratio(u.MilliCPURequested, u.MilliCPUAllocatable)
+}
+
+func (u *NodeUtilization) MemoryRatio() float64 {
+	return ratio(u.MemoryRequested, u.MemoryAllocatable)
+}
+
+func ratio(requested, allocatable int64) float64 {
+	if allocatable == 0 {
+		return 0
+	}
+	return float64(requested) / float64(allocatable)
+}
+
+// GetNodeZone returns the zone of the node from the well-known labels, empty if it isn't labeled
+func GetNodeZone(node *corev1.Node) string {
+	if zone, ok := node.Labels[corev1.LabelTopologyZone]; ok {
+		return zone
+	}
+	return node.Labels[corev1.LabelFailureDomainBetaZone]
+}
+
+// GetNodeUtilizations returns the utilizations of the nodes sorted by name
+func GetNodeUtilizations(nodes map[string]corev1.Node, pods []corev1.Pod) []NodeUtilization {
+	utilizations := make(map[string]*NodeUtilization, len(nodes))
+	for name := range nodes {
+		node := nodes[name]
+		utilizations[name] = &NodeUtilization{
+			Name:                name,
+			Zone:                GetNodeZone(&node),
+			MilliCPUAllocatable: node.Status.Allocatable.Cpu().MilliValue(),
+			MemoryAllocatable:   node.Status.Allocatable.Memory().Value(),
+		}
+	}
+
+	for i := range pods {
+		u, ok := utilizations[pods[i].Spec.NodeName]
+		if !ok {
+			continue
+		}
+		request := ComputePodResourceRequest(&pods[i])
+		u.Pods++
+		u.MilliCPURequested += request.MilliCPU
+		u.MemoryRequested += request.Memory
+	}
+
+	result := make([]NodeUtilization, 0, len(utilizations))
+	for _, u := range utilizations {
+		result = append(result, *u)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// getZoneUtilizations sums the utilizations of the nodes by zone, the nodes without zone are summed as well
+func getZoneUtilizations(nodes []NodeUtilization) []zoneUtilization {
+	zones := make(map[string]*zoneUtilization)
+	for _, node := range nodes {
+		zone, ok := zones[node.Zone]
+		if !ok {
+			zone = &zoneUtilization{NodeUtilization: NodeUtilization{Name: node.Zone, Zone: node.Zone}}
+			zones[node.Zone] = zone
+		}
+		zone.Nodes++
+		zone.Pods += node.Pods
+		zone.MilliCPURequested += node.MilliCPURequested
+		zone.MilliCPUAllocatable += node.MilliCPUAllocatable
+		zone.MemoryRequested += node.MemoryRequested
+		zone.MemoryAllocatable += node.MemoryAllocatable
+	}
+
+	result := make([]zoneUtilization, 0, len(zones))
+	for _, zone := range zones {
+		result = append(result, *zone)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Zone < result[j].Zone
+	})
+
+	return result
+}
+
+// HTMLPage is a self-contained HTML report, the tables are rendered by go-pretty and the utilization bars by CSS
+type HTMLPage struct {
+	Title    string
+	Sections []HTMLSection
+}
+
+// HTMLSection is a titled part of the page, every part of it is optional and rendered in the order of the fields
+type HTMLSection struct {
+	Title      string
+	Paragraphs []string
+	// ordered list, e.g. the removal order of the nodes
+	List  []string
+	Table table.Writer
+	// utilization bars of the nodes
+	Bars []NodeUtilization
+}
+
+// ZoneTable returns the table of the utilizations of the zones, the zones are only shown if any node has one
+func ZoneTable(nodes []NodeUtilization) table.Writer {
+	zones := getZoneUtilizations(nodes)
+	if len(zones) == 1 && len(zones[0].Zone) == 0 {
+		return nil
+	}
+
+	t := table.NewWriter()
+	t.AppendHeader(table.Row{"zone", "nodes", "pods", "cpu requested", "memory requested"})
+	for i := range zones {
+		zone := &zones[i]
+		name := zone.Zone
+		if len(name) == 0 {
+			name = "<none>"
+		}
+		t.AppendRow(table.Row{name, zone.Nodes, zone.Pods, fmt.Sprintf("%.1f%%", zone.CPURatio()*100), fmt.Sprintf("%.1f%%", zone.MemoryRatio()*100)})
+	}
+
+	return t
+}
+
+type htmlBar struct {
+	Name   string
+	Detail string
+	CPU    string
+	Memory string
+	// width of the bars, capped at 100%
+	CPUWidth    string
+	MemoryWidth string
+	// class of the color of the bars
+	CPULevel    string
+	MemoryLevel string
+}
+
+type htmlSection struct {
+	Title      string
+	Paragraphs []string
+	List       []string
+	Table      template.HTML
+	Bars       []htmlBar
+}
+
+// PrintHTML prints the page to stdout
+func PrintHTML(page *HTMLPage) error {
+	sections := make([]htmlSection, 0, len(page.Sections))
+	for _, section := range page.Sections {
+		s := htmlSection{
+			Title:      section.Title,
+			Paragraphs: section.Paragraphs,
+			List:       section.List,
+		}
+		if section.Table != nil {
+			// the text of the cells is escaped by go-pretty
+			s.Table = template.HTML(section.Table.RenderHTML())
+		}
+		for i := range section.Bars {
+			u := &section.Bars[i]
+			detail := fmt.Sprintf("%d pod(s)", u.Pods)
+			if len(u.Zone) > 0 && u.Zone != u.Name {
+				detail = fmt.Sprintf("%s, %s", u.Zone, detail)
+			}
+			s.Bars = append(s.Bars, htmlBar{
+				Name:        u.Name,
+				Detail:      detail,
+				CPU:         fmt.Sprintf("%.1f", u.CPURatio()*100),
+				Memory:      fmt.Sprintf("%.1f", u.MemoryRatio()*100),
+				CPUWidth:    fmt.Sprintf("%.1f", math.Min(u.CPURatio(), 1)*100),
+				MemoryWidth: fmt.Sprintf("%.1f", math.Min(u.MemoryRatio(), 1)*100),
+				CPULevel:    level(u.CPURatio()),
+				MemoryLevel: level(u.MemoryRatio()),
+			})
+		}
+		sections = append(sections, s)
+	}
+
+	return htmlTemplate.Execute(os.Stdout, struct {
+		Title    string
+		Sections []htmlSection
+	}{
+		Title:    page.Title,
+		Sections: sections,
+	})
+}
+
+func level(ratio float64) string {
+	switch {
+	case ratio >= 0.9:
+		return "high"
+	case ratio >= 0.7:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 1100px; color: #24292f; }
+h1 { border-bottom: 2px solid #d0d7de; padding-bottom: .3em; }
+h2 { margin-top: 1.5em; border-bottom: 1px solid #d0d7de; padding-bottom: .2em; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #d0d7de; padding: 4px 10px; text-align: left; }
+th { background: #f6f8fa; }
+.bars { display: grid; grid-template-columns: max-content 1fr 1fr; gap: 6px 16px; align-items: center; }
+.name { font-family: monospace; }
+.detail { color: #57606a; font-size: 0.85em; }
+.bar { position: relative; background: #eaeef2; height: 18px; border-radius: 3px; }
+.fill { height: 100%; border-radius: 3px; }
+.low { background: #2da44e; }
+.medium { background: #d4a72c; }
+.high { background: #cf222e; }
+.value { position: absolute; left: 6px; top: 0; font-size: 0.8em; line-height: 18px; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{- range .Sections}}
+<h2>{{.Title}}</h2>
+{{- range .Paragraphs}}
+<p>{{.}}</p>
+{{- end}}
+{{- if .List}}
+<ol>
+{{- range .List}}
+<li>{{.}}</li>
+{{- end}}
+</ol>
+{{- end}}
+{{- if .Table}}
+{{.Table}}
+{{- end}}
+{{- if .Bars}}
+<div class="bars">
+<b>node</b><b>cpu requested</b><b>memory requested</b>
+{{- range .Bars}}
+<div><span class="name">{{.Name}}</span> <span class="detail">{{.Detail}}</span></div>
+<div class="bar"><div class="fill {{.CPULevel}}" style="width: {{.CPUWidth}}%"></div><span class="value">{{.CPU}}%</span></div>
+<div class="bar"><div class="fill {{.MemoryLevel}}" style="width: {{.MemoryWidth}}%"></div><span class="value">{{.Memory}}%</span></div>
+{{- end}}
+</div>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	api "k8s.io/kubernetes/pkg/apis/core"
+	apiv1 "k8s.io/kubernetes/pkg/apis/core/v1"
+	"k8s.io/kubernetes/plugin/pkg/admission/limitranger"
+)
+
+// ApplyLimitRanges sets the default resource requirements of the pod according to the LimitRanges of its namespace,
+// which is the same as what LimitRanger admission plugin does for a pod.
+func ApplyLimitRanges(pod *corev1.Pod, limitRanges []corev1.LimitRange) error {
+	if len(limitRanges) == 0 {
+		return nil
+	}
+
+	internalPod := &api.Pod{}
+	if err := apiv1.Convert_v1_Pod_To_core_Pod(pod, internalPod, nil); err != nil {
+		return err
+	}
+
+	for i := range limitRanges {
+		if err := limitranger.PodMutateLimitFunc(&limitRanges[i], internalPod); err != nil {
+			return err
+		}
+	}
+
+	return apiv1.Convert_core_Pod_To_v1_Pod(internalPod, pod, nil)
+}
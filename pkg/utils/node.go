@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+// CordonNode prevents new pods from being scheduled to the node with the unschedulable taint
+func CordonNode(client clientset.Interface, nodeName string) error {
+	node, err := client.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	copy := node.DeepCopy()
+
+	taints := []corev1.Taint{}
+	unScheduleTaint := corev1.Taint{
+		Key:    corev1.TaintNodeUnschedulable,
+		Effect: corev1.TaintEffectNoSchedule,
+	}
+	taints = append(taints, unScheduleTaint)
+
+	for i := range copy.Spec.Taints {
+		if copy.Spec.Taints[i].Key != corev1.TaintNodeUnschedulable {
+			taints = append(taints, copy.Spec.Taints[i])
+		}
+	}
+	copy.Spec.Taints = taints
+
+	_, err = client.CoreV1().Nodes().Update(context.TODO(), copy, metav1.UpdateOptions{})
+	return err
+}
+
+// UncordonNode removes the unschedulable taint added by CordonNode
+func UncordonNode(client clientset.Interface, nodeName string) error {
+	node, err := client.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	copy := node.DeepCopy()
+
+	taints := []corev1.Taint{}
+	for i := range copy.Spec.Taints {
+		if copy.Spec.Taints[i].Key != corev1.TaintNodeUnschedulable {
+			taints = append(taints, copy.Spec.Taints[i])
+		}
+	}
+	copy.Spec.Taints = taints
+
+	_, err = client.CoreV1().Nodes().Update(context.TODO(), copy, metav1.UpdateOptions{})
+	return err
+}
+
+// DeletePodsToReschedule deletes the pods which need to be scheduled somewhere else when their node is gone,
+// i.e. all the pods except the DaemonSet and terminating ones, and returns the pods deleted
+func DeletePodsToReschedule(client clientset.Interface, pods []*corev1.Pod) ([]*corev1.Pod, error) {
+	var deleted []*corev1.Pod
+	for i := range pods {
+		if !IsDaemonsetPod(pods[i].OwnerReferences) && pods[i].DeletionTimestamp == nil {
+			deleted = append(deleted, pods[i])
+			err := client.CoreV1().Pods(pods[i].Namespace).Delete(context.TODO(), pods[i].Name, metav1.DeleteOptions{})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return deleted, nil
+}
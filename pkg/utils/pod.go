@@ -1,12 +1,20 @@
 package utils
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 
 	uuid "github.com/satori/go.uuid"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	clientset "k8s.io/client-go/kubernetes"
 	apiv1 "k8s.io/kubernetes/pkg/apis/core/v1"
 
 	"github.com/k-cloud-labs/kluster-capacity/pkg"
@@ -96,3 +104,65 @@ func InitPod(podTemplate *corev1.Pod) *corev1.Pod {
 
 	return pod
 }
+
+// GetPodFromTemplate reads the pod definition from a JSON or YAML file or URL.
+func GetPodFromTemplate(template string) (*corev1.Pod, error) {
+	var (
+		err          error
+		versionedPod = &corev1.Pod{}
+		spec         io.Reader
+	)
+
+	if strings.HasPrefix(template, "http://") || strings.HasPrefix(template, "https://") {
+		response, err := http.Get(template)
+		if err != nil {
+			return nil, err
+		}
+		defer response.Body.Close()
+		if response.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unable to read URL %q, server reported %v, status code=%v", template, response.Status, response.StatusCode)
+		}
+		spec = response.Body
+	} else {
+		filename, _ := filepath.Abs(template)
+		spec, err = os.Open(filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open config file: %v", err)
+		}
+	}
+
+	decoder := yaml.NewYAMLOrJSONDecoder(spec, 4096)
+	err = decoder.Decode(versionedPod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode config file: %v", err)
+	}
+
+	return versionedPod, nil
+}
+
+// GetPodPriority resolves the priority of the pod from its priority class as the Priority admission plugin does
+func GetPodPriority(client clientset.Interface, pod *corev1.Pod) (int32, error) {
+	if pod.Spec.Priority != nil {
+		return *pod.Spec.Priority, nil
+	}
+
+	if len(pod.Spec.PriorityClassName) > 0 {
+		pc, err := client.SchedulingV1().PriorityClasses().Get(context.TODO(), pod.Spec.PriorityClassName, metav1.GetOptions{})
+		if err != nil {
+			return 0, fmt.Errorf("unable to get priority class %s: %v", pod.Spec.PriorityClassName, err)
+		}
+		return pc.Value, nil
+	}
+
+	pcList, err := client.SchedulingV1().PriorityClasses().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return 0, err
+	}
+	for _, pc := range pcList.Items {
+		if pc.GlobalDefault {
+			return pc.Value, nil
+		}
+	}
+
+	return 0, nil
+}
@@ -60,18 +60,35 @@ func BuildRestConfig(config string) (*restclient.Config, error) {
 }
 
 func BuildKubeSchedulerCompletedConfig(config, kubeconfig string) (*schedconfig.CompletedConfig, error) {
-	var kcfg *kubeschedulerconfig.KubeSchedulerConfiguration
-	if len(config) > 0 {
-		cfg, err := loadConfigFromFile(config)
-		if err != nil {
-			return nil, err
-		}
-		if err := validation.ValidateKubeSchedulerConfiguration(cfg); err != nil {
-			return nil, err
-		}
-		kcfg = cfg
+	kcfg, err := LoadKubeSchedulerConfiguration(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildKubeSchedulerCompletedConfigFromConfiguration(kcfg, kubeconfig)
+}
+
+// LoadKubeSchedulerConfiguration loads and validates the scheduler configuration from the file, an empty
+// file means the default configuration.
+func LoadKubeSchedulerConfiguration(config string) (*kubeschedulerconfig.KubeSchedulerConfiguration, error) {
+	if len(config) == 0 {
+		return defaultKubeSchedulerConfiguration()
+	}
+
+	cfg, err := loadConfigFromFile(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateKubeSchedulerConfiguration(cfg); err != nil {
+		return nil, err
 	}
 
+	return cfg, nil
+}
+
+// BuildKubeSchedulerCompletedConfigFromConfiguration is the same as BuildKubeSchedulerCompletedConfig but takes
+// a scheduler configuration in memory, the configuration is modified so a copy should be passed if it's reused.
+func BuildKubeSchedulerCompletedConfigFromConfiguration(kcfg *kubeschedulerconfig.KubeSchedulerConfiguration, kubeconfig string) (*schedconfig.CompletedConfig, error) {
 	if len(kcfg.ClientConnection.Kubeconfig) == 0 && len(kubeconfig) > 0 {
 		kcfg.ClientConnection.Kubeconfig = kubeconfig
 	}
@@ -123,14 +140,11 @@ func ComputePodResourceRequest(pod *corev1.Pod) *framework.Resource {
 
 func buildKubeSchedulerCompletedConfig(kcfg *kubeschedulerconfig.KubeSchedulerConfiguration) (*schedconfig.CompletedConfig, error) {
 	if kcfg == nil {
-		kcfg = &kubeschedulerconfig.KubeSchedulerConfiguration{}
-		versionedCfg := kubeschedulerconfigv1.KubeSchedulerConfiguration{}
-		versionedCfg.DebuggingConfiguration = *configv1alpha1.NewRecommendedDebuggingConfiguration()
-
-		kubeschedulerscheme.Scheme.Default(&versionedCfg)
-		if err := kubeschedulerscheme.Scheme.Convert(&versionedCfg, kcfg, nil); err != nil {
+		cfg, err := defaultKubeSchedulerConfiguration()
+		if err != nil {
 			return nil, err
 		}
+		kcfg = cfg
 	}
 
 	// inject scheduler config
@@ -149,6 +163,11 @@ func buildKubeSchedulerCompletedConfig(kcfg *kubeschedulerconfig.KubeSchedulerCo
 		ComponentConfig: kcfg,
 		Logs:            logs.NewOptions(),
 	}
+	// the client is replaced by a fake one below, so a placeholder master is enough when there is no
+	// kubeconfig, e.g. the world is initialized from a snapshot
+	if len(kcfg.ClientConnection.Kubeconfig) == 0 {
+		opts.Master = "https://127.0.0.1"
+	}
 
 	c := &schedconfig.Config{}
 	// clear out all unnecessary options so no port is bound
@@ -172,6 +191,19 @@ func buildKubeSchedulerCompletedConfig(kcfg *kubeschedulerconfig.KubeSchedulerCo
 	return &cc, nil
 }
 
+func defaultKubeSchedulerConfiguration() (*kubeschedulerconfig.KubeSchedulerConfiguration, error) {
+	kcfg := &kubeschedulerconfig.KubeSchedulerConfiguration{}
+	versionedCfg := kubeschedulerconfigv1.KubeSchedulerConfiguration{}
+	versionedCfg.DebuggingConfiguration = *configv1alpha1.NewRecommendedDebuggingConfiguration()
+
+	kubeschedulerscheme.Scheme.Default(&versionedCfg)
+	if err := kubeschedulerscheme.Scheme.Convert(&versionedCfg, kcfg, nil); err != nil {
+		return nil, err
+	}
+
+	return kcfg, nil
+}
+
 func loadConfigFromFile(file string) (*kubeschedulerconfig.KubeSchedulerConfiguration, error) {
 	data, err := os.ReadFile(file)
 	if err != nil {